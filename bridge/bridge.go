@@ -0,0 +1,237 @@
+package bridge
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultBridge struct {
+	config   configuration
+	logger   logger
+	ctx      context.Context
+	shutdown context.CancelFunc
+}
+
+func newBridge(config configuration) messaging.ListenCloser {
+	ctx, shutdown := context.WithCancel(context.Background())
+	return &defaultBridge{config: config, logger: config.Logger, ctx: ctx, shutdown: shutdown}
+}
+
+// Listen relays deliveries until closed, reconnecting to both the source and target whenever either fails. Any
+// deliveries not yet committed to the target when a failure occurs remain unacknowledged and are redelivered by the
+// source, so each delivery is relayed at least once.
+func (this *defaultBridge) Listen() {
+	for this.ctx.Err() == nil {
+		if err := this.relay(); err != nil && this.ctx.Err() == nil {
+			this.logger.Printf("[WARN] Bridge of stream [%s] interrupted [%s], reconnecting...", this.config.Stream.StreamName, err)
+		}
+
+		this.sleep()
+	}
+}
+func (this *defaultBridge) relay() error {
+	source, err := this.config.Source.Connect(this.ctx)
+	if err != nil {
+		return err
+	}
+	defer closeResource(source)
+
+	reader, err := source.Reader(this.ctx)
+	if err != nil {
+		return err
+	}
+	defer closeResource(reader)
+
+	stream, err := reader.Stream(this.ctx, this.config.Stream)
+	if err != nil {
+		return err
+	}
+	defer closeResource(stream)
+
+	target, err := this.config.Target.Connect(this.ctx)
+	if err != nil {
+		return err
+	}
+	defer closeResource(target)
+
+	writer, err := target.CommitWriter(this.ctx)
+	if err != nil {
+		return err
+	}
+	defer closeResource(writer)
+
+	this.logger.Printf("[INFO] Bridge of stream [%s] established, relaying deliveries...", this.config.Stream.StreamName)
+	return newRelay(stream, writer, this.config).Relay(this.ctx)
+}
+func (this *defaultBridge) sleep() {
+	if this.config.ReconnectDelay <= 0 {
+		return
+	}
+
+	sleeper, cancel := context.WithTimeout(this.ctx, this.config.ReconnectDelay)
+	defer cancel()
+	<-sleeper.Done()
+}
+
+func (this *defaultBridge) Close() error {
+	this.shutdown()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type relay struct {
+	stream messaging.Stream
+	writer messaging.CommitWriter
+	route  func(messaging.Delivery) string
+
+	buffer     chan messaging.Delivery
+	readError  error
+	deliveries []messaging.Delivery
+	dispatches []messaging.Dispatch
+}
+
+func newRelay(stream messaging.Stream, writer messaging.CommitWriter, config configuration) *relay {
+	return &relay{
+		stream:     stream,
+		writer:     writer,
+		route:      config.Route,
+		buffer:     make(chan messaging.Delivery, config.BatchCapacity),
+		deliveries: make([]messaging.Delivery, 0, config.BatchCapacity),
+		dispatches: make([]messaging.Dispatch, 0, config.BatchCapacity),
+	}
+}
+
+func (this *relay) Relay(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var waiter sync.WaitGroup
+	defer waiter.Wait()
+	defer cancel()
+
+	waiter.Add(1)
+	go func() {
+		defer waiter.Done()
+		this.read(ctx)
+	}()
+
+	for this.fill(ctx) {
+		if err := this.forward(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return this.readError
+}
+func (this *relay) read(ctx context.Context) {
+	defer close(this.buffer)
+
+	for {
+		var delivery messaging.Delivery
+		if this.readError = this.stream.Read(ctx, &delivery); this.readError != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case this.buffer <- delivery:
+		}
+	}
+}
+
+// fill waits for the next delivery and then gathers any further deliveries which are immediately available, up to
+// the capacity of the batch.
+func (this *relay) fill(ctx context.Context) bool {
+	this.deliveries = this.deliveries[0:0]
+
+	select {
+	case <-ctx.Done():
+		return false
+	case delivery, open := <-this.buffer:
+		if !open {
+			return false
+		}
+		this.deliveries = append(this.deliveries, delivery)
+	}
+
+	for len(this.deliveries) < cap(this.deliveries) {
+		select {
+		case delivery, open := <-this.buffer:
+			if !open {
+				return true
+			}
+			this.deliveries = append(this.deliveries, delivery)
+		default:
+			return true
+		}
+	}
+
+	return true
+}
+func (this *relay) forward(ctx context.Context) error {
+	this.dispatches = this.dispatches[0:0]
+	for _, delivery := range this.deliveries {
+		if topic := this.route(delivery); len(topic) > 0 {
+			this.dispatches = append(this.dispatches, toDispatch(delivery, topic))
+		}
+	}
+
+	if len(this.dispatches) > 0 {
+		if _, err := this.writer.Write(ctx, this.dispatches...); err != nil {
+			_ = this.writer.Rollback()
+			return err
+		}
+
+		if err := this.writer.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return this.stream.Acknowledge(ctx, this.deliveries...)
+}
+
+// toDispatch carries the metadata of the delivery to the dispatch; the expiration of the original message, if any, is
+// not carried because it is not available on the delivery. The routing key with which the original message was
+// published, if any, is carried by the header with which the RabbitMQ writer determines the routing key.
+func toDispatch(delivery messaging.Delivery, topic string) messaging.Dispatch {
+	var headers map[string]any
+	if len(delivery.Headers) > 0 || len(delivery.RoutingKey) > 0 {
+		headers = make(map[string]any, len(delivery.Headers)+1)
+		for key, value := range delivery.Headers {
+			headers[key] = value
+		}
+	}
+	if _, contains := headers[messaging.HeaderRoutingKey]; !contains && len(delivery.RoutingKey) > 0 {
+		headers[messaging.HeaderRoutingKey] = delivery.RoutingKey
+	}
+
+	return messaging.Dispatch{
+		SourceID:        delivery.SourceID,
+		MessageID:       delivery.MessageID,
+		CorrelationID:   delivery.CorrelationID,
+		Timestamp:       delivery.Timestamp,
+		Durable:         delivery.Durable,
+		Topic:           topic,
+		Partition:       delivery.Partition,
+		Priority:        delivery.Priority,
+		MessageType:     delivery.MessageType,
+		ContentType:     delivery.ContentType,
+		ContentEncoding: delivery.ContentEncoding,
+		Payload:         delivery.Payload,
+		Headers:         headers,
+	}
+}
+
+func closeResource(resource io.Closer) {
+	if resource != nil {
+		_ = resource.Close()
+	}
+}
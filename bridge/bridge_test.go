@@ -0,0 +1,287 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestBridgeFixture(t *testing.T) {
+	gunit.Run(new(BridgeFixture), t)
+}
+
+type BridgeFixture struct {
+	*gunit.Fixture
+
+	options []option
+	bridge  messaging.ListenCloser
+	stream  messaging.StreamConfig
+
+	mutex        sync.Mutex
+	deliveries   chan messaging.Delivery
+	streamConfig messaging.StreamConfig
+	connectCount int
+	connectError error
+	closeCount   int
+
+	written       [][]messaging.Dispatch
+	writeError    error
+	commitCount   int
+	commitError   error
+	rollbackCount int
+
+	acknowledged chan []messaging.Delivery
+}
+
+func (this *BridgeFixture) Setup() {
+	this.stream = messaging.StreamConfig{StreamName: "queue"}
+	this.deliveries = make(chan messaging.Delivery, 16)
+	this.acknowledged = make(chan []messaging.Delivery, 16)
+	this.options = []option{Options.ReconnectDelay(time.Millisecond)}
+}
+func (this *BridgeFixture) listen() (done chan struct{}) {
+	this.bridge = New(this, this.stream, this, this.options...)
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		this.bridge.Listen()
+	}()
+	return done
+}
+func (this *BridgeFixture) awaitAcknowledgement() []messaging.Delivery {
+	select {
+	case deliveries := <-this.acknowledged:
+		return deliveries
+	case <-time.After(time.Millisecond * 500):
+		this.Error("timed out awaiting acknowledgement")
+		return nil
+	}
+}
+
+func (this *BridgeFixture) TestWhenDeliveryReceived_RelayAsDispatchThenCommitThenAcknowledge() {
+	timestamp := time.Now().UTC()
+	delivery := messaging.Delivery{
+		DeliveryID:      7,
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Timestamp:       timestamp,
+		Durable:         true,
+		Topic:           "topic",
+		Partition:       4,
+		Priority:        5,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Payload:         []byte("payload"),
+		Headers:         map[string]any{"key": "value"},
+	}
+	this.deliveries <- delivery
+	done := this.listen()
+
+	acknowledged := this.awaitAcknowledgement()
+	_ = this.bridge.Close()
+	<-done
+
+	this.So(acknowledged, should.Equal, []messaging.Delivery{delivery})
+	this.So(this.streamConfig, should.Equal, this.stream)
+	this.So(this.written, should.Equal, [][]messaging.Dispatch{{{
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Timestamp:       timestamp,
+		Durable:         true,
+		Topic:           "topic",
+		Partition:       4,
+		Priority:        5,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Payload:         []byte("payload"),
+		Headers:         map[string]any{"key": "value"},
+	}}})
+	this.So(this.commitCount, should.Equal, 1)
+}
+func (this *BridgeFixture) TestWhenDeliveryPublishedToExchange_RelayToExchangeWithOriginalRoutingKey() {
+	this.deliveries <- messaging.Delivery{DeliveryID: 1, Topic: "queue", Exchange: "orders", RoutingKey: "orders.created"}
+	this.deliveries <- messaging.Delivery{DeliveryID: 2, Topic: "queue", Exchange: "orders", Headers: map[string]any{"key": "value"}}
+	this.options = append(this.options, Options.BatchCapacity(2))
+	done := this.listen()
+
+	acknowledged := this.awaitAcknowledgement()
+	if len(acknowledged) < 2 {
+		acknowledged = append(acknowledged, this.awaitAcknowledgement()...)
+	}
+	_ = this.bridge.Close()
+	<-done
+
+	var dispatches []messaging.Dispatch
+	for _, batch := range this.written {
+		dispatches = append(dispatches, batch...)
+	}
+	this.So(dispatches, should.Equal, []messaging.Dispatch{
+		{Topic: "orders", Headers: map[string]any{"routing-key": "orders.created"}},
+		{Topic: "orders", Headers: map[string]any{"key": "value"}},
+	})
+}
+func (this *BridgeFixture) TestWhenMultipleDeliveriesAvailable_RelayTogetherInSingleTransaction() {
+	this.deliveries <- messaging.Delivery{DeliveryID: 1, Topic: "a"}
+	this.deliveries <- messaging.Delivery{DeliveryID: 2, Topic: "b"}
+	this.deliveries <- messaging.Delivery{DeliveryID: 3, Topic: "c"}
+	this.options = append(this.options, Options.BatchCapacity(2))
+	done := this.listen()
+
+	first := this.awaitAcknowledgement()
+	second := this.awaitAcknowledgement()
+	_ = this.bridge.Close()
+	<-done
+
+	this.So(len(first)+len(second), should.Equal, 3)
+	this.So(len(first), should.BeLessThanOrEqualTo, 2)
+	this.So(this.commitCount, should.Equal, 2)
+}
+func (this *BridgeFixture) TestWhenRouted_DispatchToRoutedTopicAndDiscardEmptyRoutes() {
+	this.options = append(this.options, Options.Route(func(delivery messaging.Delivery) string {
+		if delivery.MessageType == "ignored" {
+			return ""
+		}
+		return "routed-" + delivery.Topic
+	}), Options.BatchCapacity(1))
+	this.deliveries <- messaging.Delivery{DeliveryID: 1, Topic: "a", MessageType: "ignored"}
+	this.deliveries <- messaging.Delivery{DeliveryID: 2, Topic: "b"}
+	done := this.listen()
+
+	ignored := this.awaitAcknowledgement()
+	_ = this.awaitAcknowledgement()
+	_ = this.bridge.Close()
+	<-done
+
+	this.So(ignored[0].DeliveryID, should.Equal, 1)
+	this.So(len(this.written), should.Equal, 1)
+	this.So(this.written[0][0].Topic, should.Equal, "routed-b")
+}
+func (this *BridgeFixture) TestWhenWriteFails_RollbackWithoutAcknowledgingAndReconnect() {
+	this.writeError = errors.New("")
+	this.deliveries <- messaging.Delivery{DeliveryID: 1, Topic: "a"}
+	done := this.listen()
+
+	time.Sleep(time.Millisecond * 20)
+	_ = this.bridge.Close()
+	<-done
+
+	this.So(this.rollbackCount, should.BeGreaterThan, 0)
+	this.So(this.commitCount, should.Equal, 0)
+	this.So(len(this.acknowledged), should.Equal, 0)
+	this.So(this.connectCount, should.BeGreaterThan, 2)
+}
+func (this *BridgeFixture) TestWhenCommitFails_DoNotAcknowledge() {
+	this.commitError = errors.New("")
+	this.deliveries <- messaging.Delivery{DeliveryID: 1, Topic: "a"}
+	done := this.listen()
+
+	time.Sleep(time.Millisecond * 20)
+	_ = this.bridge.Close()
+	<-done
+
+	this.So(this.commitCount, should.BeGreaterThan, 0)
+	this.So(len(this.acknowledged), should.Equal, 0)
+}
+func (this *BridgeFixture) TestWhenConnectFails_RetryAfterDelay() {
+	this.connectError = errors.New("")
+	done := this.listen()
+
+	time.Sleep(time.Millisecond * 20)
+	_ = this.bridge.Close()
+	<-done
+
+	this.So(this.connectCount, should.BeGreaterThan, 1)
+	this.So(this.closeCount, should.Equal, 0)
+}
+func (this *BridgeFixture) TestWhenClosed_ListenConcludesAndResourcesClosed() {
+	done := this.listen()
+	time.Sleep(time.Millisecond * 5)
+
+	_ = this.bridge.Close()
+	<-done
+
+	this.So(this.closeCount, should.Equal, 5) // source connection, reader, stream, target connection, writer
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *BridgeFixture) Connect(_ context.Context) (messaging.Connection, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.connectCount++
+	if this.connectError != nil {
+		return nil, this.connectError
+	}
+	return this, nil
+}
+func (this *BridgeFixture) Reader(_ context.Context) (messaging.Reader, error) { return this, nil }
+func (this *BridgeFixture) Writer(_ context.Context) (messaging.Writer, error) { panic("nop") }
+func (this *BridgeFixture) CommitWriter(_ context.Context) (messaging.CommitWriter, error) {
+	return this, nil
+}
+func (this *BridgeFixture) Stream(_ context.Context, config messaging.StreamConfig) (messaging.Stream, error) {
+	this.streamConfig = config
+	return this, nil
+}
+
+func (this *BridgeFixture) Read(ctx context.Context, delivery *messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case item, open := <-this.deliveries:
+		if !open {
+			return io.EOF
+		}
+		*delivery = item
+		return nil
+	}
+}
+func (this *BridgeFixture) Acknowledge(_ context.Context, deliveries ...messaging.Delivery) error {
+	this.acknowledged <- append([]messaging.Delivery{}, deliveries...)
+	return nil
+}
+
+func (this *BridgeFixture) Write(_ context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.writeError != nil {
+		return 0, this.writeError
+	}
+	this.written = append(this.written, append([]messaging.Dispatch{}, dispatches...))
+	return len(dispatches), nil
+}
+func (this *BridgeFixture) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.commitCount++
+	return this.commitError
+}
+func (this *BridgeFixture) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.rollbackCount++
+	return nil
+}
+
+func (this *BridgeFixture) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closeCount++
+	return nil
+}
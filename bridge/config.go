@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// New relays each delivery of the stream opened on the source connector to the target connector as a dispatch. The
+// deliveries of each batch are acknowledged to the source only once their dispatches have been committed to the target.
+func New(source messaging.Connector, stream messaging.StreamConfig, target messaging.Connector, options ...option) messaging.ListenCloser {
+	var config configuration
+	Options.apply(options...)(&config)
+	config.Source, config.Stream, config.Target = source, stream, target
+	return newBridge(config)
+}
+
+type configuration struct {
+	Source         messaging.Connector
+	Stream         messaging.StreamConfig
+	Target         messaging.Connector
+	Route          func(messaging.Delivery) string
+	BatchCapacity  int
+	ReconnectDelay time.Duration
+	Logger         logger
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// Route determines the topic to which each delivery is dispatched on the target. Deliveries routed to an empty topic
+// are acknowledged without being relayed. By default, each delivery is dispatched to the exchange to which it was
+// originally published, if known (e.g. from RabbitMQ, whose Topic is the name of the queue read), and otherwise to the
+// topic on which it was received.
+func (singleton) Route(value func(messaging.Delivery) string) option {
+	return func(this *configuration) { this.Route = value }
+}
+
+// Topic dispatches every delivery to the topic provided.
+func (singleton) Topic(value string) option {
+	return Options.Route(func(messaging.Delivery) string { return value })
+}
+
+// BatchCapacity is the maximum number of deliveries relayed within a single transaction on the target. Smaller batches
+// are relayed whenever no further deliveries are immediately available.
+func (singleton) BatchCapacity(value int) option {
+	return func(this *configuration) { this.BatchCapacity = value }
+}
+
+// ReconnectDelay is the duration to wait before reconnecting to the source and target after either has failed.
+func (singleton) ReconnectDelay(value time.Duration) option {
+	return func(this *configuration) { this.ReconnectDelay = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+
+		if this.BatchCapacity <= 0 {
+			this.BatchCapacity = 1
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.Route(defaultRoute),
+		Options.BatchCapacity(defaultBatchCapacity),
+		Options.ReconnectDelay(time.Second),
+		Options.Logger(defaultLogger),
+	}, options...)
+}
+
+func defaultRoute(delivery messaging.Delivery) string {
+	if len(delivery.Exchange) > 0 {
+		return delivery.Exchange
+	}
+
+	return delivery.Topic
+}
+
+const defaultBatchCapacity = 64
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
@@ -50,6 +50,12 @@ type (
 		Durable         bool
 		Topic           string
 		Partition       uint64
+		Sequence        uint64 // the position within the stream, if supported, from which a later stream may resume
+		Redelivered     bool   // whether the message may have been delivered (but not acknowledged) previously
+		Attempt         uint64 // the delivery attempt, starting at 1, if supported; a lower bound unless counted upstream
+		Exchange        string // for RabbitMQ, the exchange to which the message was published
+		RoutingKey      string // for RabbitMQ, the routing key with which the message was published
+		Priority        uint8  // the priority with which the message was written, if supported
 		MessageType     string
 		ContentType     string
 		ContentEncoding string
@@ -66,6 +72,11 @@ type (
 		// On RabbitMQ, this will re-create queues and exchanges and then bind the associated queue to those exchanges.
 		EstablishTopology bool
 
+		// When the topology isn't established, asserts that it exists without declaring or otherwise modifying it, e.g.
+		// when the credentials used lack the permission to configure it. On RabbitMQ, this passively declares the queue
+		// and the exchanges of the Topics and AvailableTopics, failing if any of them does not exist.
+		VerifyTopology bool
+
 		// Indicates whether the stream is the only one that will be opened with the broker.
 		ExclusiveStream bool
 
@@ -86,6 +97,11 @@ type (
 		// per-dispatch and per-delivery latencies when writing and/or reading messages.
 		StreamReplication bool
 
+		// For RabbitMQ, indicates whether to use a stream queue, a replicated and append-only log from which messages are
+		// not removed when acknowledged and which may be read (and re-read) from any position as specified by Sequence or
+		// StreamOffset. The position of each delivery read from a stream queue is its Sequence.
+		StreamQueue bool
+
 		// For Kafka, the name of the consumer group. When this value is specified, other values such as Topics must now be
 		// specified while other values such as Partition and Sequence are ignored.
 		GroupName string
@@ -105,15 +121,64 @@ type (
 		Partition uint64
 
 		// If supported by the underlying messaging infrastructure, the sequence at which messages should be read from
-		// the topic. In RabbitMQ, this value is ignored. With Kafka, this value is the starting index on the topic of an
-		// individual consumer that is not part of a consumer group.
+		// the topic. In RabbitMQ, this value is the offset from which a stream queue is read (when zero, only if StreamOffset
+		// is "sequence"), otherwise it is ignored.
+		// With Kafka, this value is the starting index on the topic of an individual consumer that is not part of a
+		// consumer group. With Redis Streams, this value is the entry ID after which a consumer group declared when
+		// establishing topology begins reading, packed as the Sequence of each Delivery is: the milliseconds component
+		// of the ID shifted left by 20 bits, combined with its sequence component (i.e. ms<<20 | seq).
 		Sequence uint64
+
+		// For RabbitMQ stream queues, the position from which to read when Sequence is not specified: "first", "last",
+		// "next" (the default), or an RFC 3339 timestamp from which to read messages appended at or after that time. The
+		// value "sequence" reads from the offset indicated by Sequence even when it is zero, i.e. the first offset.
+		StreamOffset string
+
+		// For RabbitMQ, the arguments with which the queue is declared when establishing topology, such as its
+		// dead-letter exchange, message TTL, and maximum length. Other messaging infrastructure ignores these values. As
+		// stream queues support none of these values, a RabbitMQ stream with a StreamQueue may not specify any of them.
+		QueueArguments QueueArguments
+	}
+	QueueArguments struct {
+		// The exchange to which messages are republished when rejected without requeue, expired, or dropped due to the
+		// maximum length. When establishing topology, the exchange is declared along with a queue bound to it, named
+		// DeadLetterQueue or, if not specified, named the same as the exchange, such that messages which cannot be
+		// processed may be inspected rather than being redelivered forever.
+		DeadLetterExchange   string
+		DeadLetterRoutingKey string
+		DeadLetterQueue      string
+
+		// The duration a message may remain in the queue before it expires.
+		MessageTTL time.Duration
+
+		// The maximum number of messages in the queue and the behavior when the maximum is reached: "drop-head" (the
+		// default), "reject-publish", or "reject-publish-dlx".
+		MaxLength uint64
+		Overflow  string
+
+		// For replicated (quorum) queues, the number of times a message may be delivered before it is dead-lettered.
+		DeliveryLimit uint64
+
+		// Indicates that only one consumer at a time receives messages from the queue, others being on standby.
+		SingleActiveConsumer bool
+
+		// The maximum priority (no more than 255, though RabbitMQ recommends no more than 10) supported by the queue.
+		// Messages of higher priority are delivered ahead of those of lower priority; those with a priority greater than
+		// the maximum are treated as having the maximum. When zero, the queue does not support priorities.
+		MaxPriority uint8
 	}
 	Stream interface {
 		Read(ctx context.Context, delivery *Delivery) error
 		Acknowledge(ctx context.Context, deliveries ...Delivery) error
 		io.Closer
 	}
+
+	// Rejecter is optionally implemented by a Stream when the underlying messaging infrastructure supports the negative
+	// acknowledgement of deliveries. Rejected deliveries are either requeued for redelivery or discarded (which, with
+	// RabbitMQ, dead-letters them when the queue is so configured).
+	Rejecter interface {
+		Reject(ctx context.Context, requeue bool, deliveries ...Delivery) error
+	}
 )
 
 // Writing:
@@ -125,9 +190,12 @@ type (
 		CorrelationID   uint64 // FUTURE: CausationID and UserID
 		Timestamp       time.Time
 		Expiration      time.Duration
+		Delay           time.Duration // the minimum duration after being written before the message is delivered, if supported
+		DeliveryTime    time.Time     // the time before which the message is not delivered, if supported; overrides Delay
 		Durable         bool
 		Topic           string
 		Partition       uint64 // Not the partition to send to, but instead the PartitionKey to be used (by a hashing algorithm) to decide which partition to send the message to.
+		Priority        uint8  // If supported, higher priority messages are delivered ahead of lower priority messages already queued.
 		MessageType     string
 		ContentType     string
 		ContentEncoding string
@@ -146,4 +214,8 @@ type (
 	}
 )
 
+// HeaderRoutingKey is the dispatch header which, when present, specifies the routing key with which a RabbitMQ writer
+// publishes the dispatch. The header itself is not published.
+const HeaderRoutingKey = "routing-key"
+
 var ErrEmptyDispatchTopic = errors.New("the destination topic is missing")
@@ -0,0 +1,101 @@
+package filelog
+
+import (
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+func New(options ...option) messaging.Connector {
+	var config configuration
+	Options.apply(options...)(&config)
+	return newConnector(config)
+}
+
+type configuration struct {
+	Directory    string
+	SegmentSize  int64
+	Retention    time.Duration
+	SyncPolicy   SyncPolicy
+	SyncInterval time.Duration
+	PollInterval time.Duration
+	Now          func() time.Time
+	Logger       logger
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// Directory is the root directory beneath which the log of each topic and the offsets of each stream are stored.
+func (singleton) Directory(value string) option {
+	return func(this *configuration) { this.Directory = value }
+}
+
+// SegmentSize is the size, in bytes, at which the active segment file of a topic is closed and a new segment started.
+func (singleton) SegmentSize(value int64) option {
+	return func(this *configuration) { this.SegmentSize = value }
+}
+
+// Retention is the age beyond which inactive segments are deleted when a topic rotates to a new segment. A zero value
+// retains all segments indefinitely.
+func (singleton) Retention(value time.Duration) option {
+	return func(this *configuration) { this.Retention = value }
+}
+
+// SyncPolicy determines when appended records are flushed to stable storage. See SyncPolicy for details.
+func (singleton) SyncPolicy(value SyncPolicy) option {
+	return func(this *configuration) { this.SyncPolicy = value }
+}
+
+// SyncInterval is the frequency at which topics are flushed to stable storage under the SyncPeriodically policy.
+func (singleton) SyncInterval(value time.Duration) option {
+	return func(this *configuration) { this.SyncInterval = value }
+}
+
+// PollInterval is the frequency at which streams check for records appended by other processes. Records appended
+// within the same process are observed immediately.
+func (singleton) PollInterval(value time.Duration) option {
+	return func(this *configuration) { this.PollInterval = value }
+}
+func (singleton) Now(value func() time.Time) option {
+	return func(this *configuration) { this.Now = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultNow = time.Now
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.Directory(defaultDirectory),
+		Options.SegmentSize(defaultSegmentSize),
+		Options.Retention(0),
+		Options.SyncPolicy(SyncEachWrite),
+		Options.SyncInterval(time.Second),
+		Options.PollInterval(time.Second),
+		Options.Now(defaultNow),
+		Options.Logger(defaultLogger),
+	}, options...)
+}
+
+const (
+	defaultDirectory   = "messages"
+	defaultSegmentSize = 1024 * 1024 * 64
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
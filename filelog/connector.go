@@ -0,0 +1,67 @@
+package filelog
+
+import (
+	"context"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// defaultConnector opens the store upon the first connection; all connections share the store, which remains open
+// until the connector is closed.
+type defaultConnector struct {
+	config configuration
+	logger logger
+
+	store  *store
+	active []messaging.Connection
+	mutex  sync.Mutex
+}
+
+func newConnector(config configuration) messaging.Connector {
+	return &defaultConnector{config: config, logger: config.Logger}
+}
+
+func (this *defaultConnector) Connect(ctx context.Context) (messaging.Connection, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.store == nil {
+		store, err := openStore(this.config)
+		if err != nil {
+			this.logger.Printf("[WARN] Unable to open log directory [%s]: %s", this.config.Directory, err)
+			return nil, err
+		}
+
+		this.logger.Printf("[INFO] Opened log directory [%s].", this.config.Directory)
+		this.store = store
+	}
+
+	this.active = append(this.active, newConnection(this.store, this.config))
+	return this.active[len(this.active)-1], nil
+}
+
+func (this *defaultConnector) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i := range this.active {
+		_ = this.active[i].Close()
+		this.active[i] = nil
+	}
+	this.active = this.active[0:0]
+
+	if this.store == nil {
+		return nil
+	}
+
+	err := this.store.Close()
+	this.store = nil
+	return err
+}
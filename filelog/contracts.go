@@ -0,0 +1,32 @@
+package filelog
+
+import "errors"
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+var (
+	ErrMissingStreamName = errors.New("the stream name is required to track the offsets of the stream")
+	ErrMissingTopics     = errors.New("at least one topic is required")
+	ErrInvalidName       = errors.New("topic and stream names must be non-empty and must not contain path separators")
+	ErrStreamInUse       = errors.New("a stream of the same name is already open")
+	ErrUnknownTopic      = errors.New("the delivery does not belong to any topic of the stream")
+	ErrClosed            = errors.New("the resource has already been closed")
+)
+
+// SyncPolicy determines when records appended to a topic are flushed (fsync) to stable storage and thus when they are
+// guaranteed to survive a crash of the operating system or a loss of power. Regardless of the policy, the records
+// written by a process which crashes are retained and any partially written record is discarded upon recovery.
+type SyncPolicy int
+
+const (
+	// SyncEachWrite flushes the records of each call to Write (or Commit) before it returns.
+	SyncEachWrite SyncPolicy = iota
+
+	// SyncPeriodically flushes all topics with unflushed records at the configured SyncInterval.
+	SyncPeriodically
+
+	// SyncNever leaves flushing to the operating system.
+	SyncNever
+)
@@ -0,0 +1,114 @@
+package filelog
+
+import (
+	"errors"
+	"os"
+)
+
+// cursor reads the records of a single topic in order, beginning at a given offset and following the log from one
+// segment to the next as it grows.
+type cursor struct {
+	directory string
+	offset    uint64
+	logger    logger
+
+	file     *os.File
+	base     uint64
+	position int64
+}
+
+func newCursor(directory string, offset uint64, logger logger) *cursor {
+	return &cursor{directory: directory, offset: offset, logger: logger}
+}
+
+// Next returns the record at the cursor's offset, if it has been written, and advances the cursor beyond it.
+func (this *cursor) Next() (item record, found bool, err error) {
+	if this.file == nil {
+		if found, err = this.open(); !found || err != nil {
+			return item, false, err
+		}
+	}
+
+	for {
+		item, size, err := readFrame(this.file, this.position)
+		if errors.Is(err, errIncompleteFrame) {
+			if advanced, err := this.advance(); !advanced || err != nil {
+				return item, false, err
+			}
+			continue
+		} else if err != nil {
+			return item, false, err
+		}
+
+		this.position += size
+		if item.Offset < this.offset {
+			continue
+		}
+
+		this.offset = item.Offset + 1
+		return item, true, nil
+	}
+}
+
+// open opens the segment containing the cursor's offset. If the offset precedes the first retained segment, the
+// cursor begins at the first retained record instead.
+func (this *cursor) open() (bool, error) {
+	bases, err := listSegments(this.directory)
+	if err != nil || len(bases) == 0 {
+		return false, err
+	}
+
+	base := bases[0]
+	for _, candidate := range bases {
+		if candidate <= this.offset {
+			base = candidate
+		}
+	}
+
+	if this.offset < base {
+		this.logger.Printf("[WARN] Offset [%d] is no longer retained, resuming at offset [%d] of [%s].", this.offset, base, this.directory)
+		this.offset = base
+	}
+
+	return true, this.openSegment(base)
+}
+
+// advance moves to the segment following the current segment, if any. As records may have been appended to the current
+// segment after it was last read but before the following segment was created, the current segment is read once more
+// and only left behind if it remains incomplete, in which case the incomplete record was torn and is discarded.
+func (this *cursor) advance() (bool, error) {
+	bases, err := listSegments(this.directory)
+	if err != nil {
+		return false, err
+	}
+
+	for _, base := range bases {
+		if base <= this.base {
+			continue
+		}
+
+		if _, _, err = readFrame(this.file, this.position); !errors.Is(err, errIncompleteFrame) {
+			return true, nil // the record (or error) is read by the caller
+		}
+
+		_ = this.file.Close()
+		return true, this.openSegment(base)
+	}
+
+	return false, nil
+}
+func (this *cursor) openSegment(base uint64) (err error) {
+	this.file, err = os.Open(segmentPath(this.directory, base))
+	this.base, this.position = base, 0
+	return err
+}
+
+func (this *cursor) Close() error {
+	if this.file == nil {
+		return nil
+	}
+
+	err := this.file.Close()
+	this.file = nil
+	return err
+}
@@ -0,0 +1,374 @@
+package filelog
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// store owns the log of each topic beneath the configured directory and is shared by all connections of a connector.
+// Appends to each topic are serialized within the process; appending to the same directory from multiple processes
+// is not supported, although any number of processes may read from it.
+type store struct {
+	directory    string
+	segmentSize  int64
+	retention    time.Duration
+	syncPolicy   SyncPolicy
+	pollInterval time.Duration
+	now          func() time.Time
+	logger       logger
+	notifier     *notifier
+
+	mutex   sync.Mutex
+	topics  map[string]*topicLog
+	streams map[string]struct{}
+	closed  bool
+	done    chan struct{}
+}
+
+func openStore(config configuration) (*store, error) {
+	for _, directory := range []string{topicsDirectory, streamsDirectory} {
+		if err := os.MkdirAll(filepath.Join(config.Directory, directory), directoryPermissions); err != nil {
+			return nil, err
+		}
+	}
+
+	this := &store{
+		directory:    config.Directory,
+		segmentSize:  config.SegmentSize,
+		retention:    config.Retention,
+		syncPolicy:   config.SyncPolicy,
+		pollInterval: config.PollInterval,
+		now:          config.Now,
+		logger:       config.Logger,
+		notifier:     newNotifier(),
+		topics:       make(map[string]*topicLog),
+		streams:      make(map[string]struct{}),
+		done:         make(chan struct{}),
+	}
+
+	if this.syncPolicy == SyncPeriodically {
+		go this.syncPeriodically(config.SyncInterval)
+	}
+
+	return this, nil
+}
+
+func (this *store) topicDirectory(topic string) string {
+	return filepath.Join(this.directory, topicsDirectory, topic)
+}
+func (this *store) streamDirectory(stream string) string {
+	return filepath.Join(this.directory, streamsDirectory, stream)
+}
+
+// DeclareTopic creates the directory of the topic, if necessary, such that it may be read before it is written.
+func (this *store) DeclareTopic(topic string) error {
+	return os.MkdirAll(this.topicDirectory(topic), directoryPermissions)
+}
+
+// Append writes the records, in order, to the end of the topic's log and assigns each its offset.
+func (this *store) Append(topic string, records []record) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return ErrClosed
+	}
+
+	log, err := this.topic(topic)
+	if err != nil {
+		return err
+	}
+
+	if err = log.Append(records, this.syncPolicy == SyncEachWrite); err != nil {
+		return err
+	}
+
+	if log.size >= this.segmentSize {
+		if err = log.Rotate(this.syncPolicy != SyncNever); err != nil {
+			return err
+		}
+		log.Purge(this.now(), this.retention, this.logger)
+	}
+
+	this.notifier.Notify()
+	return nil
+}
+func (this *store) topic(name string) (*topicLog, error) {
+	if log, contains := this.topics[name]; contains {
+		return log, nil
+	}
+
+	log, err := openTopicLog(this.topicDirectory(name))
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to open log of topic [%s]: %s", name, err)
+		return nil, err
+	}
+
+	if log.recovered > 0 {
+		this.logger.Printf("[WARN] Discarded [%d] bytes of incomplete records from log of topic [%s].", log.recovered, name)
+	}
+
+	this.topics[name] = log
+	return log, nil
+}
+
+// Acquire reserves the stream name such that only a single stream of each name is open within the process.
+func (this *store) Acquire(stream string) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return ErrClosed
+	}
+
+	if _, contains := this.streams[stream]; contains {
+		return ErrStreamInUse
+	}
+
+	this.streams[stream] = struct{}{}
+	return nil
+}
+func (this *store) Release(stream string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	delete(this.streams, stream)
+}
+
+func (this *store) syncPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-this.done:
+			return
+		case <-ticker.C:
+			this.sync()
+		}
+	}
+}
+func (this *store) sync() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for name, log := range this.topics {
+		if err := log.Sync(); err != nil {
+			this.logger.Printf("[WARN] Unable to flush log of topic [%s]: %s", name, err)
+		}
+	}
+}
+
+func (this *store) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil
+	}
+
+	this.closed = true
+	close(this.done)
+	for name, log := range this.topics {
+		_ = log.Close(this.syncPolicy != SyncNever)
+		delete(this.topics, name)
+	}
+
+	this.notifier.Notify()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// topicLog is the sequence of segment files of a single topic. Records are only ever appended to the last (active)
+// segment; each record is assigned the next offset of the topic, beginning at 1.
+type topicLog struct {
+	directory string
+	active    *os.File
+	base      uint64
+	size      int64
+	next      uint64
+	dirty     bool
+	recovered int64
+}
+
+func openTopicLog(directory string) (*topicLog, error) {
+	if err := os.MkdirAll(directory, directoryPermissions); err != nil {
+		return nil, err
+	}
+
+	bases, err := listSegments(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	this := &topicLog{directory: directory, base: 1, next: 1}
+	if len(bases) > 0 {
+		this.base = bases[len(bases)-1]
+		this.next = this.base
+	}
+
+	if this.active, err = os.OpenFile(segmentPath(directory, this.base), os.O_RDWR|os.O_CREATE, filePermissions); err != nil {
+		return nil, err
+	}
+
+	if err = this.recover(); err != nil {
+		_ = this.active.Close()
+		return nil, err
+	}
+
+	return this, nil
+}
+
+// recover scans the active segment to find the offset of the next record and truncates any partially written record
+// from the end of the segment such that subsequent records are appended immediately after the last complete record.
+func (this *topicLog) recover() error {
+	info, err := this.active.Stat()
+	if err != nil {
+		return err
+	}
+
+	for {
+		item, size, err := readFrame(this.active, this.size)
+		if errors.Is(err, errIncompleteFrame) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		this.size += size
+		this.next = item.Offset + 1
+	}
+
+	if this.recovered = info.Size() - this.size; this.recovered == 0 {
+		_, err = this.active.Seek(this.size, io.SeekStart)
+		return err
+	}
+
+	if err = this.active.Truncate(this.size); err != nil {
+		return err
+	}
+
+	_, err = this.active.Seek(this.size, io.SeekStart)
+	return err
+}
+
+func (this *topicLog) Append(records []record, sync bool) error {
+	buffer := make([]byte, 0, 512*len(records))
+	next := this.next
+	for _, item := range records {
+		item.Offset = next
+		next++
+
+		var err error
+		if buffer, err = appendFrame(buffer, item); err != nil {
+			return err
+		}
+	}
+
+	// a single write such that concurrent readers observe either all or none of the batch (or, in the event of a
+	// crash, a torn final record which is discarded upon recovery)
+	if _, err := this.active.Write(buffer); err != nil {
+		_ = this.active.Truncate(this.size)
+		_, _ = this.active.Seek(this.size, io.SeekStart)
+		return err
+	}
+
+	this.size += int64(len(buffer))
+	this.next = next
+	this.dirty = true
+	if sync {
+		return this.Sync()
+	}
+
+	return nil
+}
+
+// Rotate completes the active segment and begins a new segment named by the next offset. When syncing, the directory is
+// also flushed such that the new segment's entry survives a crash.
+func (this *topicLog) Rotate(sync bool) error {
+	if err := this.active.Sync(); err != nil {
+		return err
+	}
+
+	next, err := os.OpenFile(segmentPath(this.directory, this.next), os.O_RDWR|os.O_CREATE|os.O_EXCL, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	_ = this.active.Close()
+	this.active, this.base, this.size, this.dirty = next, this.next, 0, false
+	if sync {
+		return syncDirectory(this.directory)
+	}
+
+	return nil
+}
+
+// Purge deletes each inactive segment last modified longer ago than the retention period, if any.
+func (this *topicLog) Purge(now time.Time, retention time.Duration, logger logger) {
+	if retention <= 0 {
+		return
+	}
+
+	cutoff := now.Add(-retention)
+	bases, _ := listSegments(this.directory)
+	for _, base := range bases {
+		if base >= this.base {
+			break
+		}
+
+		path := segmentPath(this.directory, base)
+		if info, err := os.Stat(path); err != nil || !info.ModTime().Before(cutoff) {
+			break // segments are modified in order, so no later segment may be expired either
+		}
+
+		if err := os.Remove(path); err != nil {
+			logger.Printf("[WARN] Unable to remove expired segment [%s]: %s", path, err)
+			break
+		}
+	}
+}
+
+func (this *topicLog) Sync() error {
+	if !this.dirty {
+		return nil
+	}
+
+	this.dirty = false
+	return this.active.Sync()
+}
+
+func (this *topicLog) Close(sync bool) error {
+	if sync {
+		_ = this.Sync()
+	}
+
+	return this.active.Close()
+}
+
+// syncDirectory flushes the entries of the directory, such as those of files created or renamed within it, to stable
+// storage.
+func syncDirectory(path string) error {
+	directory, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	err = directory.Sync()
+	if closeErr := directory.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+const (
+	topicsDirectory      = "topics"
+	streamsDirectory     = "streams"
+	directoryPermissions = 0o755
+	filePermissions      = 0o644
+)
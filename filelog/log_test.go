@@ -0,0 +1,196 @@
+package filelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+)
+
+func TestLogFixture(t *testing.T) {
+	gunit.Run(new(LogFixture), t)
+}
+
+type LogFixture struct {
+	*gunit.Fixture
+
+	now    time.Time
+	config configuration
+	store  *store
+}
+
+func (this *LogFixture) Setup() {
+	directory, err := os.MkdirTemp("", "filelog-")
+	this.So(err, should.BeNil)
+
+	this.now = time.Now()
+	Options.apply(
+		Options.Directory(directory),
+		Options.SegmentSize(256),
+		Options.Now(func() time.Time { return this.now }),
+	)(&this.config)
+	this.openStore()
+}
+func (this *LogFixture) Teardown() {
+	_ = this.store.Close()
+	_ = os.RemoveAll(this.config.Directory)
+}
+func (this *LogFixture) openStore() {
+	var err error
+	this.store, err = openStore(this.config)
+	this.So(err, should.BeNil)
+}
+
+func (this *LogFixture) TestWhenAppending_RecordsAssignedSequentialOffsetsBeginningAtOne() {
+	_ = this.store.Append("topic", []record{{MessageID: 1}, {MessageID: 2}})
+	_ = this.store.Append("topic", []record{{MessageID: 3}})
+
+	records := this.readAll("topic", 1)
+
+	this.So(len(records), should.Equal, 3)
+	this.So(records[0].Offset, should.Equal, 1)
+	this.So(records[2].Offset, should.Equal, 3)
+	this.So(records[2].MessageID, should.Equal, 3)
+}
+func (this *LogFixture) TestWhenSegmentSizeExceeded_RotateToNewSegmentNamedByNextOffset() {
+	for i := 0; i < 8; i++ {
+		_ = this.store.Append("topic", []record{{Payload: make([]byte, 64)}})
+	}
+
+	bases, _ := listSegments(this.store.topicDirectory("topic"))
+	records := this.readAll("topic", 1)
+
+	this.So(len(bases), should.BeGreaterThan, 1)
+	this.So(bases[0], should.Equal, 1)
+	this.So(len(records), should.Equal, 8)
+	this.So(records[7].Offset, should.Equal, 8)
+}
+func (this *LogFixture) TestWhenAppendedAndRotatedWhileAdvancingFromEnd_ReadRemainderOfPreviousSegment() {
+	_ = this.store.Append("topic", []record{{MessageID: 1}})
+	cursor := newCursor(this.store.topicDirectory("topic"), 1, nop{})
+	defer func() { _ = cursor.Close() }()
+	first, _, _ := cursor.Next()
+
+	// the cursor found the end of the segment, but the writer appends and rotates before it lists the segments
+	_ = this.store.Append("topic", []record{{MessageID: 2, Payload: make([]byte, 256)}})
+	_ = this.store.Append("topic", []record{{MessageID: 3}})
+	advanced, err := cursor.advance()
+	second, _, _ := cursor.Next()
+	third, _, _ := cursor.Next()
+
+	bases, _ := listSegments(this.store.topicDirectory("topic"))
+	this.So(bases, should.Equal, []uint64{1, 3})
+	this.So(advanced, should.BeTrue)
+	this.So(err, should.BeNil)
+	this.So(first.MessageID, should.Equal, 1)
+	this.So(second.MessageID, should.Equal, 2)
+	this.So(third.MessageID, should.Equal, 3)
+}
+func (this *LogFixture) TestWhenReopened_ContinueAtNextOffset() {
+	_ = this.store.Append("topic", []record{{}, {}})
+	_ = this.store.Close()
+	this.openStore()
+
+	_ = this.store.Append("topic", []record{{MessageID: 3}})
+	records := this.readAll("topic", 1)
+
+	this.So(len(records), should.Equal, 3)
+	this.So(records[2].Offset, should.Equal, 3)
+}
+func (this *LogFixture) TestWhenReopenedAfterTornWrite_DiscardIncompleteRecord() {
+	_ = this.store.Append("topic", []record{{MessageID: 1}})
+	_ = this.store.Close()
+	this.appendGarbage("topic", []byte{0, 0, 0, 42, 1, 2})
+	this.openStore()
+
+	_ = this.store.Append("topic", []record{{MessageID: 2}})
+	records := this.readAll("topic", 1)
+
+	this.So(len(records), should.Equal, 2)
+	this.So(records[1].Offset, should.Equal, 2)
+	this.So(records[1].MessageID, should.Equal, 2)
+}
+func (this *LogFixture) TestWhenRotatingWithRetention_RemoveExpiredSegments() {
+	this.config.Retention = time.Hour
+	_ = this.store.Close()
+	this.openStore()
+
+	for i := 0; i < 4; i++ {
+		_ = this.store.Append("topic", []record{{Payload: make([]byte, 256)}})
+	}
+	this.now = this.now.Add(time.Hour * 2)
+	_ = this.store.Append("topic", []record{{Payload: make([]byte, 256)}})
+
+	bases, _ := listSegments(this.store.topicDirectory("topic"))
+	this.So(bases, should.Equal, []uint64{6})
+}
+func (this *LogFixture) TestWhenReadingFromExpiredOffset_ResumeAtFirstRetainedRecord() {
+	this.config.Retention = time.Hour
+	_ = this.store.Close()
+	this.openStore()
+	for i := 0; i < 3; i++ {
+		_ = this.store.Append("topic", []record{{Payload: make([]byte, 256)}})
+	}
+	this.now = this.now.Add(time.Hour * 2)
+	_ = this.store.Append("topic", []record{{MessageID: 4, Payload: make([]byte, 256)}})
+	_ = this.store.Append("topic", []record{{MessageID: 5}})
+
+	records := this.readAll("topic", 1)
+
+	this.So(len(records), should.Equal, 1)
+	this.So(records[0].MessageID, should.Equal, 5)
+}
+func (this *LogFixture) TestWhenStreamNameAcquiredTwice_ReturnError() {
+	first := this.store.Acquire("stream")
+	second := this.store.Acquire("stream")
+	this.store.Release("stream")
+	third := this.store.Acquire("stream")
+
+	this.So(first, should.BeNil)
+	this.So(second, should.Equal, ErrStreamInUse)
+	this.So(third, should.BeNil)
+}
+func (this *LogFixture) TestWhenClosed_AppendFails() {
+	_ = this.store.Close()
+
+	err := this.store.Append("topic", []record{{}})
+
+	this.So(err, should.Equal, ErrClosed)
+}
+func (this *LogFixture) TestWhenStoringOffsets_LoadReturnsLatest() {
+	directory := this.store.streamDirectory("stream")
+	_ = os.MkdirAll(directory, directoryPermissions)
+
+	initial, _ := loadOffset(directory, "topic")
+	_ = storeOffset(directory, "topic", 42, true)
+	_ = storeOffset(directory, "topic", 43, true)
+	stored, err := loadOffset(directory, "topic")
+
+	this.So(initial, should.Equal, 0)
+	this.So(err, should.BeNil)
+	this.So(stored, should.Equal, 43)
+}
+
+func (this *LogFixture) readAll(topic string, offset uint64) (records []record) {
+	cursor := newCursor(this.store.topicDirectory(topic), offset, nop{})
+	defer func() { _ = cursor.Close() }()
+
+	for {
+		item, found, err := cursor.Next()
+		this.So(err, should.BeNil)
+		if !found {
+			return records
+		}
+		records = append(records, item)
+	}
+}
+func (this *LogFixture) appendGarbage(topic string, garbage []byte) {
+	bases, _ := listSegments(this.store.topicDirectory(topic))
+	path := segmentPath(filepath.Join(this.config.Directory, topicsDirectory, topic), bases[len(bases)-1])
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, filePermissions)
+	_, _ = file.Write(garbage)
+	_ = file.Close()
+}
@@ -0,0 +1,33 @@
+package filelog
+
+import "sync"
+
+// notifier wakes any streams waiting for records as soon as they are appended by writers in the same process.
+type notifier struct {
+	mutex  sync.Mutex
+	signal chan struct{}
+}
+
+func newNotifier() *notifier {
+	return &notifier{signal: make(chan struct{})}
+}
+
+func (this *notifier) Notify() {
+	if this == nil {
+		return
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	close(this.signal)
+	this.signal = make(chan struct{})
+}
+func (this *notifier) Changed() <-chan struct{} {
+	if this == nil {
+		return nil
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.signal
+}
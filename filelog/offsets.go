@@ -0,0 +1,58 @@
+package filelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// loadOffset returns the offset of the next record to be read from the topic by the stream whose offsets are stored in
+// the directory provided, or zero if the stream has not yet acknowledged any record of the topic.
+func loadOffset(directory, topic string) (uint64, error) {
+	raw, err := os.ReadFile(filepath.Join(directory, topic+offsetExtension))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+}
+
+// storeOffset replaces the stored offset by writing it to a temporary file which is then renamed over the previous
+// file such that a crash leaves either the previous or the new offset intact. When syncing, both the file and the
+// directory containing it are flushed such that the new offset, once stored, survives a crash.
+func storeOffset(directory, topic string, offset uint64, sync bool) error {
+	path := filepath.Join(directory, topic+offsetExtension)
+	temporary := path + temporaryExtension
+
+	file, err := os.OpenFile(temporary, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.WriteString(strconv.FormatUint(offset, 10)); err == nil && sync {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temporary)
+		return err
+	}
+
+	if err = os.Rename(temporary, path); err != nil || !sync {
+		return err
+	}
+
+	return syncDirectory(directory)
+}
+
+const (
+	offsetExtension    = ".offset"
+	temporaryExtension = ".tmp"
+)
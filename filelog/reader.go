@@ -0,0 +1,117 @@
+package filelog
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultReader struct {
+	store   *store
+	config  configuration
+	streams []io.Closer
+	closed  bool
+	mutex   sync.Mutex
+	logger  logger
+}
+
+func newReader(store *store, config configuration) messaging.Reader {
+	return &defaultReader{store: store, config: config, logger: config.Logger}
+}
+
+// Stream reads the Topics on behalf of the stream named by StreamName, whose acknowledged offsets are stored such that
+// the stream resumes where it left off when reopened. When Sequence is specified, each topic is instead read from that
+// offset (the first record of each topic has offset 1), which allows the history of the topics to be replayed.
+func (this *defaultReader) Stream(_ context.Context, settings messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	offsets, err := this.prepare(settings)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to open stream [%s]: %s", settings.StreamName, err)
+		return nil, err
+	}
+
+	if err = this.store.Acquire(settings.StreamName); err != nil {
+		this.logger.Printf("[WARN] Unable to open stream [%s]: %s", settings.StreamName, err)
+		return nil, err
+	}
+
+	this.logger.Printf("[INFO] Stream [%s] opened on topics %v at offsets %v, awaiting messages...", settings.StreamName, settings.Topics, offsets)
+	stream := newStream(this.store, settings.StreamName, settings.Topics, offsets, this.config)
+	this.streams = append(this.streams, stream)
+	return stream, nil
+}
+func (this *defaultReader) prepare(settings messaging.StreamConfig) ([]uint64, error) {
+	if len(settings.StreamName) == 0 {
+		return nil, ErrMissingStreamName
+	}
+	if len(settings.Topics) == 0 {
+		return nil, ErrMissingTopics
+	}
+	if !isValidName(settings.StreamName) {
+		return nil, ErrInvalidName
+	}
+	for _, topic := range append(append([]string{}, settings.Topics...), settings.AvailableTopics...) {
+		if !isValidName(topic) {
+			return nil, ErrInvalidName
+		}
+	}
+
+	if settings.EstablishTopology {
+		for _, topic := range append(append([]string{}, settings.Topics...), settings.AvailableTopics...) {
+			if err := this.store.DeclareTopic(topic); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	offsets := make([]uint64, 0, len(settings.Topics))
+	for _, topic := range settings.Topics {
+		offset, err := this.startingOffset(settings, topic)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+
+	return offsets, nil
+}
+func (this *defaultReader) startingOffset(settings messaging.StreamConfig, topic string) (uint64, error) {
+	if settings.Sequence > 0 {
+		return settings.Sequence, nil
+	}
+
+	offset, err := loadOffset(this.store.streamDirectory(settings.StreamName), topic)
+	if err != nil || offset > 0 {
+		return offset, err
+	}
+
+	return 1, nil
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
+
+// isValidName ensures that each topic and stream name maps to a single directory beneath the store's directory.
+func isValidName(value string) bool {
+	return len(value) > 0 && value != "." && value != ".." && !strings.ContainsAny(value, `/\`+"\x00")
+}
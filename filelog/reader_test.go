@@ -0,0 +1,93 @@
+package filelog
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestReaderFixture(t *testing.T) {
+	gunit.Run(new(ReaderFixture), t)
+}
+
+type ReaderFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	config configuration
+	store  *store
+	reader messaging.Reader
+}
+
+func (this *ReaderFixture) Setup() {
+	directory, err := os.MkdirTemp("", "filelog-")
+	this.So(err, should.BeNil)
+
+	this.ctx = context.Background()
+	Options.apply(Options.Directory(directory))(&this.config)
+	this.store, err = openStore(this.config)
+	this.So(err, should.BeNil)
+	this.reader = newReader(this.store, this.config)
+}
+func (this *ReaderFixture) Teardown() {
+	_ = this.reader.Close()
+	_ = this.store.Close()
+	_ = os.RemoveAll(this.config.Directory)
+}
+
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutName_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{Topics: []string{"a"}})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingStreamName)
+}
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutTopics_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "stream"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingTopics)
+}
+func (this *ReaderFixture) TestWhenNamesContainPathSeparators_ReturnError() {
+	_, streamErr := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "../stream", Topics: []string{"a"}})
+	_, topicErr := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "stream", Topics: []string{".."}})
+
+	this.So(streamErr, should.Equal, ErrInvalidName)
+	this.So(topicErr, should.Equal, ErrInvalidName)
+}
+func (this *ReaderFixture) TestWhenStreamAlreadyOpen_ReturnError() {
+	_, _ = this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "stream", Topics: []string{"a"}})
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "stream", Topics: []string{"a"}})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrStreamInUse)
+}
+func (this *ReaderFixture) TestWhenEstablishingTopology_CreateTopicDirectories() {
+	_, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "stream",
+		Topics:            []string{"a"},
+		AvailableTopics:   []string{"b"},
+	})
+
+	_, aErr := os.Stat(this.store.topicDirectory("a"))
+	_, bErr := os.Stat(this.store.topicDirectory("b"))
+	this.So(err, should.BeNil)
+	this.So(aErr, should.BeNil)
+	this.So(bErr, should.BeNil)
+}
+func (this *ReaderFixture) TestWhenClosed_StreamsClosedAndNamesReleased() {
+	stream, _ := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "stream", Topics: []string{"a"}})
+
+	err := this.reader.Close()
+	_, streamErr := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "stream", Topics: []string{"a"}})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).closed.Err(), should.NotBeNil)
+	this.So(streamErr, should.Equal, ErrClosed)
+	this.So(this.store.Acquire("stream"), should.BeNil)
+}
@@ -0,0 +1,114 @@
+package filelog
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// record is the durable representation of a dispatch within a segment. Each record is framed by a header containing
+// the length of the encoded record and its CRC-32 checksum such that a partially written (torn) record is detected.
+type record struct {
+	Offset          uint64         `json:"offset"`
+	Timestamp       time.Time      `json:"timestamp"`
+	SourceID        uint64         `json:"source_id,omitempty"`
+	MessageID       uint64         `json:"message_id,omitempty"`
+	CorrelationID   uint64         `json:"correlation_id,omitempty"`
+	Partition       uint64         `json:"partition,omitempty"`
+	MessageType     string         `json:"message_type,omitempty"`
+	ContentType     string         `json:"content_type,omitempty"`
+	ContentEncoding string         `json:"content_encoding,omitempty"`
+	Headers         map[string]any `json:"headers,omitempty"`
+	Payload         []byte         `json:"payload,omitempty"`
+}
+
+func appendFrame(target []byte, item record) ([]byte, error) {
+	body, err := json.Marshal(item)
+	if err != nil {
+		return target, err
+	}
+
+	var header [frameHeaderSize]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(body)))
+	binary.BigEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(body))
+	return append(append(target, header[:]...), body...), nil
+}
+
+// readFrame reads the record framed at the position provided and returns it along with the size of the frame. It
+// returns errIncompleteFrame if the frame has not been completely written (or was torn).
+func readFrame(file io.ReaderAt, position int64) (item record, size int64, err error) {
+	var header [frameHeaderSize]byte
+	if _, err = file.ReadAt(header[:], position); errors.Is(err, io.EOF) {
+		return item, 0, errIncompleteFrame
+	} else if err != nil {
+		return item, 0, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxFrameLength {
+		return item, 0, errIncompleteFrame
+	}
+
+	body := make([]byte, length)
+	if _, err = file.ReadAt(body, position+frameHeaderSize); errors.Is(err, io.EOF) {
+		return item, 0, errIncompleteFrame
+	} else if err != nil {
+		return item, 0, err
+	}
+
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:8]) {
+		return item, 0, errIncompleteFrame
+	}
+
+	if err = json.Unmarshal(body, &item); err != nil {
+		return item, 0, errIncompleteFrame
+	}
+
+	return item, frameHeaderSize + int64(length), nil
+}
+
+// listSegments returns the base offsets of the segments within the directory in ascending order. Each segment is
+// named by the offset of its first record.
+func listSegments(directory string) ([]uint64, error) {
+	entries, err := os.ReadDir(directory)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	bases := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		name, found := strings.CutSuffix(entry.Name(), segmentExtension)
+		if !found || entry.IsDir() {
+			continue
+		}
+
+		if base, err := strconv.ParseUint(name, 10, 64); err == nil {
+			bases = append(bases, base)
+		}
+	}
+
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+	return bases, nil
+}
+func segmentPath(directory string, base uint64) string {
+	return filepath.Join(directory, fmt.Sprintf("%020d%s", base, segmentExtension))
+}
+
+var errIncompleteFrame = errors.New("the frame is incomplete")
+
+const (
+	segmentExtension = ".log"
+	frameHeaderSize  = 8
+	maxFrameLength   = 1024 * 1024 * 1024
+)
@@ -0,0 +1,185 @@
+package filelog
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// defaultStream reads the topics of the stream in turn, delivering the next available record of each. The offset of
+// each delivery is its DeliveryID; acknowledging a delivery stores the offset following it as the position from which
+// the stream resumes when reopened, thereby implicitly acknowledging all earlier records of the same topic.
+type defaultStream struct {
+	store     *store
+	name      string
+	directory string
+	topics    []string
+	cursors   []*cursor
+	sync      bool
+	logger    logger
+
+	closed   context.Context
+	shutdown context.CancelFunc
+	reading  sync.Mutex
+	next     int
+
+	mutex        sync.Mutex
+	acknowledged map[string]uint64
+}
+
+func newStream(store *store, name string, topics []string, offsets []uint64, config configuration) *defaultStream {
+	cursors := make([]*cursor, 0, len(topics))
+	for i, topic := range topics {
+		cursors = append(cursors, newCursor(store.topicDirectory(topic), offsets[i], config.Logger))
+	}
+
+	closed, shutdown := context.WithCancel(context.Background())
+	return &defaultStream{
+		store:        store,
+		name:         name,
+		directory:    store.streamDirectory(name),
+		topics:       topics,
+		cursors:      cursors,
+		sync:         config.SyncPolicy != SyncNever,
+		logger:       config.Logger,
+		closed:       closed,
+		shutdown:     shutdown,
+		acknowledged: make(map[string]uint64, len(topics)),
+	}
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	for {
+		changed := this.store.notifier.Changed() // before reading such that no append is missed
+		found, err := this.tryRead(target)
+		if found || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-this.closed.Done():
+			return io.EOF
+		case <-changed:
+		case <-time.After(this.store.pollInterval):
+		}
+	}
+}
+func (this *defaultStream) tryRead(target *messaging.Delivery) (bool, error) {
+	this.reading.Lock()
+	defer this.reading.Unlock()
+
+	if this.closed.Err() != nil {
+		return false, io.EOF
+	}
+
+	for range this.cursors {
+		index := this.next
+		this.next = (this.next + 1) % len(this.cursors)
+
+		item, found, err := this.cursors[index].Next()
+		if err != nil {
+			this.logger.Printf("[WARN] Unable to read log of topic [%s]: %s", this.topics[index], err)
+			return false, err
+		}
+
+		if found {
+			processDelivery(this.topics[index], item, target)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+func processDelivery(topic string, source record, target *messaging.Delivery) {
+	target.Upstream = source
+	target.DeliveryID = source.Offset
+	target.SourceID = source.SourceID
+	target.MessageID = source.MessageID
+	target.CorrelationID = source.CorrelationID
+	target.Timestamp = source.Timestamp
+	target.Durable = true
+	target.Topic = topic
+	target.Partition = source.Partition
+	target.Sequence = source.Offset
+	target.MessageType = source.MessageType
+	target.ContentType = source.ContentType
+	target.ContentEncoding = source.ContentEncoding
+	target.Payload = source.Payload
+	target.Headers = source.Headers
+}
+
+func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	pending := make(map[string]uint64, len(this.topics))
+	for _, delivery := range deliveries {
+		if !this.contains(delivery.Topic) {
+			return ErrUnknownTopic
+		}
+
+		if offset := delivery.DeliveryID + 1; offset > pending[delivery.Topic] && offset > this.acknowledged[delivery.Topic] {
+			pending[delivery.Topic] = offset
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(this.directory, directoryPermissions); err != nil {
+		return err
+	}
+
+	for topic, offset := range pending {
+		if err := storeOffset(this.directory, topic, offset, this.sync); err != nil {
+			this.logger.Printf("[WARN] Unable to store offset of stream [%s] for topic [%s]: %s", this.name, topic, err)
+			return err
+		}
+
+		this.acknowledged[topic] = offset
+	}
+
+	return nil
+}
+func (this *defaultStream) contains(topic string) bool {
+	for _, item := range this.topics {
+		if item == topic {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (this *defaultStream) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed.Err() != nil {
+		return nil
+	}
+
+	this.shutdown()
+	this.store.Release(this.name)
+
+	this.reading.Lock()
+	defer this.reading.Unlock()
+	for _, cursor := range this.cursors {
+		_ = cursor.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package filelog
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestStreamFixture(t *testing.T) {
+	gunit.Run(new(StreamFixture), t)
+}
+
+type StreamFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	config configuration
+	store  *store
+	reader messaging.Reader
+	stream messaging.Stream
+}
+
+func (this *StreamFixture) Setup() {
+	directory, err := os.MkdirTemp("", "filelog-")
+	this.So(err, should.BeNil)
+
+	this.ctx = context.Background()
+	Options.apply(Options.Directory(directory), Options.PollInterval(time.Hour))(&this.config)
+	this.store, err = openStore(this.config)
+	this.So(err, should.BeNil)
+	this.reader = newReader(this.store, this.config)
+	this.stream = this.openStream(messaging.StreamConfig{StreamName: "stream", Topics: []string{"a", "b"}})
+}
+func (this *StreamFixture) Teardown() {
+	_ = this.reader.Close()
+	_ = this.store.Close()
+	_ = os.RemoveAll(this.config.Directory)
+}
+func (this *StreamFixture) openStream(settings messaging.StreamConfig) messaging.Stream {
+	stream, err := this.reader.Stream(this.ctx, settings)
+	this.So(err, should.BeNil)
+	return stream
+}
+
+func (this *StreamFixture) TestWhenReading_MapRecordToDelivery() {
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	_ = this.store.Append("a", []record{{
+		Timestamp:       timestamp,
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Partition:       4,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Headers:         map[string]any{"key": "value"},
+		Payload:         []byte("payload"),
+	}})
+
+	delivery := this.read()
+
+	this.So(delivery.DeliveryID, should.Equal, 1)
+	this.So(delivery.SourceID, should.Equal, 1)
+	this.So(delivery.MessageID, should.Equal, 2)
+	this.So(delivery.CorrelationID, should.Equal, 3)
+	this.So(delivery.Partition, should.Equal, 4)
+	this.So(delivery.Sequence, should.Equal, 1)
+	this.So(delivery.Timestamp, should.Equal, timestamp)
+	this.So(delivery.Durable, should.BeTrue)
+	this.So(delivery.Topic, should.Equal, "a")
+	this.So(delivery.MessageType, should.Equal, "message-type")
+	this.So(delivery.ContentType, should.Equal, "content-type")
+	this.So(delivery.ContentEncoding, should.Equal, "content-encoding")
+	this.So(delivery.Headers, should.Equal, map[string]any{"key": "value"})
+	this.So(delivery.Payload, should.Equal, []byte("payload"))
+}
+func (this *StreamFixture) TestWhenReadingMultipleTopics_AlternateBetweenTopics() {
+	_ = this.store.Append("a", []record{{MessageID: 1}, {MessageID: 2}})
+	_ = this.store.Append("b", []record{{MessageID: 3}})
+
+	first, second, third := this.read(), this.read(), this.read()
+
+	this.So([]uint64{first.MessageID, second.MessageID, third.MessageID}, should.Equal, []uint64{1, 3, 2})
+}
+func (this *StreamFixture) TestWhenWaitingForRecords_AppendWakesStream() {
+	go func() {
+		time.Sleep(time.Millisecond * 5)
+		_ = this.store.Append("b", []record{{MessageID: 42}})
+	}()
+
+	delivery := this.read()
+
+	this.So(delivery.MessageID, should.Equal, 42)
+}
+func (this *StreamFixture) TestWhenContextCancelledWhileWaiting_ReturnError() {
+	ctx, cancel := context.WithCancel(this.ctx)
+	cancel()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(ctx, &delivery)
+
+	this.So(err, should.Equal, context.Canceled)
+}
+func (this *StreamFixture) TestWhenClosedWhileWaiting_ReturnEOF() {
+	go func() {
+		time.Sleep(time.Millisecond * 5)
+		_ = this.stream.Close()
+	}()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+}
+func (this *StreamFixture) TestWhenAcknowledged_ReopenedStreamResumesAfterAcknowledgedOffset() {
+	_ = this.store.Append("a", []record{{MessageID: 1}, {MessageID: 2}, {MessageID: 3}})
+	_ = this.read()
+	second := this.read()
+
+	err := this.stream.Acknowledge(this.ctx, second)
+	_ = this.stream.Close()
+	this.stream = this.openStream(messaging.StreamConfig{StreamName: "stream", Topics: []string{"a", "b"}})
+
+	this.So(err, should.BeNil)
+	this.So(this.read().MessageID, should.Equal, 3)
+}
+func (this *StreamFixture) TestWhenAcknowledgingOutOfOrder_OffsetNeverMovesBackward() {
+	_ = this.store.Append("a", []record{{MessageID: 1}, {MessageID: 2}, {MessageID: 3}})
+	first, second := this.read(), this.read()
+
+	_ = this.stream.Acknowledge(this.ctx, second)
+	_ = this.stream.Acknowledge(this.ctx, first)
+
+	offset, _ := loadOffset(this.store.streamDirectory("stream"), "a")
+	this.So(offset, should.Equal, 3)
+}
+func (this *StreamFixture) TestWhenAcknowledgingDeliveryOfAnotherTopic_ReturnError() {
+	err := this.stream.Acknowledge(this.ctx, messaging.Delivery{Topic: "c", DeliveryID: 1})
+
+	this.So(err, should.Equal, ErrUnknownTopic)
+}
+func (this *StreamFixture) TestWhenOpenedWithSequence_ReplayFromOffsetRegardlessOfAcknowledgements() {
+	_ = this.store.Append("a", []record{{MessageID: 1}, {MessageID: 2}, {MessageID: 3}})
+	_ = this.read()
+	_ = this.stream.Acknowledge(this.ctx, this.read())
+	_ = this.stream.Close()
+
+	this.stream = this.openStream(messaging.StreamConfig{StreamName: "stream", Topics: []string{"a"}, Sequence: 1})
+
+	this.So(this.read().MessageID, should.Equal, 1)
+}
+
+func (this *StreamFixture) read() (delivery messaging.Delivery) {
+	ctx, cancel := context.WithTimeout(this.ctx, time.Millisecond*500)
+	defer cancel()
+
+	this.So(this.stream.Read(ctx, &delivery), should.BeNil)
+	return delivery
+}
@@ -0,0 +1,126 @@
+package filelog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// defaultWriter appends each dispatch to the log of its topic. The records of a single call to Write (or, for a
+// transactional writer, all records written prior to Commit) are appended to each topic with a single write such that
+// readers observe them together; no such guarantee is made across topics.
+type defaultWriter struct {
+	store         *store
+	transactional bool
+	now           func() time.Time
+	logger        logger
+
+	mutex  sync.Mutex
+	buffer []topicRecord
+}
+type topicRecord struct {
+	topic  string
+	record record
+}
+
+func newWriter(store *store, transactional bool, config configuration) messaging.CommitWriter {
+	return &defaultWriter{store: store, transactional: transactional, now: config.Now, logger: config.Logger}
+}
+
+func (this *defaultWriter) Write(_ context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	now := this.now().UTC()
+	records := make([]topicRecord, 0, len(dispatches))
+	for _, dispatch := range dispatches {
+		if len(dispatch.Topic) == 0 {
+			return 0, messaging.ErrEmptyDispatchTopic
+		}
+		if !isValidName(dispatch.Topic) {
+			return 0, ErrInvalidName
+		}
+
+		records = append(records, topicRecord{topic: dispatch.Topic, record: toRecord(dispatch, now)})
+	}
+
+	if this.transactional {
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		this.buffer = append(this.buffer, records...)
+		return len(records), nil
+	}
+
+	if err := this.append(records); err != nil {
+		return 0, err
+	}
+
+	return len(records), nil
+}
+func (this *defaultWriter) append(records []topicRecord) error {
+	var topics []string
+	grouped := make(map[string][]record)
+	for _, item := range records {
+		if _, contains := grouped[item.topic]; !contains {
+			topics = append(topics, item.topic)
+		}
+		grouped[item.topic] = append(grouped[item.topic], item.record)
+	}
+
+	for _, topic := range topics {
+		if err := this.store.Append(topic, grouped[topic]); err != nil {
+			this.logger.Printf("[WARN] Unable to append records to log of topic [%s]: %s", topic, err)
+			return err
+		}
+	}
+
+	return nil
+}
+func toRecord(dispatch messaging.Dispatch, now time.Time) record {
+	if dispatch.Timestamp.IsZero() {
+		dispatch.Timestamp = now
+	}
+
+	return record{
+		Timestamp:       dispatch.Timestamp,
+		SourceID:        dispatch.SourceID,
+		MessageID:       dispatch.MessageID,
+		CorrelationID:   dispatch.CorrelationID,
+		Partition:       dispatch.Partition,
+		MessageType:     dispatch.MessageType,
+		ContentType:     dispatch.ContentType,
+		ContentEncoding: dispatch.ContentEncoding,
+		Headers:         dispatch.Headers,
+		Payload:         dispatch.Payload,
+	}
+}
+
+func (this *defaultWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if len(this.buffer) == 0 {
+		return nil
+	}
+
+	err := this.append(this.buffer)
+	this.clearBuffer()
+	return err
+}
+func (this *defaultWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.clearBuffer()
+	return nil
+}
+func (this *defaultWriter) clearBuffer() {
+	for i := range this.buffer {
+		this.buffer[i] = topicRecord{} // clear it out to avoid a memory leak
+	}
+
+	this.buffer = this.buffer[0:0]
+}
+
+func (this *defaultWriter) Close() error {
+	return this.Rollback()
+}
@@ -0,0 +1,109 @@
+package filelog
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	now    time.Time
+	config configuration
+	store  *store
+	writer messaging.CommitWriter
+}
+
+func (this *WriterFixture) Setup() {
+	directory, err := os.MkdirTemp("", "filelog-")
+	this.So(err, should.BeNil)
+
+	this.ctx = context.Background()
+	this.now = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	Options.apply(Options.Directory(directory), Options.Now(func() time.Time { return this.now }))(&this.config)
+	this.store, err = openStore(this.config)
+	this.So(err, should.BeNil)
+	this.writer = newWriter(this.store, false, this.config)
+}
+func (this *WriterFixture) Teardown() {
+	_ = this.store.Close()
+	_ = os.RemoveAll(this.config.Directory)
+}
+
+func (this *WriterFixture) TestWhenWritingDispatchWithoutTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+}
+func (this *WriterFixture) TestWhenWritingDispatchWithInvalidTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a/b"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, ErrInvalidName)
+}
+func (this *WriterFixture) TestWhenWriting_AppendRecordsToLogOfEachTopic() {
+	count, err := this.writer.Write(this.ctx,
+		messaging.Dispatch{Topic: "a", MessageID: 1, Payload: []byte("1")},
+		messaging.Dispatch{Topic: "b", MessageID: 2, Timestamp: time.Unix(1, 0).UTC()},
+		messaging.Dispatch{Topic: "a", MessageID: 3},
+	)
+
+	this.So(count, should.Equal, 3)
+	this.So(err, should.BeNil)
+
+	a := this.readAll("a")
+	b := this.readAll("b")
+	this.So(len(a), should.Equal, 2)
+	this.So(a[0].MessageID, should.Equal, 1)
+	this.So(a[0].Payload, should.Equal, []byte("1"))
+	this.So(a[0].Timestamp, should.Equal, this.now)
+	this.So(a[1].MessageID, should.Equal, 3)
+	this.So(len(b), should.Equal, 1)
+	this.So(b[0].Timestamp, should.Equal, time.Unix(1, 0).UTC())
+}
+func (this *WriterFixture) TestWhenWritingTransactionally_AppendUponCommit() {
+	this.writer = newWriter(this.store, true, this.config)
+
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	beforeCommit := this.readAll("a")
+	err := this.writer.Commit()
+
+	this.So(beforeCommit, should.BeEmpty)
+	this.So(err, should.BeNil)
+	this.So(len(this.readAll("a")), should.Equal, 1)
+}
+func (this *WriterFixture) TestWhenRollingBack_DiscardBufferedDispatches() {
+	this.writer = newWriter(this.store, true, this.config)
+
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	_ = this.writer.Rollback()
+	_ = this.writer.Commit()
+
+	this.So(this.readAll("a"), should.BeEmpty)
+}
+
+func (this *WriterFixture) readAll(topic string) (records []record) {
+	cursor := newCursor(this.store.topicDirectory(topic), 1, nop{})
+	defer func() { _ = cursor.Close() }()
+
+	for {
+		item, found, err := cursor.Next()
+		this.So(err, should.BeNil)
+		if !found {
+			return records
+		}
+		records = append(records, item)
+	}
+}
@@ -0,0 +1,100 @@
+package httpmq
+
+import (
+	"net/http"
+	"time"
+)
+
+func New(options ...option) Connector {
+	var config configuration
+	Options.apply(options...)(&config)
+	return newConnector(config)
+}
+
+type configuration struct {
+	Endpoints          map[string]string
+	Secret             []byte
+	SignatureTolerance time.Duration
+	Client             httpClient
+	AckTimeout         time.Duration
+	MaxBodyBytes       int64
+	Now                func() time.Time
+	Logger             logger
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// Endpoint is the URL to which dispatches of the topic are posted by writers.
+func (singleton) Endpoint(topic, url string) option {
+	return func(this *configuration) { this.Endpoints[topic] = url }
+}
+
+// Secret is the key used to sign each outbound request and to verify the signature of each inbound request using
+// HMAC-SHA256. When no secret is configured, outbound requests are not signed and inbound requests are not verified.
+func (singleton) Secret(value []byte) option {
+	return func(this *configuration) { this.Secret = value }
+}
+
+// SignatureTolerance is the largest difference between the signed timestamp of an inbound request and the current
+// time for which the request is accepted when a secret is configured; other requests are answered with 401
+// Unauthorized such that captured requests cannot be replayed later.
+func (singleton) SignatureTolerance(value time.Duration) option {
+	return func(this *configuration) { this.SignatureTolerance = value }
+}
+
+// Client is the HTTP client used by writers to post dispatches.
+func (singleton) Client(value httpClient) option {
+	return func(this *configuration) { this.Client = value }
+}
+
+// AckTimeout is the longest duration an inbound request waits to be acknowledged by a stream, after which the request
+// is answered with 503 Service Unavailable such that the sender retries it later.
+func (singleton) AckTimeout(value time.Duration) option {
+	return func(this *configuration) { this.AckTimeout = value }
+}
+
+// MaxBodyBytes is the largest inbound request body accepted; larger requests are answered with 413 Request Entity
+// Too Large.
+func (singleton) MaxBodyBytes(value int64) option {
+	return func(this *configuration) { this.MaxBodyBytes = value }
+}
+func (singleton) Now(value func() time.Time) option {
+	return func(this *configuration) { this.Now = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		this.Endpoints = make(map[string]string)
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultClient = &http.Client{Timeout: time.Second * 30}
+	var defaultNow = time.Now
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.SignatureTolerance(time.Minute * 5),
+		Options.Client(defaultClient),
+		Options.AckTimeout(time.Second * 30),
+		Options.MaxBodyBytes(defaultMaxBodyBytes),
+		Options.Now(defaultNow),
+		Options.Logger(defaultLogger),
+	}, options...)
+}
+
+const defaultMaxBodyBytes = 1024 * 1024 * 4
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
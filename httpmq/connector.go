@@ -0,0 +1,51 @@
+package httpmq
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultConnector struct {
+	receiver *receiver
+	config   configuration
+
+	active []messaging.Connection
+	mutex  sync.Mutex
+}
+
+func newConnector(config configuration) Connector {
+	return &defaultConnector{receiver: newReceiver(config), config: config}
+}
+
+func (this *defaultConnector) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	this.receiver.ServeHTTP(response, request)
+}
+
+func (this *defaultConnector) Connect(ctx context.Context) (messaging.Connection, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.active = append(this.active, newConnection(this.receiver, this.config))
+	return this.active[len(this.active)-1], nil
+}
+
+func (this *defaultConnector) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i := range this.active {
+		_ = this.active[i].Close()
+		this.active[i] = nil
+	}
+	this.active = this.active[0:0]
+
+	return nil
+}
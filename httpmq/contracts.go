@@ -0,0 +1,49 @@
+package httpmq
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// Connector posts the dispatches of its writers to the configured endpoints and, when mounted as an http.Handler,
+// provides each inbound request to the streams of its readers, answering the request once the resulting delivery has
+// been acknowledged.
+type Connector interface {
+	messaging.Connector
+	http.Handler
+}
+
+type httpClient interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+var (
+	ErrUnknownTopic       = errors.New("no endpoint has been configured for the topic")
+	ErrUnexpectedStatus   = errors.New("the endpoint responded with an unexpected status")
+	ErrUnknownDelivery    = errors.New("the delivery is not outstanding on the stream and cannot be acknowledged")
+	ErrClosed             = errors.New("the resource has already been closed")
+	errStreamClosed       = errors.New("the stream was closed before the delivery was acknowledged")
+	errInvalidSignature   = errors.New("the request signature is missing or invalid")
+	errStaleTimestamp     = errors.New("the request timestamp is missing or outside of the signature tolerance")
+	errAcknowledgeTimeout = errors.New("the delivery was not acknowledged in time")
+)
+
+// The request headers which carry the properties of a dispatch. The content type and encoding are carried in the
+// standard Content-Type and Content-Encoding headers. The signature is "sha256=" followed by the hex-encoded
+// HMAC-SHA256 of the values of the timestamp, topic, message type, source ID, message ID, correlation ID, content type,
+// and content encoding headers, in that order and each followed by a newline, and then the request body.
+const (
+	HeaderTopic         = "X-Topic"
+	HeaderSourceID      = "X-Source-Id"
+	HeaderMessageID     = "X-Message-Id"
+	HeaderCorrelationID = "X-Correlation-Id"
+	HeaderMessageType   = "X-Message-Type"
+	HeaderTimestamp     = "X-Message-Timestamp"
+	HeaderSignature     = "X-Signature"
+)
@@ -0,0 +1,49 @@
+package httpmq
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultReader struct {
+	receiver *receiver
+	streams  []io.Closer
+	closed   bool
+	mutex    sync.Mutex
+}
+
+func newReader(receiver *receiver) messaging.Reader {
+	return &defaultReader{receiver: receiver}
+}
+
+// Stream provides inbound requests to the caller. Every stream of the connector competes for the same requests, so
+// the settings of the stream (including its topics) are disregarded.
+func (this *defaultReader) Stream(_ context.Context, _ messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	stream := newStream(this.receiver.inbound)
+	this.streams = append(this.streams, stream)
+	return stream, nil
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
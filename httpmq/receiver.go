@@ -0,0 +1,193 @@
+package httpmq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// receiver answers each inbound request only once the delivery it produces has been acknowledged by a stream. Any
+// request not acknowledged within the configured timeout (or whose stream is closed first) is answered with 503
+// Service Unavailable so that the sender retries it later; each request is therefore delivered at least once.
+type receiver struct {
+	inbound      chan *inboundRequest
+	secret       []byte
+	ackTimeout   time.Duration
+	tolerance    time.Duration
+	maxBodyBytes int64
+	now          func() time.Time
+	logger       logger
+}
+type inboundRequest struct {
+	ctx      context.Context
+	delivery messaging.Delivery
+	result   chan error
+}
+
+func newReceiver(config configuration) *receiver {
+	return &receiver{
+		inbound:      make(chan *inboundRequest),
+		secret:       config.Secret,
+		ackTimeout:   config.AckTimeout,
+		tolerance:    config.SignatureTolerance,
+		maxBodyBytes: config.MaxBodyBytes,
+		now:          config.Now,
+		logger:       config.Logger,
+	}
+}
+
+func (this *receiver) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		response.Header().Set("Allow", http.MethodPost)
+		http.Error(response, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(response, request.Body, this.maxBodyBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(response, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	} else if err != nil {
+		http.Error(response, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err = this.authenticate(request.Header, body); err != nil {
+		this.logger.Printf("[WARN] Rejected inbound request to [%s]: %s", request.URL.Path, err)
+		http.Error(response, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	item := &inboundRequest{ctx: request.Context(), delivery: this.parse(request, body), result: make(chan error, 1)}
+	if err = this.deliver(item); err != nil {
+		if request.Context().Err() == nil {
+			http.Error(response, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// authenticate verifies the signature of the request, when a secret is configured, and rejects requests whose
+// timestamp differs from the current time by more than the configured tolerance such that a captured request cannot
+// be replayed later.
+func (this *receiver) authenticate(headers http.Header, body []byte) error {
+	if len(this.secret) == 0 {
+		return nil
+	}
+
+	if !verify(this.secret, headers, body) {
+		return errInvalidSignature
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, headers.Get(HeaderTimestamp))
+	if err != nil {
+		return errStaleTimestamp
+	}
+
+	if skew := this.now().Sub(timestamp); skew > this.tolerance || skew < -this.tolerance {
+		return errStaleTimestamp
+	}
+
+	return nil
+}
+func (this *receiver) deliver(item *inboundRequest) error {
+	timeout := time.NewTimer(this.ackTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-item.ctx.Done():
+		return item.ctx.Err()
+	case <-timeout.C:
+		return errAcknowledgeTimeout
+	case this.inbound <- item:
+	}
+
+	select {
+	case <-item.ctx.Done():
+		return item.ctx.Err()
+	case <-timeout.C:
+		return errAcknowledgeTimeout
+	case err := <-item.result:
+		return err
+	}
+}
+
+// parse produces the delivery of the request. The topic is taken from the topic header or, when absent (as with
+// requests from third parties), from the request path; likewise, the message type defaults to the topic such that
+// the requests of each third-party webhook may be deserialized as a distinct message type.
+func (this *receiver) parse(request *http.Request, body []byte) messaging.Delivery {
+	headers := request.Header
+	delivery := messaging.Delivery{
+		Upstream:        request,
+		SourceID:        parseUint64(headers.Get(HeaderSourceID)),
+		MessageID:       parseUint64(headers.Get(HeaderMessageID)),
+		CorrelationID:   parseUint64(headers.Get(HeaderCorrelationID)),
+		Timestamp:       this.now().UTC(),
+		Topic:           headers.Get(HeaderTopic),
+		MessageType:     headers.Get(HeaderMessageType),
+		ContentType:     headers.Get(headerContentType),
+		ContentEncoding: headers.Get(headerContentEncoding),
+		Payload:         body,
+	}
+
+	if parsed, err := time.Parse(time.RFC3339Nano, headers.Get(HeaderTimestamp)); err == nil {
+		delivery.Timestamp = parsed
+	}
+	if len(delivery.Topic) == 0 {
+		delivery.Topic = strings.Trim(request.URL.Path, "/")
+	}
+	if len(delivery.MessageType) == 0 {
+		delivery.MessageType = delivery.Topic
+	}
+
+	for key, values := range headers {
+		if _, contains := reservedHeaders[key]; contains || len(values) == 0 {
+			continue
+		}
+		if delivery.Headers == nil {
+			delivery.Headers = make(map[string]any, len(headers))
+		}
+		delivery.Headers[key] = values[0]
+	}
+
+	return delivery
+}
+func parseUint64(value string) uint64 {
+	parsed, _ := strconv.ParseUint(value, 10, 64)
+	return parsed
+}
+
+func (this *inboundRequest) complete(err error) {
+	select {
+	case this.result <- err:
+	default: // already completed
+	}
+}
+
+const (
+	headerContentType     = "Content-Type"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+)
+
+var reservedHeaders = map[string]struct{}{
+	HeaderTopic:           {},
+	HeaderSourceID:        {},
+	HeaderMessageID:       {},
+	HeaderCorrelationID:   {},
+	HeaderMessageType:     {},
+	HeaderTimestamp:       {},
+	HeaderSignature:       {},
+	headerContentType:     {},
+	headerContentEncoding: {},
+	headerContentLength:   {},
+}
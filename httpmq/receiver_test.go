@@ -0,0 +1,206 @@
+package httpmq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestReceiverFixture(t *testing.T) {
+	gunit.Run(new(ReceiverFixture), t)
+}
+
+type ReceiverFixture struct {
+	*gunit.Fixture
+
+	ctx       context.Context
+	now       time.Time
+	connector Connector
+	stream    messaging.Stream
+}
+
+func (this *ReceiverFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	this.initialize()
+}
+func (this *ReceiverFixture) initialize(options ...option) {
+	options = append([]option{
+		Options.AckTimeout(time.Millisecond * 100),
+		Options.Now(func() time.Time { return this.now }),
+	}, options...)
+	this.connector = New(options...)
+
+	connection, _ := this.connector.Connect(this.ctx)
+	reader, _ := connection.Reader(this.ctx)
+	this.stream, _ = reader.Stream(this.ctx, messaging.StreamConfig{})
+}
+func (this *ReceiverFixture) serve(request *http.Request) <-chan *httptest.ResponseRecorder {
+	result := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		recorder := httptest.NewRecorder()
+		this.connector.ServeHTTP(recorder, request)
+		result <- recorder
+	}()
+	return result
+}
+func (this *ReceiverFixture) read() (delivery messaging.Delivery) {
+	ctx, cancel := context.WithTimeout(this.ctx, time.Millisecond*250)
+	defer cancel()
+
+	this.So(this.stream.Read(ctx, &delivery), should.BeNil)
+	return delivery
+}
+
+func (this *ReceiverFixture) TestWhenRequestAcknowledged_RespondWithOK() {
+	request := httptest.NewRequest(http.MethodPost, "/hooks/github", strings.NewReader("payload"))
+	request.Header.Set(HeaderTopic, "topic")
+	request.Header.Set(HeaderSourceID, "1")
+	request.Header.Set(HeaderMessageID, "2")
+	request.Header.Set(HeaderCorrelationID, "3")
+	request.Header.Set(HeaderMessageType, "message-type")
+	request.Header.Set(HeaderTimestamp, "2021-01-01T00:00:00Z")
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Content-Encoding", "gzip")
+	request.Header.Set("X-Other", "value")
+	response := this.serve(request)
+
+	delivery := this.read()
+	err := this.stream.Acknowledge(this.ctx, delivery)
+
+	this.So(err, should.BeNil)
+	this.So((<-response).Code, should.Equal, http.StatusOK)
+	this.So(delivery.Upstream, should.Equal, request)
+	this.So(delivery.DeliveryID, should.Equal, 1)
+	this.So(delivery.SourceID, should.Equal, 1)
+	this.So(delivery.MessageID, should.Equal, 2)
+	this.So(delivery.CorrelationID, should.Equal, 3)
+	this.So(delivery.Timestamp, should.Equal, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	this.So(delivery.Topic, should.Equal, "topic")
+	this.So(delivery.MessageType, should.Equal, "message-type")
+	this.So(delivery.ContentType, should.Equal, "application/json")
+	this.So(delivery.ContentEncoding, should.Equal, "gzip")
+	this.So(delivery.Headers, should.Equal, map[string]any{"X-Other": "value"})
+	this.So(delivery.Payload, should.Equal, []byte("payload"))
+}
+func (this *ReceiverFixture) TestWhenThirdPartyRequest_TopicAndMessageTypeTakenFromPath() {
+	response := this.serve(httptest.NewRequest(http.MethodPost, "/hooks/github/", strings.NewReader("{}")))
+
+	delivery := this.read()
+	_ = this.stream.Acknowledge(this.ctx, delivery)
+	<-response
+
+	this.So(delivery.Topic, should.Equal, "hooks/github")
+	this.So(delivery.MessageType, should.Equal, "hooks/github")
+	this.So(delivery.Timestamp, should.Equal, this.now)
+}
+func (this *ReceiverFixture) TestWhenNotAcknowledgedInTime_RespondWithServiceUnavailable() {
+	response := this.serve(httptest.NewRequest(http.MethodPost, "/", nil))
+
+	_ = this.read()
+
+	this.So((<-response).Code, should.Equal, http.StatusServiceUnavailable)
+}
+func (this *ReceiverFixture) TestWhenStreamClosedBeforeAcknowledgement_RespondWithServiceUnavailable() {
+	response := this.serve(httptest.NewRequest(http.MethodPost, "/", nil))
+	delivery := this.read()
+
+	_ = this.stream.Close()
+	err := this.stream.Acknowledge(this.ctx, delivery)
+
+	this.So((<-response).Code, should.Equal, http.StatusServiceUnavailable)
+	this.So(err, should.Equal, ErrUnknownDelivery)
+}
+func (this *ReceiverFixture) TestWhenMethodIsNotPost_RespondWithMethodNotAllowed() {
+	recorder := <-this.serve(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	this.So(recorder.Code, should.Equal, http.StatusMethodNotAllowed)
+}
+func (this *ReceiverFixture) TestWhenBodyTooLarge_RespondWithRequestEntityTooLarge() {
+	this.initialize(Options.MaxBodyBytes(4))
+
+	recorder := <-this.serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345")))
+
+	this.So(recorder.Code, should.Equal, http.StatusRequestEntityTooLarge)
+}
+func (this *ReceiverFixture) signedRequest(secret string, timestamp time.Time) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload"))
+	request.Header.Set(HeaderTimestamp, timestamp.Format(time.RFC3339Nano))
+	request.Header.Set(HeaderTopic, "topic")
+	request.Header.Set(HeaderMessageType, "message-type")
+	request.Header.Set(HeaderMessageID, "2")
+	request.Header.Set(HeaderSignature, sign([]byte(secret), request.Header, []byte("payload")))
+	return request
+}
+func (this *ReceiverFixture) TestWhenSignatureInvalid_RespondWithUnauthorized() {
+	this.initialize(Options.Secret([]byte("secret")))
+
+	recorder := <-this.serve(this.signedRequest("other", this.now))
+
+	this.So(recorder.Code, should.Equal, http.StatusUnauthorized)
+}
+func (this *ReceiverFixture) TestWhenSignedHeaderTampered_RespondWithUnauthorized() {
+	this.initialize(Options.Secret([]byte("secret")))
+
+	for _, key := range signedHeaders {
+		request := this.signedRequest("secret", this.now)
+		request.Header.Set(key, "tampered")
+
+		recorder := <-this.serve(request)
+
+		this.So(recorder.Code, should.Equal, http.StatusUnauthorized)
+	}
+}
+func (this *ReceiverFixture) TestWhenTimestampOutsideTolerance_RespondWithUnauthorized() {
+	this.initialize(Options.Secret([]byte("secret")), Options.SignatureTolerance(time.Minute))
+
+	stale := <-this.serve(this.signedRequest("secret", this.now.Add(-time.Minute-time.Nanosecond)))
+	future := <-this.serve(this.signedRequest("secret", this.now.Add(time.Minute+time.Nanosecond)))
+
+	this.So(stale.Code, should.Equal, http.StatusUnauthorized)
+	this.So(future.Code, should.Equal, http.StatusUnauthorized)
+}
+func (this *ReceiverFixture) TestWhenSignatureValidAndTimestampWithinTolerance_Deliver() {
+	this.initialize(Options.Secret([]byte("secret")), Options.SignatureTolerance(time.Minute))
+	response := this.serve(this.signedRequest("secret", this.now.Add(-time.Minute)))
+
+	delivery := this.read()
+	_ = this.stream.Acknowledge(this.ctx, delivery)
+
+	this.So((<-response).Code, should.Equal, http.StatusOK)
+	this.So(delivery.Topic, should.Equal, "topic")
+	this.So(delivery.MessageID, should.Equal, 2)
+}
+func (this *ReceiverFixture) TestWhenWriterPostsToReceiver_DeliveryMatchesDispatch() {
+	this.initialize(Options.Secret([]byte("secret")))
+	server := httptest.NewServer(this.connector)
+	defer server.Close()
+	config := configuration{}
+	Options.apply(
+		Options.Endpoint("topic", server.URL+"/inbound"),
+		Options.Secret([]byte("secret")),
+		Options.Now(func() time.Time { return this.now }),
+	)(&config)
+	writer := newWriter(false, config)
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := writer.Write(this.ctx, messaging.Dispatch{Topic: "topic", MessageID: 42, MessageType: "type", Payload: []byte("payload")})
+		written <- err
+	}()
+	delivery := this.read()
+	_ = this.stream.Acknowledge(this.ctx, delivery)
+
+	this.So(<-written, should.BeNil)
+	this.So(delivery.Topic, should.Equal, "topic")
+	this.So(delivery.MessageID, should.Equal, 42)
+	this.So(delivery.MessageType, should.Equal, "type")
+	this.So(delivery.Payload, should.Equal, []byte("payload"))
+}
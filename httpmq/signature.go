@@ -0,0 +1,43 @@
+package httpmq
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// sign produces the signature of the canonical form of the request: the values of each signed header, in order, each
+// followed by a newline, and then the request body. Every property of the dispatch carried by a header is thereby
+// signed, such that none may be altered without invalidating the signature.
+func sign(secret []byte, headers http.Header, body []byte) string {
+	hash := hmac.New(sha256.New, secret)
+	for _, key := range signedHeaders {
+		_, _ = hash.Write([]byte(headers.Get(key)))
+		_, _ = hash.Write([]byte("\n"))
+	}
+	_, _ = hash.Write(body)
+	return signaturePrefix + hex.EncodeToString(hash.Sum(nil))
+}
+func verify(secret []byte, headers http.Header, body []byte) bool {
+	signature := headers.Get(HeaderSignature)
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(sign(secret, headers, body)), []byte(signature))
+}
+
+const signaturePrefix = "sha256="
+
+var signedHeaders = []string{
+	HeaderTimestamp,
+	HeaderTopic,
+	HeaderMessageType,
+	HeaderSourceID,
+	HeaderMessageID,
+	HeaderCorrelationID,
+	headerContentType,
+	headerContentEncoding,
+}
@@ -0,0 +1,89 @@
+package httpmq
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultStream struct {
+	inbound  <-chan *inboundRequest
+	closed   context.Context
+	shutdown context.CancelFunc
+
+	mutex    sync.Mutex
+	sequence uint64
+	inflight map[uint64]*inboundRequest
+}
+
+func newStream(inbound <-chan *inboundRequest) messaging.Stream {
+	closed, shutdown := context.WithCancel(context.Background())
+	return &defaultStream{inbound: inbound, closed: closed, shutdown: shutdown, inflight: make(map[uint64]*inboundRequest)}
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-this.closed.Done():
+			return io.EOF
+		case item := <-this.inbound:
+			if item.ctx.Err() != nil {
+				continue // the sender has already given up on the request
+			}
+
+			return this.track(item, target)
+		}
+	}
+}
+func (this *defaultStream) track(item *inboundRequest, target *messaging.Delivery) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed.Err() != nil {
+		item.complete(errStreamClosed)
+		return io.EOF
+	}
+
+	this.sequence++
+	this.inflight[this.sequence] = item
+	*target = item.delivery
+	target.DeliveryID = this.sequence
+	return nil
+}
+
+// Acknowledge answers the inbound request of each delivery with 200 OK.
+func (this *defaultStream) Acknowledge(_ context.Context, deliveries ...messaging.Delivery) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, delivery := range deliveries {
+		if _, contains := this.inflight[delivery.DeliveryID]; !contains {
+			return ErrUnknownDelivery
+		}
+	}
+
+	for _, delivery := range deliveries {
+		this.inflight[delivery.DeliveryID].complete(nil)
+		delete(this.inflight, delivery.DeliveryID)
+	}
+
+	return nil
+}
+
+// Close answers the inbound request of each unacknowledged delivery with 503 Service Unavailable.
+func (this *defaultStream) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.shutdown()
+	for deliveryID, item := range this.inflight {
+		item.complete(errStreamClosed)
+		delete(this.inflight, deliveryID)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package httpmq
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// defaultWriter posts each dispatch, in order, to the endpoint configured for its topic. HTTP offers no transaction
+// spanning multiple requests, so a transactional writer buffers the dispatches written and posts them upon Commit.
+type defaultWriter struct {
+	client        httpClient
+	endpoints     map[string]string
+	secret        []byte
+	transactional bool
+	now           func() time.Time
+	logger        logger
+
+	mutex  sync.Mutex
+	buffer []messaging.Dispatch
+}
+
+func newWriter(transactional bool, config configuration) messaging.CommitWriter {
+	return &defaultWriter{
+		client:        config.Client,
+		endpoints:     config.Endpoints,
+		secret:        config.Secret,
+		transactional: transactional,
+		now:           config.Now,
+		logger:        config.Logger,
+	}
+}
+
+func (this *defaultWriter) Write(ctx context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	for _, dispatch := range dispatches {
+		if len(dispatch.Topic) == 0 {
+			return 0, messaging.ErrEmptyDispatchTopic
+		}
+		if _, contains := this.endpoints[dispatch.Topic]; !contains {
+			return 0, ErrUnknownTopic
+		}
+	}
+
+	if this.transactional {
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		this.buffer = append(this.buffer, dispatches...)
+		return len(dispatches), nil
+	}
+
+	return this.post(ctx, dispatches)
+}
+func (this *defaultWriter) post(ctx context.Context, dispatches []messaging.Dispatch) (int, error) {
+	now := this.now().UTC()
+	for i, dispatch := range dispatches {
+		if err := this.postDispatch(ctx, dispatch, now); err != nil {
+			this.logger.Printf("[WARN] Unable to post dispatch of topic [%s]: %s", dispatch.Topic, err)
+			return i, err
+		}
+	}
+
+	return len(dispatches), nil
+}
+func (this *defaultWriter) postDispatch(ctx context.Context, dispatch messaging.Dispatch, now time.Time) error {
+	request, err := this.newRequest(ctx, dispatch, now)
+	if err != nil {
+		return err
+	}
+
+	response, err := this.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body) // drain the body such that the connection may be reused
+	_ = response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, response.StatusCode)
+	}
+
+	return nil
+}
+func (this *defaultWriter) newRequest(ctx context.Context, dispatch messaging.Dispatch, now time.Time) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, this.endpoints[dispatch.Topic], bytes.NewReader(dispatch.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	if dispatch.Timestamp.IsZero() {
+		dispatch.Timestamp = now
+	}
+
+	headers := request.Header
+	for key, value := range dispatch.Headers {
+		headers.Set(key, formatHeader(value))
+	}
+
+	timestamp := dispatch.Timestamp.UTC().Format(time.RFC3339Nano)
+	setHeader(headers, HeaderTopic, dispatch.Topic)
+	setHeader(headers, HeaderSourceID, formatUint64(dispatch.SourceID))
+	setHeader(headers, HeaderMessageID, formatUint64(dispatch.MessageID))
+	setHeader(headers, HeaderCorrelationID, formatUint64(dispatch.CorrelationID))
+	setHeader(headers, HeaderMessageType, dispatch.MessageType)
+	setHeader(headers, HeaderTimestamp, timestamp)
+	setHeader(headers, headerContentType, dispatch.ContentType)
+	setHeader(headers, headerContentEncoding, dispatch.ContentEncoding)
+	if len(this.secret) > 0 {
+		headers.Set(HeaderSignature, sign(this.secret, headers, dispatch.Payload))
+	}
+
+	return request, nil
+}
+func setHeader(headers http.Header, key, value string) {
+	if len(value) > 0 {
+		headers.Set(key, value)
+	}
+}
+func formatUint64(value uint64) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(value, 10)
+}
+func formatHeader(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func (this *defaultWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	_, err := this.post(context.Background(), this.buffer)
+	this.clearBuffer()
+	return err
+}
+func (this *defaultWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.clearBuffer()
+	return nil
+}
+func (this *defaultWriter) clearBuffer() {
+	for i := range this.buffer {
+		this.buffer[i] = messaging.Dispatch{} // clear it out to avoid a memory leak
+	}
+
+	this.buffer = this.buffer[0:0]
+}
+
+func (this *defaultWriter) Close() error {
+	return this.Rollback()
+}
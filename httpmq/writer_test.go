@@ -0,0 +1,156 @@
+package httpmq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	now    time.Time
+	config configuration
+	writer messaging.CommitWriter
+
+	requests       []*http.Request
+	requestBodies  []string
+	responseStatus int
+	responseError  error
+}
+
+func (this *WriterFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	this.responseStatus = http.StatusOK
+	Options.apply(
+		Options.Client(this),
+		Options.Endpoint("a", "https://a.example.com/hook"),
+		Options.Endpoint("b", "https://b.example.com/hook"),
+		Options.Now(func() time.Time { return this.now }),
+	)(&this.config)
+	this.writer = newWriter(false, this.config)
+}
+
+func (this *WriterFixture) TestWhenWritingDispatchWithoutTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+}
+func (this *WriterFixture) TestWhenWritingDispatchForTopicWithoutEndpoint_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "c"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, ErrUnknownTopic)
+	this.So(this.requests, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWriting_PostPayloadWithHeadersToTopicEndpoint() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Topic:           "b",
+		MessageType:     "message-type",
+		ContentType:     "application/json",
+		ContentEncoding: "gzip",
+		Headers:         map[string]any{"Key": "value"},
+		Payload:         []byte("payload"),
+	})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(len(this.requests), should.Equal, 1)
+	request := this.requests[0]
+	this.So(request.Method, should.Equal, http.MethodPost)
+	this.So(request.URL.String(), should.Equal, "https://b.example.com/hook")
+	this.So(request.Context(), should.Equal, this.ctx)
+	this.So(this.requestBodies[0], should.Equal, "payload")
+	this.So(request.Header.Get(HeaderTopic), should.Equal, "b")
+	this.So(request.Header.Get(HeaderSourceID), should.Equal, "1")
+	this.So(request.Header.Get(HeaderMessageID), should.Equal, "2")
+	this.So(request.Header.Get(HeaderCorrelationID), should.Equal, "3")
+	this.So(request.Header.Get(HeaderMessageType), should.Equal, "message-type")
+	this.So(request.Header.Get(HeaderTimestamp), should.Equal, "2020-01-02T03:04:05.000000006Z")
+	this.So(request.Header.Get("Content-Type"), should.Equal, "application/json")
+	this.So(request.Header.Get("Content-Encoding"), should.Equal, "gzip")
+	this.So(request.Header.Get("Key"), should.Equal, "value")
+	this.So(request.Header.Get(HeaderSignature), should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenSecretConfigured_SignHeadersAndPayload() {
+	this.config.Secret = []byte("secret")
+	this.writer = newWriter(false, this.config)
+
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a", MessageType: "message-type", Payload: []byte("payload")})
+
+	headers := this.requests[0].Header
+	this.So(headers.Get(HeaderSignature), should.StartWith, "sha256=")
+	this.So(verify([]byte("secret"), headers, []byte("payload")), should.BeTrue)
+	this.So(verify([]byte("other"), headers, []byte("payload")), should.BeFalse)
+	this.So(verify([]byte("secret"), headers, []byte("tampered")), should.BeFalse)
+	headers.Set(HeaderMessageType, "tampered")
+	this.So(verify([]byte("secret"), headers, []byte("payload")), should.BeFalse)
+}
+func (this *WriterFixture) TestWhenEndpointRespondsWithFailureStatus_ReturnCountWrittenAndError() {
+	this.responseStatus = http.StatusInternalServerError
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+
+	this.So(count, should.Equal, 0)
+	this.So(errors.Is(err, ErrUnexpectedStatus), should.BeTrue)
+	this.So(len(this.requests), should.Equal, 1)
+}
+func (this *WriterFixture) TestWhenRequestFails_ReturnError() {
+	this.responseError = errors.New("")
+
+	_, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	this.So(err, should.Equal, this.responseError)
+}
+func (this *WriterFixture) TestWhenWritingTransactionally_PostUponCommit() {
+	this.writer = newWriter(true, this.config)
+
+	count, _ := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+	beforeCommit := len(this.requests)
+	err := this.writer.Commit()
+
+	this.So(count, should.Equal, 2)
+	this.So(beforeCommit, should.Equal, 0)
+	this.So(err, should.BeNil)
+	this.So(len(this.requests), should.Equal, 2)
+}
+func (this *WriterFixture) TestWhenRollingBack_DiscardBufferedDispatches() {
+	this.writer = newWriter(true, this.config)
+
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	_ = this.writer.Rollback()
+	_ = this.writer.Commit()
+
+	this.So(this.requests, should.BeEmpty)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *WriterFixture) Do(request *http.Request) (*http.Response, error) {
+	this.requests = append(this.requests, request)
+	body, _ := io.ReadAll(request.Body)
+	this.requestBodies = append(this.requestBodies, string(body))
+	if this.responseError != nil {
+		return nil, this.responseError
+	}
+
+	return &http.Response{StatusCode: this.responseStatus, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
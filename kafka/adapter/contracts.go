@@ -0,0 +1,43 @@
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func New() Connector { return kafkaConnector{} }
+
+type ReaderConfig struct {
+	Brokers       []string
+	TLSConfig     *tls.Config
+	GroupID       string
+	GroupTopics   []string
+	Topic         string
+	Partition     int
+	Offset        int64
+	QueueCapacity int
+	MaxBytes      int
+}
+type WriterConfig struct {
+	Brokers   []string
+	TLSConfig *tls.Config
+}
+
+type Connector interface {
+	Reader(config ReaderConfig) (Reader, error)
+	Writer(config WriterConfig) (Writer, error)
+}
+
+type Reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, messages ...kafka.Message) error
+	io.Closer
+}
+
+type Writer interface {
+	WriteMessages(ctx context.Context, messages ...kafka.Message) error
+	io.Closer
+}
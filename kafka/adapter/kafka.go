@@ -0,0 +1,65 @@
+package adapter
+
+import (
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type kafkaConnector struct{}
+
+func (this kafkaConnector) Reader(config ReaderConfig) (Reader, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        config.Brokers,
+		Dialer:         &kafka.Dialer{Timeout: defaultDialTimeout, DualStack: true, TLS: config.TLSConfig},
+		GroupID:        config.GroupID,
+		GroupTopics:    config.GroupTopics,
+		Topic:          config.Topic,
+		Partition:      config.Partition,
+		QueueCapacity:  config.QueueCapacity,
+		MaxBytes:       config.MaxBytes,
+		CommitInterval: 0, // commits are synchronous such that acknowledgement errors are reported to the caller
+	})
+
+	if len(config.GroupID) > 0 {
+		return reader, nil
+	}
+
+	if err := reader.SetOffset(config.Offset); err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func (this kafkaConnector) Writer(config WriterConfig) (Writer, error) {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(config.Brokers...),
+		Balancer:     keyBalancer{keyed: &kafka.Hash{}, unkeyed: &kafka.RoundRobin{}},
+		RequiredAcks: kafka.RequireAll,
+	}
+
+	if config.TLSConfig != nil {
+		writer.Transport = &kafka.Transport{TLS: config.TLSConfig}
+	}
+
+	return writer, nil
+}
+
+// keyBalancer hashes the partition key of each message to select its partition such that all messages sharing a key
+// are routed to the same partition; messages without a key are distributed evenly.
+type keyBalancer struct {
+	keyed   kafka.Balancer
+	unkeyed kafka.Balancer
+}
+
+func (this keyBalancer) Balance(message kafka.Message, partitions ...int) int {
+	if len(message.Key) == 0 {
+		return this.unkeyed.Balance(message, partitions...)
+	}
+
+	return this.keyed.Balance(message, partitions...)
+}
+
+const defaultDialTimeout = time.Second * 10
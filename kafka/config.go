@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/kafka/adapter"
+)
+
+func New(options ...option) messaging.Connector {
+	var config configuration
+	Options.apply(options...)(&config)
+	return newConnector(config)
+}
+
+type configuration struct {
+	Brokers   []string
+	TLSConfig *tls.Config
+	Connector adapter.Connector
+	Logger    logger
+	Now       func() time.Time
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// Brokers are the addresses (host:port) of the Kafka brokers used to bootstrap connections to the cluster.
+func (singleton) Brokers(values ...string) option {
+	return func(this *configuration) { this.Brokers = values }
+}
+
+// TLSConfig, when provided, encrypts all connections to the cluster.
+func (singleton) TLSConfig(value *tls.Config) option {
+	return func(this *configuration) { this.TLSConfig = value }
+}
+func (singleton) Connector(value adapter.Connector) option {
+	return func(this *configuration) { this.Connector = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+func (singleton) Now(value func() time.Time) option {
+	return func(this *configuration) { this.Now = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultNow = time.Now
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.Brokers(defaultBroker),
+		Options.Connector(adapter.New()),
+		Options.Logger(defaultLogger),
+		Options.Now(defaultNow),
+	}, options...)
+}
+
+const defaultBroker = "127.0.0.1:9092"
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultConnector struct {
+	config configuration
+
+	active []messaging.Connection
+	mutex  sync.Mutex
+}
+
+func newConnector(config configuration) messaging.Connector {
+	return &defaultConnector{config: config}
+}
+
+// Connect doesn't contact the cluster; each reader and writer established through the connection maintains its own
+// connections to the brokers responsible for the partitions involved.
+func (this *defaultConnector) Connect(ctx context.Context) (messaging.Connection, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.active = append(this.active, newConnection(this.config))
+	return this.active[len(this.active)-1], nil
+}
+
+func (this *defaultConnector) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i := range this.active {
+		_ = this.active[i].Close()
+		this.active[i] = nil
+	}
+	this.active = this.active[0:0]
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package kafka
+
+import "errors"
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+var (
+	ErrMissingStreamName = errors.New("the stream name (topic) is required when not reading as part of a consumer group")
+	ErrMissingTopics     = errors.New("at least one topic is required when reading as part of a consumer group")
+	ErrClosed            = errors.New("the resource has already been closed")
+)
+
+// The record headers which carry the properties of a dispatch that have no native representation on a Kafka record.
+const (
+	HeaderSourceID        = "source-id"
+	HeaderMessageID       = "message-id"
+	HeaderCorrelationID   = "correlation-id"
+	HeaderMessageType     = "message-type"
+	HeaderContentType     = "content-type"
+	HeaderContentEncoding = "content-encoding"
+)
@@ -0,0 +1,99 @@
+package kafka
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/kafka/adapter"
+)
+
+type defaultReader struct {
+	inner   adapter.Connector
+	config  configuration
+	streams []io.Closer
+	closed  bool
+	mutex   sync.Mutex
+	logger  logger
+}
+
+func newReader(inner adapter.Connector, config configuration) messaging.Reader {
+	return &defaultReader{inner: inner, config: config, logger: config.Logger}
+}
+
+// Stream opens a consumer in one of two modes. When GroupName is specified, the stream joins that consumer group and
+// is assigned partitions of the Topics by the group coordinator; acknowledgements commit the group's offsets. Otherwise,
+// the stream reads the single Partition of the topic named by StreamName starting at the offset indicated by Sequence;
+// acknowledgements are not recorded with the cluster and the caller is responsible for tracking its position.
+func (this *defaultReader) Stream(_ context.Context, settings messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	config, err := this.readerConfig(settings)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to open Kafka consumer for stream [%s]: %s", settings.StreamName, err)
+		return nil, err
+	}
+
+	inner, err := this.inner.Reader(config)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to open Kafka consumer for stream [%s]: %s", settings.StreamName, err)
+		return nil, err
+	}
+
+	if len(config.GroupID) > 0 {
+		this.logger.Printf("[INFO] Consumer opened for group [%s] on topics %v, awaiting messages...", config.GroupID, config.GroupTopics)
+	} else {
+		this.logger.Printf("[INFO] Consumer opened for topic [%s] partition [%d] at offset [%d], awaiting messages...", config.Topic, config.Partition, config.Offset)
+	}
+
+	stream := newStream(inner, len(config.GroupID) > 0, this.config)
+	this.streams = append(this.streams, stream)
+	return stream, nil
+}
+func (this *defaultReader) readerConfig(settings messaging.StreamConfig) (adapter.ReaderConfig, error) {
+	config := adapter.ReaderConfig{
+		Brokers:       this.config.Brokers,
+		TLSConfig:     this.config.TLSConfig,
+		QueueCapacity: int(settings.BufferCapacity),
+		MaxBytes:      int(settings.MaxMessageBytes),
+	}
+
+	if len(settings.GroupName) > 0 {
+		if len(settings.Topics) == 0 {
+			return config, ErrMissingTopics
+		}
+
+		config.GroupID = settings.GroupName
+		config.GroupTopics = settings.Topics
+		return config, nil
+	}
+
+	if len(settings.StreamName) == 0 {
+		return config, ErrMissingStreamName
+	}
+
+	config.Topic = settings.StreamName
+	config.Partition = int(settings.Partition)
+	config.Offset = int64(settings.Sequence)
+	return config, nil
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
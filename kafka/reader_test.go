@@ -0,0 +1,125 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/kafka/adapter"
+)
+
+func TestReaderFixture(t *testing.T) {
+	gunit.Run(new(ReaderFixture), t)
+}
+
+type ReaderFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	reader messaging.Reader
+
+	readerConfigs []adapter.ReaderConfig
+	readerError   error
+	closeCount    int
+}
+
+func (this *ReaderFixture) Setup() {
+	this.ctx = context.Background()
+	config := configuration{}
+	Options.apply(Options.Brokers("broker1:9092", "broker2:9092"))(&config)
+	this.reader = newReader(this, config)
+}
+
+func (this *ReaderFixture) TestWhenOpeningGroupStream_JoinConsumerGroupForTopics() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		GroupName:       "group",
+		StreamName:      "ignored",
+		Topics:          []string{"topic1", "topic2"},
+		Partition:       1,
+		Sequence:        2,
+		BufferCapacity:  3,
+		MaxMessageBytes: 4,
+	})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).grouped, should.BeTrue)
+	this.So(this.readerConfigs, should.Equal, []adapter.ReaderConfig{{
+		Brokers:       []string{"broker1:9092", "broker2:9092"},
+		GroupID:       "group",
+		GroupTopics:   []string{"topic1", "topic2"},
+		QueueCapacity: 3,
+		MaxBytes:      4,
+	}})
+}
+func (this *ReaderFixture) TestWhenOpeningGroupStreamWithoutTopics_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{GroupName: "group"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingTopics)
+	this.So(this.readerConfigs, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenOpeningIndividualStream_ReadPartitionOfTopicFromSequence() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		StreamName: "topic",
+		Topics:     []string{"ignored"},
+		Partition:  5,
+		Sequence:   42,
+	})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).grouped, should.BeFalse)
+	this.So(this.readerConfigs, should.Equal, []adapter.ReaderConfig{{
+		Brokers:   []string{"broker1:9092", "broker2:9092"},
+		Topic:     "topic",
+		Partition: 5,
+		Offset:    42,
+	}})
+}
+func (this *ReaderFixture) TestWhenOpeningIndividualStreamWithoutName_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingStreamName)
+}
+func (this *ReaderFixture) TestWhenUnderlyingReaderFails_ReturnError() {
+	this.readerError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "topic"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.readerError)
+}
+func (this *ReaderFixture) TestWhenClosed_StreamsClosedAndNewStreamsRejected() {
+	_, _ = this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "topic"})
+
+	_ = this.reader.Close()
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "topic"})
+
+	this.So(this.closeCount, should.Equal, 1)
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrClosed)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ReaderFixture) Reader(config adapter.ReaderConfig) (adapter.Reader, error) {
+	this.readerConfigs = append(this.readerConfigs, config)
+	if this.readerError != nil {
+		return nil, this.readerError
+	}
+	return this, nil
+}
+func (this *ReaderFixture) Writer(_ adapter.WriterConfig) (adapter.Writer, error) { panic("nop") }
+
+func (this *ReaderFixture) FetchMessage(_ context.Context) (kafka.Message, error) { panic("nop") }
+func (this *ReaderFixture) CommitMessages(_ context.Context, _ ...kafka.Message) error {
+	panic("nop")
+}
+func (this *ReaderFixture) Close() error {
+	this.closeCount++
+	return nil
+}
@@ -0,0 +1,107 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/kafka/adapter"
+)
+
+type defaultStream struct {
+	inner   adapter.Reader
+	grouped bool
+	closer  sync.Once
+	logger  logger
+}
+
+func newStream(inner adapter.Reader, grouped bool, config configuration) messaging.Stream {
+	return &defaultStream{inner: inner, grouped: grouped, logger: config.Logger}
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	source, err := this.inner.FetchMessage(ctx)
+	if err != nil {
+		return err // io.EOF once the stream has been closed
+	}
+
+	processDelivery(source, target)
+	return nil
+}
+func processDelivery(source kafka.Message, target *messaging.Delivery) {
+	target.Upstream = source
+	target.DeliveryID = uint64(source.Offset)
+	target.Timestamp = source.Time
+	target.Durable = true
+	target.Topic = source.Topic
+	target.Partition = uint64(source.Partition)
+	target.Sequence = uint64(source.Offset)
+	target.Payload = source.Value
+	target.Headers = nil
+
+	for _, header := range source.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case HeaderSourceID:
+			target.SourceID = parseUint64(value)
+		case HeaderMessageID:
+			target.MessageID = parseUint64(value)
+		case HeaderCorrelationID:
+			target.CorrelationID = parseUint64(value)
+		case HeaderMessageType:
+			target.MessageType = value
+		case HeaderContentType:
+			target.ContentType = value
+		case HeaderContentEncoding:
+			target.ContentEncoding = value
+		default:
+			if target.Headers == nil {
+				target.Headers = make(map[string]any, len(source.Headers))
+			}
+			target.Headers[header.Key] = value
+		}
+	}
+}
+func parseUint64(value string) uint64 {
+	parsed, _ := strconv.ParseUint(value, 10, 64)
+	return parsed
+}
+
+// Acknowledge commits the offsets of the deliveries provided to the consumer group. Because Kafka offsets are
+// positional, committing a delivery implicitly acknowledges all earlier deliveries on the same partition.
+func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if !this.grouped || len(deliveries) == 0 {
+		return nil
+	}
+
+	messages := make([]kafka.Message, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		messages = append(messages, kafka.Message{
+			Topic:     delivery.Topic,
+			Partition: int(delivery.Partition),
+			Offset:    int64(delivery.DeliveryID),
+		})
+	}
+
+	if err := this.inner.CommitMessages(ctx, messages...); err != nil {
+		this.logger.Printf("[WARN] Unable to commit consumer group offsets [%s].", err)
+		return err
+	}
+
+	return nil
+}
+
+func (this *defaultStream) Close() (err error) {
+	this.closer.Do(func() {
+		err = this.inner.Close()
+	})
+	return err
+}
@@ -0,0 +1,155 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestStreamFixture(t *testing.T) {
+	gunit.Run(new(StreamFixture), t)
+}
+
+type StreamFixture struct {
+	*gunit.Fixture
+
+	ctx     context.Context
+	now     time.Time
+	grouped bool
+	stream  messaging.Stream
+
+	fetchContext context.Context
+	fetchMessage kafka.Message
+	fetchError   error
+
+	commitMessages []kafka.Message
+	commitError    error
+	closeCount     int
+}
+
+func (this *StreamFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Now().UTC()
+	this.grouped = true
+	this.initializeStream()
+}
+func (this *StreamFixture) initializeStream() {
+	this.stream = newStream(this, this.grouped, configuration{Logger: nop{}})
+}
+
+func (this *StreamFixture) TestWhenReading_PopulateDeliveryFromRecordAndHeaders() {
+	this.fetchMessage = kafka.Message{
+		Topic:     "topic",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("7"),
+		Value:     []byte("payload"),
+		Time:      this.now,
+		Headers: []kafka.Header{
+			{Key: HeaderSourceID, Value: []byte("1")},
+			{Key: HeaderMessageID, Value: []byte("2")},
+			{Key: HeaderCorrelationID, Value: []byte("3")},
+			{Key: HeaderMessageType, Value: []byte("message-type")},
+			{Key: HeaderContentType, Value: []byte("content-type")},
+			{Key: HeaderContentEncoding, Value: []byte("content-encoding")},
+			{Key: "custom", Value: []byte("value")},
+		},
+	}
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(this.fetchContext, should.Equal, this.ctx)
+	this.So(delivery, should.Equal, messaging.Delivery{
+		Upstream:        this.fetchMessage,
+		DeliveryID:      42,
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Timestamp:       this.now,
+		Durable:         true,
+		Topic:           "topic",
+		Partition:       3,
+		Sequence:        42,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Payload:         []byte("payload"),
+		Headers:         map[string]any{"custom": "value"},
+	})
+}
+func (this *StreamFixture) TestWhenReadingFails_ReturnUnderlyingError() {
+	this.fetchError = io.EOF
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+	this.So(delivery, should.Equal, messaging.Delivery{})
+}
+func (this *StreamFixture) TestWhenAcknowledgingGroupStream_CommitOffsetsOfEachDelivery() {
+	err := this.stream.Acknowledge(this.ctx,
+		messaging.Delivery{Topic: "a", Partition: 1, DeliveryID: 10},
+		messaging.Delivery{Topic: "b", Partition: 2, DeliveryID: 20},
+	)
+
+	this.So(err, should.BeNil)
+	this.So(this.commitMessages, should.Equal, []kafka.Message{
+		{Topic: "a", Partition: 1, Offset: 10},
+		{Topic: "b", Partition: 2, Offset: 20},
+	})
+}
+func (this *StreamFixture) TestWhenCommitFails_ReturnError() {
+	this.commitError = errors.New("")
+
+	err := this.stream.Acknowledge(this.ctx, messaging.Delivery{Topic: "a"})
+
+	this.So(err, should.Equal, this.commitError)
+}
+func (this *StreamFixture) TestWhenAcknowledgingIndividualStream_NothingCommitted() {
+	this.grouped = false
+	this.initializeStream()
+
+	err := this.stream.Acknowledge(this.ctx, messaging.Delivery{Topic: "a", DeliveryID: 10})
+
+	this.So(err, should.BeNil)
+	this.So(this.commitMessages, should.BeEmpty)
+}
+func (this *StreamFixture) TestWhenAcknowledgingWithCancelledContext_ReturnError() {
+	ctx, cancel := context.WithCancel(this.ctx)
+	cancel()
+
+	err := this.stream.Acknowledge(ctx, messaging.Delivery{Topic: "a"})
+
+	this.So(err, should.Equal, context.Canceled)
+	this.So(this.commitMessages, should.BeEmpty)
+}
+func (this *StreamFixture) TestWhenClosedMultipleTimes_UnderlyingReaderClosedOnce() {
+	_ = this.stream.Close()
+	_ = this.stream.Close()
+
+	this.So(this.closeCount, should.Equal, 1)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *StreamFixture) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	this.fetchContext = ctx
+	return this.fetchMessage, this.fetchError
+}
+func (this *StreamFixture) CommitMessages(_ context.Context, messages ...kafka.Message) error {
+	this.commitMessages = append(this.commitMessages, messages...)
+	return this.commitError
+}
+func (this *StreamFixture) Close() error {
+	this.closeCount++
+	return nil
+}
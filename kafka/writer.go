@@ -0,0 +1,153 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/kafka/adapter"
+)
+
+// defaultWriter produces records to the topic named by each dispatch. Kafka has no notion of a transaction spanning
+// topics and partitions which is comparable to an AMQP channel transaction, so a transactional writer buffers the
+// records written and produces them together upon Commit.
+type defaultWriter struct {
+	inner         adapter.Writer
+	transactional bool
+	now           func() time.Time
+	logger        logger
+
+	mutex  sync.Mutex
+	buffer []kafka.Message
+	closer sync.Once
+}
+
+func newWriter(inner adapter.Writer, transactional bool, config configuration) messaging.CommitWriter {
+	return &defaultWriter{inner: inner, transactional: transactional, now: config.Now, logger: config.Logger}
+}
+
+func (this *defaultWriter) Write(ctx context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	now := this.now().UTC()
+	messages := make([]kafka.Message, 0, len(dispatches))
+	for _, dispatch := range dispatches {
+		if len(dispatch.Topic) == 0 {
+			return 0, messaging.ErrEmptyDispatchTopic
+		}
+
+		messages = append(messages, toKafkaMessage(dispatch, now))
+	}
+
+	if this.transactional {
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		this.buffer = append(this.buffer, messages...)
+		return len(messages), nil
+	}
+
+	if err := this.inner.WriteMessages(ctx, messages...); err != nil {
+		this.logger.Printf("[WARN] Unable to produce records to Kafka [%s].", err)
+		return 0, err
+	}
+
+	return len(messages), nil
+}
+func toKafkaMessage(dispatch messaging.Dispatch, now time.Time) kafka.Message {
+	if dispatch.Timestamp.IsZero() {
+		dispatch.Timestamp = now
+	}
+
+	headers := make([]kafka.Header, 0, 6+len(dispatch.Headers))
+	headers = appendHeader(headers, HeaderSourceID, strconv.FormatUint(dispatch.SourceID, 10))
+	headers = appendHeader(headers, HeaderMessageID, strconv.FormatUint(dispatch.MessageID, 10))
+	headers = appendHeader(headers, HeaderCorrelationID, strconv.FormatUint(dispatch.CorrelationID, 10))
+	headers = appendHeader(headers, HeaderMessageType, dispatch.MessageType)
+	headers = appendHeader(headers, HeaderContentType, dispatch.ContentType)
+	headers = appendHeader(headers, HeaderContentEncoding, dispatch.ContentEncoding)
+	for _, key := range sortedKeys(dispatch.Headers) {
+		headers = appendHeader(headers, key, formatHeader(dispatch.Headers[key]))
+	}
+
+	return kafka.Message{
+		Topic:   dispatch.Topic,
+		Key:     formatPartitionKey(dispatch.Partition),
+		Value:   dispatch.Payload,
+		Headers: headers,
+		Time:    dispatch.Timestamp,
+	}
+}
+func appendHeader(headers []kafka.Header, key, value string) []kafka.Header {
+	if len(value) == 0 {
+		return headers
+	}
+
+	return append(headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+func formatHeader(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+func formatPartitionKey(value uint64) []byte {
+	if value == 0 {
+		return nil
+	}
+
+	return []byte(strconv.FormatUint(value, 10))
+}
+
+func (this *defaultWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if len(this.buffer) == 0 {
+		return nil
+	}
+
+	err := this.inner.WriteMessages(context.Background(), this.buffer...)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to produce records to Kafka [%s].", err)
+	}
+
+	this.clearBuffer()
+	return err
+}
+func (this *defaultWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.clearBuffer()
+	return nil
+}
+func (this *defaultWriter) clearBuffer() {
+	for i := range this.buffer {
+		this.buffer[i] = kafka.Message{} // clear it out to avoid a memory leak
+	}
+
+	this.buffer = this.buffer[0:0]
+}
+
+func (this *defaultWriter) Close() (err error) {
+	this.closer.Do(func() {
+		err = this.inner.Close()
+	})
+	return err
+}
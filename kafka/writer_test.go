@@ -0,0 +1,157 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	ctx           context.Context
+	now           time.Time
+	transactional bool
+	writer        messaging.CommitWriter
+
+	writeContexts []context.Context
+	writeMessages [][]kafka.Message
+	writeError    error
+	closeCount    int
+}
+
+func (this *WriterFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Now().UTC()
+	this.initializeWriter()
+}
+func (this *WriterFixture) initializeWriter() {
+	config := configuration{}
+	Options.apply(Options.Now(func() time.Time { return this.now }))(&config)
+	this.writer = newWriter(this, this.transactional, config)
+}
+
+func (this *WriterFixture) TestWhenWritingWithoutTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+	this.So(this.writeMessages, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWriting_ProduceRecordKeyedByPartitionWithHeaders() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Topic:           "topic",
+		Partition:       4,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Payload:         []byte("payload"),
+		Headers:         map[string]any{"b": 5, "a": "value"},
+	})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.writeContexts, should.Equal, []context.Context{this.ctx})
+	this.So(this.writeMessages, should.Equal, [][]kafka.Message{{{
+		Topic: "topic",
+		Key:   []byte("4"),
+		Value: []byte("payload"),
+		Time:  this.now,
+		Headers: []kafka.Header{
+			{Key: HeaderSourceID, Value: []byte("1")},
+			{Key: HeaderMessageID, Value: []byte("2")},
+			{Key: HeaderCorrelationID, Value: []byte("3")},
+			{Key: HeaderMessageType, Value: []byte("message-type")},
+			{Key: HeaderContentType, Value: []byte("content-type")},
+			{Key: HeaderContentEncoding, Value: []byte("content-encoding")},
+			{Key: "a", Value: []byte("value")},
+			{Key: "b", Value: []byte("5")},
+		},
+	}}})
+}
+func (this *WriterFixture) TestWhenWritingWithoutPartition_RecordHasNoKey() {
+	timestamp := this.now.Add(-time.Hour)
+
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic", Timestamp: timestamp})
+
+	this.So(this.writeMessages[0][0].Key, should.BeNil)
+	this.So(this.writeMessages[0][0].Time, should.Equal, timestamp)
+}
+func (this *WriterFixture) TestWhenWriteFails_ReturnError() {
+	this.writeError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.writeError)
+}
+func (this *WriterFixture) TestWhenTransactional_RecordsProducedTogetherOnCommit() {
+	this.transactional = true
+	this.initializeWriter()
+
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "b"})
+	this.So(this.writeMessages, should.BeEmpty)
+
+	err := this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(len(this.writeMessages), should.Equal, 1)
+	this.So(len(this.writeMessages[0]), should.Equal, 2)
+}
+func (this *WriterFixture) TestWhenTransactionalCommitFails_ReturnErrorAndDiscardBuffer() {
+	this.transactional = true
+	this.initializeWriter()
+	this.writeError = errors.New("")
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	err := this.writer.Commit()
+	this.writeError = nil
+	_ = this.writer.Commit()
+
+	this.So(err, should.NotBeNil)
+	this.So(len(this.writeMessages), should.Equal, 1)
+}
+func (this *WriterFixture) TestWhenRollingBack_BufferedRecordsDiscarded() {
+	this.transactional = true
+	this.initializeWriter()
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	rollbackErr := this.writer.Rollback()
+	commitErr := this.writer.Commit()
+
+	this.So(rollbackErr, should.BeNil)
+	this.So(commitErr, should.BeNil)
+	this.So(this.writeMessages, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenClosedMultipleTimes_UnderlyingWriterClosedOnce() {
+	_ = this.writer.Close()
+	_ = this.writer.Close()
+
+	this.So(this.closeCount, should.Equal, 1)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *WriterFixture) WriteMessages(ctx context.Context, messages ...kafka.Message) error {
+	this.writeContexts = append(this.writeContexts, ctx)
+	this.writeMessages = append(this.writeMessages, messages)
+	return this.writeError
+}
+func (this *WriterFixture) Close() error {
+	this.closeCount++
+	return nil
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+// broker models the exchange-to-queue fan-out performed by RabbitMQ: each topic is a fanout exchange and every queue
+// bound to that topic receives its own copy of each message written to it.
+type broker struct {
+	mutex  sync.Mutex
+	signal chan struct{}
+	topics map[string]map[string]struct{}
+	queues map[string]*queue
+	now    func() time.Time
+}
+type queue struct {
+	pending []envelope
+}
+type envelope struct {
+	Dispatch    messaging.Dispatch
+	Expires     time.Time
+	Redelivered bool
+	Attempt     uint64 // the number of times the message has been received
+}
+
+func newBroker(config configuration) *broker {
+	this := &broker{
+		signal: make(chan struct{}),
+		topics: make(map[string]map[string]struct{}),
+		queues: make(map[string]*queue),
+		now:    config.Now,
+	}
+
+	for _, topic := range config.Topics {
+		this.DeclareTopic(topic)
+	}
+
+	for _, item := range config.Queues {
+		this.DeclareQueue(item.Name)
+		for _, topic := range item.Topics {
+			this.DeclareTopic(topic)
+			this.BindQueue(item.Name, topic)
+		}
+	}
+
+	return this
+}
+
+func (this *broker) DeclareTopic(name string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if _, contains := this.topics[name]; !contains {
+		this.topics[name] = make(map[string]struct{})
+	}
+}
+func (this *broker) DeclareQueue(name string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if _, contains := this.queues[name]; !contains {
+		this.queues[name] = &queue{}
+	}
+}
+func (this *broker) BindQueue(queue, topic string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if bindings, contains := this.topics[topic]; contains {
+		bindings[queue] = struct{}{}
+	}
+}
+func (this *broker) QueueExists(name string) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	_, contains := this.queues[name]
+	return contains
+}
+
+// Publish routes each dispatch to every queue bound to its topic. Either all dispatches are routed or, if any topic
+// has not been declared, none of them are.
+func (this *broker) Publish(dispatches []messaging.Dispatch) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, dispatch := range dispatches {
+		if _, contains := this.topics[dispatch.Topic]; !contains {
+			return ErrUnknownTopic
+		}
+	}
+
+	now := this.now().UTC()
+	for _, dispatch := range dispatches {
+		item := envelope{Dispatch: dispatch}
+		if dispatch.Expiration > 0 {
+			item.Expires = now.Add(dispatch.Expiration)
+		}
+
+		for name := range this.topics[dispatch.Topic] {
+			this.queues[name].pending = append(this.queues[name].pending, item)
+		}
+	}
+
+	if len(dispatches) > 0 {
+		this.notify()
+	}
+
+	return nil
+}
+
+// Receive removes and returns the next unexpired message on the queue along with a channel which is closed the next
+// time the state of the broker changes, such that callers can wait for a message to arrive.
+func (this *broker) Receive(name string) (item envelope, received bool, changed <-chan struct{}) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	target := this.queues[name]
+	if target == nil {
+		return envelope{}, false, this.signal
+	}
+
+	now := this.now().UTC()
+	for len(target.pending) > 0 {
+		item, target.pending[0] = target.pending[0], envelope{}
+		target.pending = target.pending[1:]
+
+		if item.Expires.IsZero() || item.Expires.After(now) {
+			item.Attempt++
+			return item, true, this.signal
+		}
+	}
+
+	return envelope{}, false, this.signal
+}
+
+// Requeue places unacknowledged messages back at the head of the queue, in their original order, such that they are
+// redelivered to the next available consumer.
+func (this *broker) Requeue(name string, items []envelope) {
+	if len(items) == 0 {
+		return
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	target := this.queues[name]
+	if target == nil {
+		return
+	}
+
+	requeued := make([]envelope, 0, len(items)+len(target.pending))
+	for _, item := range items {
+		item.Redelivered = true
+		requeued = append(requeued, item)
+	}
+
+	target.pending = append(requeued, target.pending...)
+	this.notify()
+}
+
+// Changed returns a channel which is closed the next time the state of the broker changes.
+func (this *broker) Changed() <-chan struct{} {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.signal
+}
+func (this *broker) Notify() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.notify()
+}
+func (this *broker) notify() {
+	close(this.signal)
+	this.signal = make(chan struct{})
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+func New(options ...option) messaging.Connector {
+	var config configuration
+	Options.apply(options...)(&config)
+	return newConnector(config)
+}
+
+type configuration struct {
+	Topics []string
+	Queues []queueBinding
+	Now    func() time.Time
+	Logger logger
+}
+type queueBinding struct {
+	Name   string
+	Topics []string
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// Topics declares the topics (exchanges) which exist with the broker before any stream is opened, such that writes
+// to them succeed even when no stream has yet established its topology.
+func (singleton) Topics(values ...string) option {
+	return func(this *configuration) { this.Topics = append(this.Topics, values...) }
+}
+
+// Queue declares a queue (stream) bound to the topics provided before any stream is opened, such that messages
+// written to those topics are retained for consumers which have yet to connect.
+func (singleton) Queue(name string, topics ...string) option {
+	return func(this *configuration) { this.Queues = append(this.Queues, queueBinding{Name: name, Topics: topics}) }
+}
+func (singleton) Now(value func() time.Time) option {
+	return func(this *configuration) { this.Now = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultNow = time.Now
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.Now(defaultNow),
+		Options.Logger(defaultLogger),
+	}, options...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
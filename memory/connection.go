@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultConnection struct {
+	broker *broker
+	config configuration
+
+	children []io.Closer
+	closed   bool
+	mutex    sync.Mutex
+}
+
+func newConnection(broker *broker, config configuration) messaging.Connection {
+	return &defaultConnection{broker: broker, config: config}
+}
+
+func (this *defaultConnection) Reader(_ context.Context) (messaging.Reader, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	reader := newReader(this.broker, this.config)
+	this.children = append(this.children, reader)
+	return reader, nil
+}
+
+func (this *defaultConnection) Writer(_ context.Context) (messaging.Writer, error) {
+	return this.writer(false)
+}
+func (this *defaultConnection) CommitWriter(_ context.Context) (messaging.CommitWriter, error) {
+	return this.writer(true)
+}
+func (this *defaultConnection) writer(transactional bool) (messaging.CommitWriter, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	writer := newWriter(this.broker, transactional, this.config)
+	this.children = append(this.children, writer)
+	return writer, nil
+}
+
+func (this *defaultConnection) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil
+	}
+
+	this.closed = true
+	for i := range this.children {
+		_ = this.children[i].Close()
+		this.children[i] = nil
+	}
+	this.children = nil
+
+	return nil
+}
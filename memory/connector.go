@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultConnector struct {
+	broker *broker
+	config configuration
+
+	active []messaging.Connection
+	mutex  sync.Mutex
+}
+
+func newConnector(config configuration) messaging.Connector {
+	return &defaultConnector{broker: newBroker(config), config: config}
+}
+
+func (this *defaultConnector) Connect(ctx context.Context) (messaging.Connection, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.active = append(this.active, newConnection(this.broker, this.config))
+	return this.active[len(this.active)-1], nil
+}
+
+func (this *defaultConnector) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i := range this.active {
+		_ = this.active[i].Close()
+		this.active[i] = nil
+	}
+	this.active = this.active[0:0]
+
+	return nil
+}
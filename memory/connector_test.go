@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestConnectorFixture(t *testing.T) {
+	gunit.Run(new(ConnectorFixture), t)
+}
+
+type ConnectorFixture struct {
+	*gunit.Fixture
+
+	ctx       context.Context
+	connector messaging.Connector
+}
+
+func (this *ConnectorFixture) Setup() {
+	this.ctx = context.Background()
+	this.connector = New()
+}
+
+func (this *ConnectorFixture) TestWhenStreamEstablishesTopology_WritesAreFannedOutToEachStream() {
+	stream1 := this.openStream(messaging.StreamConfig{EstablishTopology: true, StreamName: "queue1", Topics: []string{"topic1"}})
+	stream2 := this.openStream(messaging.StreamConfig{EstablishTopology: true, StreamName: "queue2", Topics: []string{"topic1", "topic2"}})
+
+	writer := this.openCommitWriter()
+	_, _ = writer.Write(this.ctx, messaging.Dispatch{Topic: "topic1", MessageID: 1}, messaging.Dispatch{Topic: "topic2", MessageID: 2})
+	err := writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.read(stream1).MessageID, should.Equal, 1)
+	this.So(this.read(stream2).MessageID, should.Equal, 1)
+	this.So(this.read(stream2).MessageID, should.Equal, 2)
+}
+func (this *ConnectorFixture) TestWhenAvailableTopicsDeclared_WritesSucceedWithoutBoundQueues() {
+	_ = this.openStream(messaging.StreamConfig{EstablishTopology: true, StreamName: "queue", AvailableTopics: []string{"other"}})
+
+	writer := this.openCommitWriter()
+	_, _ = writer.Write(this.ctx, messaging.Dispatch{Topic: "other"})
+
+	this.So(writer.Commit(), should.BeNil)
+}
+func (this *ConnectorFixture) TestWhenStreamDoesNotEstablishTopologyForUndeclaredQueue_ReturnError() {
+	connection, _ := this.connector.Connect(this.ctx)
+	reader, _ := connection.Reader(this.ctx)
+
+	stream, err := reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrUnknownStream)
+}
+func (this *ConnectorFixture) TestWhenQueueDeclaredByOption_MessagesRetainedUntilStreamOpened() {
+	this.connector = New(Options.Queue("queue", "topic"))
+	writer := this.openCommitWriter()
+	_, _ = writer.Write(this.ctx, messaging.Dispatch{Topic: "topic", MessageID: 1})
+	_ = writer.Commit()
+
+	stream := this.openStream(messaging.StreamConfig{StreamName: "queue"})
+
+	this.So(this.read(stream).MessageID, should.Equal, 1)
+}
+func (this *ConnectorFixture) TestWhenExclusiveStreamAlreadyOpen_AdditionalStreamsRejected() {
+	connection, _ := this.connector.Connect(this.ctx)
+	reader, _ := connection.Reader(this.ctx)
+	_, _ = reader.Stream(this.ctx, messaging.StreamConfig{EstablishTopology: true, StreamName: "queue", ExclusiveStream: true})
+
+	stream, err := reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrAlreadyExclusive)
+}
+func (this *ConnectorFixture) TestWhenOpeningExclusiveStreamAlongsideOthers_ReturnError() {
+	connection, _ := this.connector.Connect(this.ctx)
+	reader, _ := connection.Reader(this.ctx)
+	_, _ = reader.Stream(this.ctx, messaging.StreamConfig{EstablishTopology: true, StreamName: "queue"})
+
+	stream, err := reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue", ExclusiveStream: true})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMultipleStreams)
+}
+func (this *ConnectorFixture) TestWhenConnectorClosed_UnacknowledgedDeliveriesAreRedeliveredToNewConnections() {
+	stream := this.openStream(messaging.StreamConfig{EstablishTopology: true, StreamName: "queue", Topics: []string{"topic"}})
+	writer := this.openCommitWriter()
+	_, _ = writer.Write(this.ctx, messaging.Dispatch{Topic: "topic", MessageID: 1})
+	_ = writer.Commit()
+	_ = this.read(stream)
+
+	_ = this.connector.Close()
+
+	this.So(this.read(this.openStream(messaging.StreamConfig{StreamName: "queue"})).MessageID, should.Equal, 1)
+}
+func (this *ConnectorFixture) TestWhenConnectionClosed_NewResourcesRejected() {
+	connection, _ := this.connector.Connect(this.ctx)
+	_ = connection.Close()
+
+	reader, readerErr := connection.Reader(this.ctx)
+	writer, writerErr := connection.Writer(this.ctx)
+
+	this.So(reader, should.BeNil)
+	this.So(readerErr, should.Equal, ErrClosed)
+	this.So(writer, should.BeNil)
+	this.So(writerErr, should.Equal, ErrClosed)
+}
+
+func (this *ConnectorFixture) openStream(config messaging.StreamConfig) messaging.Stream {
+	connection, _ := this.connector.Connect(this.ctx)
+	reader, _ := connection.Reader(this.ctx)
+	stream, err := reader.Stream(this.ctx, config)
+	this.So(err, should.BeNil)
+	return stream
+}
+func (this *ConnectorFixture) openCommitWriter() messaging.CommitWriter {
+	connection, _ := this.connector.Connect(this.ctx)
+	writer, err := connection.CommitWriter(this.ctx)
+	this.So(err, should.BeNil)
+	return writer
+}
+func (this *ConnectorFixture) read(stream messaging.Stream) (delivery messaging.Delivery) {
+	this.So(stream.Read(this.ctx, &delivery), should.BeNil)
+	return delivery
+}
@@ -0,0 +1,16 @@
+package memory
+
+import "errors"
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+var (
+	ErrAlreadyExclusive = errors.New("unable to open additional stream, an exclusive stream already exists")
+	ErrMultipleStreams  = errors.New("unable to open exclusive stream, another stream already exists")
+	ErrUnknownTopic     = errors.New("the destination topic has not been declared with the broker")
+	ErrUnknownStream    = errors.New("the stream (queue) has not been declared with the broker")
+	ErrUnknownDelivery  = errors.New("the delivery is not outstanding on the stream and cannot be acknowledged")
+	ErrClosed           = errors.New("the resource has already been closed")
+)
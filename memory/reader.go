@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultReader struct {
+	broker  *broker
+	config  configuration
+	streams []io.Closer
+	closed  bool
+	mutex   sync.Mutex
+	logger  logger
+
+	hasExclusiveStream bool
+}
+
+func newReader(broker *broker, config configuration) messaging.Reader {
+	return &defaultReader{broker: broker, config: config, logger: config.Logger}
+}
+func (this *defaultReader) Stream(_ context.Context, settings messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+	if this.hasExclusiveStream {
+		return nil, ErrAlreadyExclusive
+	}
+	if settings.ExclusiveStream && len(this.streams) > 0 {
+		return nil, ErrMultipleStreams
+	}
+
+	this.establishTopology(settings)
+	if !this.broker.QueueExists(settings.StreamName) {
+		this.logger.Printf("[WARN] Unable to open consumer for stream [%s]: %s", settings.StreamName, ErrUnknownStream)
+		return nil, ErrUnknownStream
+	}
+
+	this.logger.Printf("[INFO] Consumer opened for queue [%s], awaiting messages...", settings.StreamName)
+	stream := newStream(this.broker, settings, this.config)
+	this.streams = append(this.streams, stream)
+	this.hasExclusiveStream = this.hasExclusiveStream || settings.ExclusiveStream
+	return stream, nil
+}
+func (this *defaultReader) establishTopology(config messaging.StreamConfig) {
+	if !config.EstablishTopology {
+		return
+	}
+
+	this.broker.DeclareQueue(config.StreamName)
+
+	for _, topic := range config.Topics {
+		this.broker.DeclareTopic(topic)
+		this.broker.BindQueue(config.StreamName, topic)
+	}
+
+	for _, topic := range config.AvailableTopics {
+		if len(topic) > 0 {
+			this.broker.DeclareTopic(topic)
+		}
+	}
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
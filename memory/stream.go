@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultStream struct {
+	broker     *broker
+	streamName string
+	capacity   int
+	batchAck   bool
+	logger     logger
+
+	mutex          sync.Mutex
+	closed         chan struct{}
+	counter        uint64
+	unacknowledged []outstanding
+}
+type outstanding struct {
+	DeliveryID uint64
+	Envelope   envelope
+}
+
+func newStream(broker *broker, config messaging.StreamConfig, settings configuration) messaging.Stream {
+	return &defaultStream{
+		broker:     broker,
+		streamName: config.StreamName,
+		capacity:   int(config.BufferCapacity),
+		batchAck:   config.ExclusiveStream,
+		logger:     settings.Logger,
+		closed:     make(chan struct{}),
+	}
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	for {
+		changed, err := this.tryRead(target)
+		if changed == nil {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-this.closed:
+			return io.EOF
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// tryRead receives the next message, if any, and otherwise returns a channel on which to wait for broker activity.
+func (this *defaultStream) tryRead(target *messaging.Delivery) (<-chan struct{}, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.isClosed() {
+		return nil, io.EOF
+	}
+
+	if this.capacity > 0 && len(this.unacknowledged) >= this.capacity {
+		return this.broker.Changed(), nil // prefetch limit reached, wait for an acknowledgement
+	}
+
+	item, received, changed := this.broker.Receive(this.streamName)
+	if !received {
+		return changed, nil
+	}
+
+	this.counter++
+	this.unacknowledged = append(this.unacknowledged, outstanding{DeliveryID: this.counter, Envelope: item})
+	this.processDelivery(item, target)
+	return nil, nil
+}
+func (this *defaultStream) processDelivery(source envelope, target *messaging.Delivery) {
+	dispatch := source.Dispatch
+
+	target.Upstream = dispatch
+	target.DeliveryID = this.counter
+	target.SourceID = dispatch.SourceID
+	target.MessageID = dispatch.MessageID
+	target.CorrelationID = dispatch.CorrelationID
+	target.Timestamp = dispatch.Timestamp
+	target.Durable = dispatch.Durable
+	target.Topic = this.streamName
+	target.Partition = dispatch.Partition
+	target.Redelivered = source.Redelivered
+	target.Attempt = source.Attempt
+	target.Priority = dispatch.Priority
+	target.MessageType = dispatch.MessageType
+	target.ContentType = dispatch.ContentType
+	target.ContentEncoding = dispatch.ContentEncoding
+	target.Headers = dispatch.Headers
+	target.Payload = dispatch.Payload
+}
+
+func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.isClosed() {
+		return ErrClosed
+	}
+
+	length := len(deliveries)
+	if length > 1 && this.batchAck {
+		deliveries = deliveries[length-1:] // only ack the last one
+	}
+
+	for _, delivery := range deliveries {
+		if !this.acknowledge(delivery.DeliveryID) {
+			this.logger.Printf("[WARN] Unable to acknowledge delivery [%d] on stream [%s].", delivery.DeliveryID, this.streamName)
+			return ErrUnknownDelivery
+		}
+	}
+
+	this.broker.Notify() // prefetch capacity may have been freed
+	return nil
+}
+func (this *defaultStream) acknowledge(deliveryID uint64) bool {
+	for i, item := range this.unacknowledged {
+		if item.DeliveryID != deliveryID {
+			continue
+		}
+
+		if this.batchAck {
+			this.unacknowledged = append(this.unacknowledged[0:0], this.unacknowledged[i+1:]...)
+		} else {
+			this.unacknowledged = append(this.unacknowledged[:i], this.unacknowledged[i+1:]...)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (this *defaultStream) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.isClosed() {
+		return nil
+	}
+
+	close(this.closed)
+
+	requeued := make([]envelope, 0, len(this.unacknowledged))
+	for _, item := range this.unacknowledged {
+		requeued = append(requeued, item.Envelope)
+	}
+	this.unacknowledged = nil
+
+	this.broker.Requeue(this.streamName, requeued)
+	return nil
+}
+func (this *defaultStream) isClosed() bool {
+	select {
+	case <-this.closed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestStreamFixture(t *testing.T) {
+	gunit.Run(new(StreamFixture), t)
+}
+
+type StreamFixture struct {
+	*gunit.Fixture
+
+	now    time.Time
+	config configuration
+	broker *broker
+	stream messaging.Stream
+}
+
+func (this *StreamFixture) Setup() {
+	this.now = time.Now().UTC()
+	Options.apply(
+		Options.Now(func() time.Time { return this.now }),
+		Options.Queue("queue", "topic"),
+	)(&this.config)
+	this.broker = newBroker(this.config)
+	this.initializeStream(messaging.StreamConfig{StreamName: "queue"})
+}
+func (this *StreamFixture) initializeStream(settings messaging.StreamConfig) {
+	this.stream = newStream(this.broker, settings, this.config)
+}
+
+func (this *StreamFixture) TestWhenReading_PopulateDeliveryFromDispatch() {
+	this.publish(messaging.Dispatch{
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Timestamp:       this.now,
+		Durable:         true,
+		Topic:           "topic",
+		Partition:       4,
+		Priority:        5,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Payload:         []byte("payload"),
+		Headers:         map[string]any{"header": "value"},
+	})
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(context.Background(), &delivery)
+
+	this.So(err, should.BeNil)
+	delivery.Upstream = nil
+	this.So(delivery, should.Equal, messaging.Delivery{
+		DeliveryID:      1,
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Timestamp:       this.now,
+		Durable:         true,
+		Topic:           "queue",
+		Partition:       4,
+		Attempt:         1,
+		Priority:        5,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Payload:         []byte("payload"),
+		Headers:         map[string]any{"header": "value"},
+	})
+}
+func (this *StreamFixture) TestWhenReadingWithoutMessages_BlockUntilContextCancelled() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(ctx, &delivery)
+
+	this.So(err, should.Equal, context.DeadlineExceeded)
+}
+func (this *StreamFixture) TestWhenMessagePublishedWhileWaiting_ReadReturnsIt() {
+	go func() {
+		time.Sleep(time.Millisecond)
+		this.publish(messaging.Dispatch{Topic: "topic", MessageID: 42})
+	}()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(context.Background(), &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery.MessageID, should.Equal, 42)
+}
+func (this *StreamFixture) TestWhenStreamClosedWhileWaiting_ReturnEOF() {
+	go func() {
+		time.Sleep(time.Millisecond)
+		_ = this.stream.Close()
+	}()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(context.Background(), &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+}
+func (this *StreamFixture) TestWhenMessageExpired_SkipIt() {
+	this.publish(
+		messaging.Dispatch{Topic: "topic", MessageID: 1, Expiration: time.Second},
+		messaging.Dispatch{Topic: "topic", MessageID: 2},
+	)
+	this.now = this.now.Add(time.Second)
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(context.Background(), &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery.MessageID, should.Equal, 2)
+}
+func (this *StreamFixture) TestWhenBufferCapacityReached_WaitForAcknowledgement() {
+	this.initializeStream(messaging.StreamConfig{StreamName: "queue", BufferCapacity: 1})
+	this.publish(messaging.Dispatch{Topic: "topic", MessageID: 1}, messaging.Dispatch{Topic: "topic", MessageID: 2})
+
+	var first, second messaging.Delivery
+	_ = this.stream.Read(context.Background(), &first)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	blockedErr := this.stream.Read(ctx, &second)
+
+	ackErr := this.stream.Acknowledge(context.Background(), first)
+	secondErr := this.stream.Read(context.Background(), &second)
+
+	this.So(blockedErr, should.Equal, context.DeadlineExceeded)
+	this.So(ackErr, should.BeNil)
+	this.So(secondErr, should.BeNil)
+	this.So(second.MessageID, should.Equal, 2)
+}
+func (this *StreamFixture) TestWhenAcknowledgingUnknownDelivery_ReturnError() {
+	err := this.stream.Acknowledge(context.Background(), messaging.Delivery{DeliveryID: 1})
+
+	this.So(err, should.Equal, ErrUnknownDelivery)
+}
+func (this *StreamFixture) TestWhenAcknowledgingWithCancelledContext_ReturnError() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := this.stream.Acknowledge(ctx, messaging.Delivery{DeliveryID: 1})
+
+	this.So(err, should.Equal, context.Canceled)
+}
+func (this *StreamFixture) TestWhenClosedWithUnacknowledgedDeliveries_RequeueInOriginalOrder() {
+	this.publish(
+		messaging.Dispatch{Topic: "topic", MessageID: 1},
+		messaging.Dispatch{Topic: "topic", MessageID: 2},
+		messaging.Dispatch{Topic: "topic", MessageID: 3},
+	)
+	first := this.read()
+	_ = this.read()
+	_ = this.stream.Acknowledge(context.Background(), first)
+
+	_ = this.stream.Close()
+
+	this.initializeStream(messaging.StreamConfig{StreamName: "queue"})
+	this.So(this.read().MessageID, should.Equal, 2)
+	this.So(this.read().MessageID, should.Equal, 3)
+}
+func (this *StreamFixture) TestWhenUnacknowledgedDeliveriesRequeued_MarkRedeliveredAndCountAttempts() {
+	this.publish(messaging.Dispatch{Topic: "topic", MessageID: 1}, messaging.Dispatch{Topic: "topic", MessageID: 2})
+	original := this.read()
+
+	_ = this.stream.Close()
+	this.initializeStream(messaging.StreamConfig{StreamName: "queue"})
+	redelivered := this.read()
+	_ = this.stream.Close()
+	this.initializeStream(messaging.StreamConfig{StreamName: "queue"})
+	again := this.read()
+	other := this.read()
+
+	this.So(original.Redelivered, should.BeFalse)
+	this.So(original.Attempt, should.Equal, 1)
+	this.So(redelivered.MessageID, should.Equal, 1)
+	this.So(redelivered.Redelivered, should.BeTrue)
+	this.So(redelivered.Attempt, should.Equal, 2)
+	this.So(again.Redelivered, should.BeTrue)
+	this.So(again.Attempt, should.Equal, 3)
+	this.So(other.MessageID, should.Equal, 2)
+	this.So(other.Redelivered, should.BeFalse)
+	this.So(other.Attempt, should.Equal, 1)
+}
+func (this *StreamFixture) TestWhenExclusiveStreamAcknowledgesLastDelivery_AllPriorDeliveriesAcknowledged() {
+	this.initializeStream(messaging.StreamConfig{StreamName: "queue", ExclusiveStream: true})
+	this.publish(
+		messaging.Dispatch{Topic: "topic", MessageID: 1},
+		messaging.Dispatch{Topic: "topic", MessageID: 2},
+		messaging.Dispatch{Topic: "topic", MessageID: 3},
+	)
+	first, second := this.read(), this.read()
+	_ = this.read()
+
+	err := this.stream.Acknowledge(context.Background(), first, second)
+	_ = this.stream.Close()
+
+	this.So(err, should.BeNil)
+	this.initializeStream(messaging.StreamConfig{StreamName: "queue"})
+	this.So(this.read().MessageID, should.Equal, 3)
+}
+func (this *StreamFixture) TestWhenClosedMultipleTimes_NoError() {
+	this.So(this.stream.Close(), should.BeNil)
+	this.So(this.stream.Close(), should.BeNil)
+}
+
+func (this *StreamFixture) publish(dispatches ...messaging.Dispatch) {
+	this.So(this.broker.Publish(dispatches), should.BeNil)
+}
+func (this *StreamFixture) read() (delivery messaging.Delivery) {
+	this.So(this.stream.Read(context.Background(), &delivery), should.BeNil)
+	return delivery
+}
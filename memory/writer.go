@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultWriter struct {
+	broker        *broker
+	transactional bool
+	now           func() time.Time
+	logger        logger
+
+	mutex  sync.Mutex
+	buffer []messaging.Dispatch
+	closed bool
+}
+
+func newWriter(broker *broker, transactional bool, config configuration) messaging.CommitWriter {
+	return &defaultWriter{
+		broker:        broker,
+		transactional: transactional,
+		now:           config.Now,
+		logger:        config.Logger,
+	}
+}
+
+func (this *defaultWriter) Write(ctx context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return 0, ErrClosed
+	}
+
+	now := this.now().UTC()
+	converted := make([]messaging.Dispatch, 0, len(dispatches))
+	for _, dispatch := range dispatches {
+		if len(dispatch.Topic) == 0 {
+			return 0, messaging.ErrEmptyDispatchTopic
+		}
+
+		converted = append(converted, toEnqueuedDispatch(dispatch, now))
+	}
+
+	if this.transactional {
+		this.buffer = append(this.buffer, converted...)
+		return len(converted), nil
+	}
+
+	if err := this.broker.Publish(converted); err != nil {
+		this.logger.Printf("[WARN] Unable to write dispatch to broker [%s].", err)
+		return 0, err
+	}
+
+	return len(converted), nil
+}
+
+// toEnqueuedDispatch copies the dispatch such that later modifications by the caller aren't observed by consumers.
+func toEnqueuedDispatch(dispatch messaging.Dispatch, now time.Time) messaging.Dispatch {
+	if dispatch.Timestamp.IsZero() {
+		dispatch.Timestamp = now
+	}
+
+	if dispatch.Payload != nil {
+		dispatch.Payload = append([]byte(nil), dispatch.Payload...)
+	}
+
+	if dispatch.Headers != nil {
+		headers := make(map[string]any, len(dispatch.Headers))
+		for key, value := range dispatch.Headers {
+			headers[key] = value
+		}
+		dispatch.Headers = headers
+	}
+
+	dispatch.Message = nil // like any other transport, only the payload crosses the wire
+	return dispatch
+}
+
+func (this *defaultWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return ErrClosed
+	}
+
+	err := this.broker.Publish(this.buffer)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to commit writer transaction [%s].", err)
+	}
+
+	this.clearBuffer()
+	return err
+}
+func (this *defaultWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return ErrClosed
+	}
+
+	this.clearBuffer()
+	return nil
+}
+func (this *defaultWriter) clearBuffer() {
+	for i := range this.buffer {
+		this.buffer[i] = messaging.Dispatch{} // clear it out to avoid a memory leak
+	}
+
+	this.buffer = this.buffer[0:0]
+}
+
+func (this *defaultWriter) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	this.buffer = nil
+	return nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	now    time.Time
+	config configuration
+	broker *broker
+}
+
+func (this *WriterFixture) Setup() {
+	this.now = time.Now().UTC()
+	Options.apply(
+		Options.Now(func() time.Time { return this.now }),
+		Options.Queue("queue1", "topic"),
+		Options.Queue("queue2", "topic"),
+		Options.Topics("unbound"),
+	)(&this.config)
+	this.broker = newBroker(this.config)
+}
+
+func (this *WriterFixture) TestWhenWriteWithoutTopic_ReturnError() {
+	writer := newWriter(this.broker, false, this.config)
+
+	count, err := writer.Write(context.Background(), messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+}
+func (this *WriterFixture) TestWhenWriteToUndeclaredTopic_ReturnError() {
+	writer := newWriter(this.broker, false, this.config)
+
+	count, err := writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"}, messaging.Dispatch{Topic: "missing"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, ErrUnknownTopic)
+	this.So(this.receive("queue1"), should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWrite_FanOutToEveryBoundQueue() {
+	writer := newWriter(this.broker, false, this.config)
+
+	count, err := writer.Write(context.Background(),
+		messaging.Dispatch{Topic: "topic", MessageID: 1, Payload: []byte("1")},
+		messaging.Dispatch{Topic: "unbound", MessageID: 2},
+		messaging.Dispatch{Topic: "topic", MessageID: 3, Message: "discarded"},
+	)
+
+	this.So(count, should.Equal, 3)
+	this.So(err, should.BeNil)
+
+	expected := []messaging.Dispatch{
+		{Topic: "topic", MessageID: 1, Payload: []byte("1"), Timestamp: this.now},
+		{Topic: "topic", MessageID: 3, Timestamp: this.now},
+	}
+	this.So(this.receive("queue1"), should.Equal, expected)
+	this.So(this.receive("queue2"), should.Equal, expected)
+}
+func (this *WriterFixture) TestWhenWrite_PayloadAndHeadersAreCopied() {
+	writer := newWriter(this.broker, false, this.config)
+	dispatch := messaging.Dispatch{Topic: "topic", Payload: []byte("a"), Headers: map[string]any{"key": "a"}}
+
+	_, _ = writer.Write(context.Background(), dispatch)
+	dispatch.Payload[0] = 'b'
+	dispatch.Headers["key"] = "b"
+
+	received := this.receive("queue1")
+	this.So(received[0].Payload, should.Equal, []byte("a"))
+	this.So(received[0].Headers, should.Equal, map[string]any{"key": "a"})
+}
+func (this *WriterFixture) TestWhenTransactionalWrite_NothingPublishedUntilCommit() {
+	writer := newWriter(this.broker, true, this.config)
+
+	count, err := writer.Write(context.Background(), messaging.Dispatch{Topic: "topic", MessageID: 1})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.receive("queue1"), should.BeEmpty)
+
+	err = writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.receive("queue1"), should.Equal, []messaging.Dispatch{{Topic: "topic", MessageID: 1, Timestamp: this.now}})
+}
+func (this *WriterFixture) TestWhenTransactionalWriteToUndeclaredTopic_CommitFails() {
+	writer := newWriter(this.broker, true, this.config)
+
+	_, writeErr := writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"}, messaging.Dispatch{Topic: "missing"})
+	commitErr := writer.Commit()
+
+	this.So(writeErr, should.BeNil)
+	this.So(commitErr, should.Equal, ErrUnknownTopic)
+	this.So(this.receive("queue1"), should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenRollback_BufferedDispatchesDiscarded() {
+	writer := newWriter(this.broker, true, this.config)
+	_, _ = writer.Write(context.Background(), messaging.Dispatch{Topic: "topic", MessageID: 1})
+
+	rollbackErr := writer.Rollback()
+	commitErr := writer.Commit()
+
+	this.So(rollbackErr, should.BeNil)
+	this.So(commitErr, should.BeNil)
+	this.So(this.receive("queue1"), should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenClosed_FurtherOperationsFail() {
+	writer := newWriter(this.broker, true, this.config)
+	_ = writer.Close()
+
+	_, writeErr := writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(writeErr, should.Equal, ErrClosed)
+	this.So(writer.Commit(), should.Equal, ErrClosed)
+	this.So(writer.Rollback(), should.Equal, ErrClosed)
+}
+func (this *WriterFixture) TestWhenContextCancelled_ReturnError() {
+	writer := newWriter(this.broker, false, this.config)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	count, err := writer.Write(ctx, messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, context.Canceled)
+}
+
+func (this *WriterFixture) receive(queue string) (results []messaging.Dispatch) {
+	for {
+		item, received, _ := this.broker.Receive(queue)
+		if !received {
+			return results
+		}
+
+		results = append(results, item.Dispatch)
+	}
+}
@@ -0,0 +1,46 @@
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func New() Connector { return natsConnector{} }
+
+type Config struct {
+	Address    string
+	ClientName string
+	TLSConfig  *tls.Config
+}
+
+type ConsumerConfig struct {
+	Durable        string
+	FilterSubjects []string
+	StartSequence  uint64
+	MaxAckPending  int
+}
+
+type Connector interface {
+	Connect(ctx context.Context, config Config) (Connection, error)
+}
+
+type Connection interface {
+	// DeclareStream creates the JetStream stream, if necessary, and ensures it captures each of the subjects provided in
+	// addition to any subjects it already captures.
+	DeclareStream(ctx context.Context, name string, subjects []string) error
+	DeclareConsumer(ctx context.Context, stream string, config ConsumerConfig) error
+
+	Consume(ctx context.Context, stream, consumer string, bufferCapacity int) (Consumer, error)
+	Publish(ctx context.Context, message *nats.Msg) error
+
+	io.Closer
+}
+
+type Consumer interface {
+	Deliveries() <-chan jetstream.Msg
+	io.Closer
+}
@@ -0,0 +1,163 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type natsConnector struct{}
+
+func (this natsConnector) Connect(_ context.Context, config Config) (Connection, error) {
+	options := []nats.Option{nats.Name(config.ClientName)}
+	if config.TLSConfig != nil {
+		options = append(options, nats.Secure(config.TLSConfig))
+	}
+
+	connection, err := nats.Connect(config.Address, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := jetstream.New(connection)
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	return natsConnection{Conn: connection, stream: stream}, nil
+}
+
+type natsConnection struct {
+	*nats.Conn
+	stream jetstream.JetStream
+}
+
+func (this natsConnection) DeclareStream(ctx context.Context, name string, subjects []string) error {
+	existing, err := this.stream.Stream(ctx, name)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		_, err = this.stream.CreateStream(ctx, jetstream.StreamConfig{Name: name, Subjects: subjects, Storage: jetstream.FileStorage})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	config := existing.CachedInfo().Config
+	merged, changed := mergeSubjects(config.Subjects, subjects)
+	if !changed {
+		return nil
+	}
+
+	config.Subjects = merged
+	_, err = this.stream.UpdateStream(ctx, config)
+	return err
+}
+func mergeSubjects(existing, additional []string) ([]string, bool) {
+	contains := make(map[string]struct{}, len(existing))
+	for _, subject := range existing {
+		contains[subject] = struct{}{}
+	}
+
+	merged := append([]string{}, existing...)
+	for _, subject := range additional {
+		if _, found := contains[subject]; !found && len(subject) > 0 {
+			contains[subject] = struct{}{}
+			merged = append(merged, subject)
+		}
+	}
+
+	return merged, len(merged) > len(existing)
+}
+
+func (this natsConnection) DeclareConsumer(ctx context.Context, stream string, config ConsumerConfig) error {
+	consumer := jetstream.ConsumerConfig{
+		Durable:        config.Durable,
+		FilterSubjects: config.FilterSubjects,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		DeliverPolicy:  jetstream.DeliverAllPolicy,
+		MaxAckPending:  config.MaxAckPending,
+	}
+
+	if config.StartSequence > 0 {
+		consumer.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		consumer.OptStartSeq = config.StartSequence
+	}
+
+	_, err := this.stream.CreateOrUpdateConsumer(ctx, stream, consumer)
+	return err
+}
+
+func (this natsConnection) Consume(ctx context.Context, stream, consumer string, bufferCapacity int) (Consumer, error) {
+	durable, err := this.stream.Consumer(ctx, stream, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []jetstream.PullMessagesOpt
+	if bufferCapacity > 0 {
+		options = append(options, jetstream.PullMaxMessages(bufferCapacity))
+	}
+
+	messages, err := durable.Messages(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNATSConsumer(messages, bufferCapacity), nil
+}
+
+func (this natsConnection) Publish(ctx context.Context, message *nats.Msg) error {
+	_, err := this.stream.PublishMsg(ctx, message)
+	return err
+}
+
+func (this natsConnection) Close() error {
+	this.Conn.Close()
+	return nil
+}
+
+// natsConsumer pumps messages from the JetStream iterator into a channel, which is closed when the iterator concludes,
+// such that callers can wait for a message while also observing cancellation.
+type natsConsumer struct {
+	messages   jetstream.MessagesContext
+	deliveries chan jetstream.Msg
+	done       chan struct{}
+	closer     sync.Once
+}
+
+func newNATSConsumer(messages jetstream.MessagesContext, bufferCapacity int) Consumer {
+	this := &natsConsumer{
+		messages:   messages,
+		deliveries: make(chan jetstream.Msg, bufferCapacity),
+		done:       make(chan struct{}),
+	}
+	go this.pump()
+	return this
+}
+func (this *natsConsumer) pump() {
+	defer close(this.deliveries)
+
+	for {
+		message, err := this.messages.Next()
+		if err != nil {
+			return
+		}
+
+		select {
+		case this.deliveries <- message:
+		case <-this.done:
+			return
+		}
+	}
+}
+func (this *natsConsumer) Deliveries() <-chan jetstream.Msg { return this.deliveries }
+func (this *natsConsumer) Close() error {
+	this.closer.Do(func() {
+		close(this.done)
+		this.messages.Stop()
+	})
+	return nil
+}
@@ -0,0 +1,54 @@
+package nats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/nats/adapter"
+)
+
+type defaultConnector struct {
+	inner  adapter.Connector
+	config configuration
+	logger logger
+
+	active []messaging.Connection
+	mutex  sync.Mutex
+}
+
+func newConnector(config configuration) messaging.Connector {
+	return &defaultConnector{inner: config.Connector, config: config, logger: config.Logger}
+}
+
+func (this *defaultConnector) Connect(ctx context.Context) (messaging.Connection, error) {
+	this.logger.Printf("[INFO] Establishing NATS connection to [%s]...", this.config.Address)
+	inner, err := this.inner.Connect(ctx, adapter.Config{
+		Address:    this.config.Address,
+		ClientName: this.config.ClientName,
+		TLSConfig:  this.config.TLSConfig,
+	})
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to connect [%s].", err)
+		return nil, err
+	}
+
+	this.logger.Printf("[INFO] Established NATS connection to [%s].", this.config.Address)
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.active = append(this.active, newConnection(inner, this.config))
+	return this.active[len(this.active)-1], nil
+}
+
+func (this *defaultConnector) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i := range this.active {
+		_ = this.active[i].Close()
+		this.active[i] = nil
+	}
+	this.active = this.active[0:0]
+
+	return nil
+}
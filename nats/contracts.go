@@ -0,0 +1,25 @@
+package nats
+
+import "errors"
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+var (
+	ErrMissingStreamName = errors.New("the stream name (durable consumer name) is required")
+	ErrUnknownDelivery   = errors.New("the delivery is not outstanding on the stream and cannot be acknowledged")
+	ErrClosed            = errors.New("the resource has already been closed")
+)
+
+// The message headers which carry the properties of a dispatch. The message ID is carried in the standard JetStream
+// de-duplication header such that duplicate publications within the stream's duplicate window are discarded.
+const (
+	HeaderMessageID       = "Nats-Msg-Id"
+	HeaderSourceID        = "Source-Id"
+	HeaderCorrelationID   = "Correlation-Id"
+	HeaderMessageType     = "Message-Type"
+	HeaderContentType     = "Content-Type"
+	HeaderContentEncoding = "Content-Encoding"
+	HeaderTimestamp       = "Message-Timestamp"
+)
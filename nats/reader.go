@@ -0,0 +1,91 @@
+package nats
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/nats/adapter"
+)
+
+type defaultReader struct {
+	inner   adapter.Connection
+	config  configuration
+	streams []io.Closer
+	closed  bool
+	mutex   sync.Mutex
+	logger  logger
+}
+
+func newReader(inner adapter.Connection, config configuration) messaging.Reader {
+	return &defaultReader{inner: inner, config: config, logger: config.Logger}
+}
+
+// Stream consumes a durable JetStream consumer of the configured JetStream stream. The consumer is named by GroupName
+// or, when empty, by StreamName; every stream opened with the same name shares the consumer and its acknowledgements.
+// When EstablishTopology is specified, the JetStream stream is extended to capture the Topics and AvailableTopics and
+// the consumer is created, filtered to the Topics, and starting at Sequence if specified.
+func (this *defaultReader) Stream(ctx context.Context, settings messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	durable := settings.GroupName
+	if len(durable) == 0 {
+		durable = settings.StreamName
+	}
+	if len(durable) == 0 {
+		return nil, ErrMissingStreamName
+	}
+
+	if err := this.establishTopology(ctx, durable, settings); err != nil {
+		this.logger.Printf("[WARN] Unable to establish topology for consumer [%s]: %s", durable, err)
+		return nil, err
+	}
+
+	inner, err := this.inner.Consume(ctx, this.config.Stream, durable, int(settings.BufferCapacity))
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to open consumer [%s] of stream [%s]: %s", durable, this.config.Stream, err)
+		return nil, err
+	}
+
+	this.logger.Printf("[INFO] Consumer [%s] opened on stream [%s], awaiting messages...", durable, this.config.Stream)
+	stream := newStream(inner, this.config)
+	this.streams = append(this.streams, stream)
+	return stream, nil
+}
+func (this *defaultReader) establishTopology(ctx context.Context, durable string, settings messaging.StreamConfig) error {
+	if !settings.EstablishTopology {
+		return nil
+	}
+
+	subjects := append(append([]string{}, settings.Topics...), settings.AvailableTopics...)
+	if err := this.inner.DeclareStream(ctx, this.config.Stream, subjects); err != nil {
+		return err
+	}
+
+	return this.inner.DeclareConsumer(ctx, this.config.Stream, adapter.ConsumerConfig{
+		Durable:        durable,
+		FilterSubjects: settings.Topics,
+		StartSequence:  settings.Sequence,
+		MaxAckPending:  int(settings.BufferCapacity),
+	})
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
@@ -0,0 +1,156 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/nats/adapter"
+)
+
+func TestReaderFixture(t *testing.T) {
+	gunit.Run(new(ReaderFixture), t)
+}
+
+type ReaderFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	reader messaging.Reader
+
+	declareStreamName     string
+	declareStreamSubjects []string
+	declareStreamError    error
+	declareConsumerStream string
+	declareConsumerConfig adapter.ConsumerConfig
+	declareConsumerError  error
+
+	consumeStream   string
+	consumeConsumer string
+	consumeCapacity int
+	consumeError    error
+	consumerClosed  int
+}
+
+func (this *ReaderFixture) Setup() {
+	this.ctx = context.Background()
+	config := configuration{}
+	Options.apply(Options.Connector(nil), Options.Stream("EVENTS"))(&config)
+	this.reader = newReader(this, config)
+}
+
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutName_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingStreamName)
+}
+func (this *ReaderFixture) TestWhenOpeningStream_ConsumeDurableConsumerNamedByStreamName() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue", BufferCapacity: 8})
+
+	this.So(stream, should.NotBeNil)
+	this.So(err, should.BeNil)
+	this.So(this.consumeStream, should.Equal, "EVENTS")
+	this.So(this.consumeConsumer, should.Equal, "queue")
+	this.So(this.consumeCapacity, should.Equal, 8)
+	this.So(this.declareStreamName, should.BeEmpty)
+	this.So(this.declareConsumerStream, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenOpeningStreamWithGroupName_ConsumeDurableConsumerNamedByGroup() {
+	_, _ = this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue", GroupName: "group"})
+
+	this.So(this.consumeConsumer, should.Equal, "group")
+}
+func (this *ReaderFixture) TestWhenEstablishingTopology_DeclareStreamSubjectsAndConsumer() {
+	_, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		BufferCapacity:    8,
+		Topics:            []string{"a", "b"},
+		AvailableTopics:   []string{"c"},
+		Sequence:          42,
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareStreamName, should.Equal, "EVENTS")
+	this.So(this.declareStreamSubjects, should.Equal, []string{"a", "b", "c"})
+	this.So(this.declareConsumerStream, should.Equal, "EVENTS")
+	this.So(this.declareConsumerConfig, should.Equal, adapter.ConsumerConfig{
+		Durable:        "queue",
+		FilterSubjects: []string{"a", "b"},
+		StartSequence:  42,
+		MaxAckPending:  8,
+	})
+}
+func (this *ReaderFixture) TestWhenDeclaringStreamFails_ReturnError() {
+	this.declareStreamError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue", EstablishTopology: true})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.declareStreamError)
+	this.So(this.declareConsumerStream, should.BeEmpty)
+	this.So(this.consumeConsumer, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenDeclaringConsumerFails_ReturnError() {
+	this.declareConsumerError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue", EstablishTopology: true})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.declareConsumerError)
+	this.So(this.consumeConsumer, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenConsumeFails_ReturnError() {
+	this.consumeError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.consumeError)
+}
+func (this *ReaderFixture) TestWhenClosed_StreamsClosedAndNoFurtherStreamsOpened() {
+	_, _ = this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+
+	err := this.reader.Close()
+	stream, streamErr := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+
+	this.So(err, should.BeNil)
+	this.So(this.consumerClosed, should.Equal, 1)
+	this.So(stream, should.BeNil)
+	this.So(streamErr, should.Equal, ErrClosed)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ReaderFixture) DeclareStream(_ context.Context, name string, subjects []string) error {
+	this.declareStreamName = name
+	this.declareStreamSubjects = subjects
+	return this.declareStreamError
+}
+func (this *ReaderFixture) DeclareConsumer(_ context.Context, stream string, config adapter.ConsumerConfig) error {
+	this.declareConsumerStream = stream
+	this.declareConsumerConfig = config
+	return this.declareConsumerError
+}
+func (this *ReaderFixture) Consume(_ context.Context, stream, consumer string, bufferCapacity int) (adapter.Consumer, error) {
+	this.consumeStream = stream
+	this.consumeConsumer = consumer
+	this.consumeCapacity = bufferCapacity
+	if this.consumeError != nil {
+		return nil, this.consumeError
+	}
+	return this, nil
+}
+func (this *ReaderFixture) Publish(_ context.Context, _ *nats.Msg) error { panic("nop") }
+
+func (this *ReaderFixture) Deliveries() <-chan jetstream.Msg { panic("nop") }
+func (this *ReaderFixture) Close() error {
+	this.consumerClosed++
+	return nil
+}
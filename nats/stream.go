@@ -0,0 +1,146 @@
+package nats
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/nats/adapter"
+)
+
+// defaultStream tracks each message received until it has been acknowledged such that the acknowledgement can be sent
+// on the message itself. Messages not acknowledged when the stream is closed are negatively acknowledged so they are
+// redelivered immediately rather than after the consumer's acknowledgement wait has expired.
+type defaultStream struct {
+	inner  adapter.Consumer
+	logger logger
+
+	mutex    sync.Mutex
+	sequence uint64
+	inflight map[uint64]jetstream.Msg
+	closed   bool
+}
+
+func newStream(inner adapter.Consumer, config configuration) messaging.Stream {
+	return &defaultStream{inner: inner, logger: config.Logger, inflight: make(map[uint64]jetstream.Msg)}
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case source, open := <-this.inner.Deliveries():
+		if !open {
+			return io.EOF
+		}
+
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		if this.closed {
+			_ = source.Nak()
+			return io.EOF
+		}
+
+		this.sequence++
+		this.inflight[this.sequence] = source
+		processDelivery(source, this.sequence, target)
+		return nil
+	}
+}
+func processDelivery(source jetstream.Msg, deliveryID uint64, target *messaging.Delivery) {
+	target.Upstream = source
+	target.DeliveryID = deliveryID
+	target.Durable = true
+	target.Topic = source.Subject()
+	target.Payload = source.Data()
+	target.Headers = nil
+	target.Timestamp = time.Time{}
+
+	if metadata, err := source.Metadata(); err == nil && metadata != nil {
+		target.Timestamp = metadata.Timestamp
+	}
+
+	for key, values := range source.Headers() {
+		if len(values) == 0 {
+			continue
+		}
+
+		value := values[0]
+		switch key {
+		case HeaderSourceID:
+			target.SourceID = parseUint64(value)
+		case HeaderMessageID:
+			target.MessageID = parseUint64(value)
+		case HeaderCorrelationID:
+			target.CorrelationID = parseUint64(value)
+		case HeaderMessageType:
+			target.MessageType = value
+		case HeaderContentType:
+			target.ContentType = value
+		case HeaderContentEncoding:
+			target.ContentEncoding = value
+		case HeaderTimestamp:
+			if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				target.Timestamp = parsed
+			}
+		default:
+			if target.Headers == nil {
+				target.Headers = make(map[string]any, len(source.Headers()))
+			}
+			target.Headers[key] = value
+		}
+	}
+}
+func parseUint64(value string) uint64 {
+	parsed, _ := strconv.ParseUint(value, 10, 64)
+	return parsed
+}
+
+func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, delivery := range deliveries {
+		if _, contains := this.inflight[delivery.DeliveryID]; !contains {
+			return ErrUnknownDelivery
+		}
+	}
+
+	for _, delivery := range deliveries {
+		if err := this.inflight[delivery.DeliveryID].Ack(); err != nil {
+			this.logger.Printf("[WARN] Unable to acknowledge delivery [%s].", err)
+			return err
+		}
+
+		delete(this.inflight, delivery.DeliveryID)
+	}
+
+	return nil
+}
+
+func (this *defaultStream) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil
+	}
+
+	this.closed = true
+	for deliveryID, message := range this.inflight {
+		_ = message.Nak()
+		delete(this.inflight, deliveryID)
+	}
+
+	return this.inner.Close()
+}
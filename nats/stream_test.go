@@ -0,0 +1,208 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestStreamFixture(t *testing.T) {
+	gunit.Run(new(StreamFixture), t)
+}
+
+type StreamFixture struct {
+	*gunit.Fixture
+
+	ctx        context.Context
+	deliveries chan jetstream.Msg
+	stream     messaging.Stream
+
+	closeCount int
+}
+
+func (this *StreamFixture) Setup() {
+	this.ctx = context.Background()
+	this.deliveries = make(chan jetstream.Msg, 16)
+	config := configuration{}
+	Options.apply(Options.Connector(nil))(&config)
+	this.stream = newStream(this, config)
+}
+
+func (this *StreamFixture) TestWhenReading_MapMessageToDelivery() {
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	message := &fakeMessage{
+		subject: "topic",
+		data:    []byte("payload"),
+		stored:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		headers: nats.Header{
+			HeaderSourceID:        {"1"},
+			HeaderMessageID:       {"2"},
+			HeaderCorrelationID:   {"3"},
+			HeaderMessageType:     {"message-type"},
+			HeaderContentType:     {"content-type"},
+			HeaderContentEncoding: {"content-encoding"},
+			HeaderTimestamp:       {timestamp.Format(time.RFC3339Nano)},
+			"key":                 {"value"},
+		},
+	}
+	this.deliveries <- message
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery, should.Equal, messaging.Delivery{
+		Upstream:        jetstream.Msg(message),
+		DeliveryID:      1,
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Timestamp:       timestamp,
+		Durable:         true,
+		Topic:           "topic",
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Headers:         map[string]any{"key": "value"},
+		Payload:         []byte("payload"),
+	})
+}
+func (this *StreamFixture) TestWhenReadingWithoutTimestampHeader_UseStoredTimestamp() {
+	stored := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	this.deliveries <- &fakeMessage{stored: stored}
+
+	var delivery messaging.Delivery
+	_ = this.stream.Read(this.ctx, &delivery)
+
+	this.So(delivery.Timestamp, should.Equal, stored)
+}
+func (this *StreamFixture) TestWhenReadingRepeatedly_DeliveryIDsIncrement() {
+	this.deliveries <- &fakeMessage{}
+	this.deliveries <- &fakeMessage{}
+
+	first, second := this.read(), this.read()
+
+	this.So(first.DeliveryID, should.Equal, 1)
+	this.So(second.DeliveryID, should.Equal, 2)
+}
+func (this *StreamFixture) TestWhenContextCancelled_ReturnError() {
+	ctx, cancel := context.WithCancel(this.ctx)
+	cancel()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(ctx, &delivery)
+
+	this.So(err, should.Equal, context.Canceled)
+}
+func (this *StreamFixture) TestWhenDeliveriesChannelClosed_ReturnEOF() {
+	close(this.deliveries)
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+}
+func (this *StreamFixture) TestWhenAcknowledging_AckEachMessage() {
+	first, second := &fakeMessage{}, &fakeMessage{}
+	this.deliveries <- first
+	this.deliveries <- second
+	deliveries := []messaging.Delivery{this.read(), this.read()}
+
+	err := this.stream.Acknowledge(this.ctx, deliveries...)
+
+	this.So(err, should.BeNil)
+	this.So(first.ackCount, should.Equal, 1)
+	this.So(second.ackCount, should.Equal, 1)
+}
+func (this *StreamFixture) TestWhenAcknowledgingUnknownDelivery_ReturnError() {
+	err := this.stream.Acknowledge(this.ctx, messaging.Delivery{DeliveryID: 1})
+
+	this.So(err, should.Equal, ErrUnknownDelivery)
+}
+func (this *StreamFixture) TestWhenAcknowledgingTwice_ReturnError() {
+	this.deliveries <- &fakeMessage{}
+	delivery := this.read()
+	_ = this.stream.Acknowledge(this.ctx, delivery)
+
+	err := this.stream.Acknowledge(this.ctx, delivery)
+
+	this.So(err, should.Equal, ErrUnknownDelivery)
+}
+func (this *StreamFixture) TestWhenAckFails_ReturnError() {
+	message := &fakeMessage{ackError: errors.New("")}
+	this.deliveries <- message
+
+	err := this.stream.Acknowledge(this.ctx, this.read())
+
+	this.So(err, should.Equal, message.ackError)
+}
+func (this *StreamFixture) TestWhenClosed_NakUnacknowledgedMessagesAndCloseConsumer() {
+	acknowledged, unacknowledged := &fakeMessage{}, &fakeMessage{}
+	this.deliveries <- acknowledged
+	this.deliveries <- unacknowledged
+	_ = this.stream.Acknowledge(this.ctx, this.read())
+	_ = this.read()
+
+	err := this.stream.Close()
+	_ = this.stream.Close()
+
+	this.So(err, should.BeNil)
+	this.So(acknowledged.nakCount, should.Equal, 0)
+	this.So(unacknowledged.nakCount, should.Equal, 1)
+	this.So(this.closeCount, should.Equal, 1)
+}
+
+func (this *StreamFixture) read() (delivery messaging.Delivery) {
+	this.So(this.stream.Read(this.ctx, &delivery), should.BeNil)
+	return delivery
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *StreamFixture) Deliveries() <-chan jetstream.Msg { return this.deliveries }
+func (this *StreamFixture) Close() error {
+	this.closeCount++
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type fakeMessage struct {
+	subject string
+	data    []byte
+	headers nats.Header
+	stored  time.Time
+
+	ackCount int
+	ackError error
+	nakCount int
+}
+
+func (this *fakeMessage) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{Timestamp: this.stored}, nil
+}
+func (this *fakeMessage) Data() []byte         { return this.data }
+func (this *fakeMessage) Headers() nats.Header { return this.headers }
+func (this *fakeMessage) Subject() string      { return this.subject }
+func (this *fakeMessage) Reply() string        { return "" }
+func (this *fakeMessage) Ack() error {
+	this.ackCount++
+	return this.ackError
+}
+func (this *fakeMessage) DoubleAck(_ context.Context) error { panic("nop") }
+func (this *fakeMessage) Nak() error {
+	this.nakCount++
+	return nil
+}
+func (this *fakeMessage) NakWithDelay(_ time.Duration) error { panic("nop") }
+func (this *fakeMessage) InProgress() error                  { panic("nop") }
+func (this *fakeMessage) Term() error                        { panic("nop") }
+func (this *fakeMessage) TermWithReason(_ string) error      { panic("nop") }
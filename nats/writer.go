@@ -0,0 +1,133 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/nats/adapter"
+)
+
+// defaultWriter publishes each dispatch to the subject named by its topic and awaits the JetStream acknowledgement of
+// each publication. JetStream has no transaction spanning multiple publications, so a transactional writer buffers the
+// messages written and publishes them in order upon Commit.
+type defaultWriter struct {
+	inner         adapter.Connection
+	transactional bool
+	now           func() time.Time
+	logger        logger
+
+	mutex  sync.Mutex
+	buffer []*nats.Msg
+}
+
+func newWriter(inner adapter.Connection, transactional bool, config configuration) messaging.CommitWriter {
+	return &defaultWriter{inner: inner, transactional: transactional, now: config.Now, logger: config.Logger}
+}
+
+func (this *defaultWriter) Write(ctx context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	now := this.now().UTC()
+	messages := make([]*nats.Msg, 0, len(dispatches))
+	for _, dispatch := range dispatches {
+		if len(dispatch.Topic) == 0 {
+			return 0, messaging.ErrEmptyDispatchTopic
+		}
+
+		messages = append(messages, toNATSMessage(dispatch, now))
+	}
+
+	if this.transactional {
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		this.buffer = append(this.buffer, messages...)
+		return len(messages), nil
+	}
+
+	return this.publish(ctx, messages)
+}
+func (this *defaultWriter) publish(ctx context.Context, messages []*nats.Msg) (int, error) {
+	for i, message := range messages {
+		if err := this.inner.Publish(ctx, message); err != nil {
+			this.logger.Printf("[WARN] Unable to publish message to subject [%s]: %s", message.Subject, err)
+			return i, err
+		}
+	}
+
+	return len(messages), nil
+}
+func toNATSMessage(dispatch messaging.Dispatch, now time.Time) *nats.Msg {
+	if dispatch.Timestamp.IsZero() {
+		dispatch.Timestamp = now
+	}
+
+	message := nats.NewMsg(dispatch.Topic)
+	message.Data = dispatch.Payload
+	for key, value := range dispatch.Headers {
+		setHeader(message, key, formatHeader(value))
+	}
+
+	setHeader(message, HeaderSourceID, formatUint64(dispatch.SourceID))
+	setHeader(message, HeaderMessageID, formatUint64(dispatch.MessageID))
+	setHeader(message, HeaderCorrelationID, formatUint64(dispatch.CorrelationID))
+	setHeader(message, HeaderMessageType, dispatch.MessageType)
+	setHeader(message, HeaderContentType, dispatch.ContentType)
+	setHeader(message, HeaderContentEncoding, dispatch.ContentEncoding)
+	setHeader(message, HeaderTimestamp, dispatch.Timestamp.UTC().Format(time.RFC3339Nano))
+	return message
+}
+func setHeader(message *nats.Msg, key, value string) {
+	if len(value) == 0 {
+		return
+	}
+
+	message.Header[key] = []string{value} // not canonicalized, Nats-Msg-Id is case-sensitive
+}
+func formatUint64(value uint64) string {
+	if value == 0 {
+		return "" // a zero message ID must not be sent as it would cause JetStream to discard every message as a duplicate
+	}
+
+	return strconv.FormatUint(value, 10)
+}
+func formatHeader(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func (this *defaultWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	_, err := this.publish(context.Background(), this.buffer)
+	this.clearBuffer()
+	return err
+}
+func (this *defaultWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.clearBuffer()
+	return nil
+}
+func (this *defaultWriter) clearBuffer() {
+	for i := range this.buffer {
+		this.buffer[i] = nil // clear it out to avoid a memory leak
+	}
+
+	this.buffer = this.buffer[0:0]
+}
+
+// Close releases any buffered messages; the underlying NATS connection is shared and is closed by the connection.
+func (this *defaultWriter) Close() error {
+	return this.Rollback()
+}
@@ -0,0 +1,146 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/nats/adapter"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	now    time.Time
+	config configuration
+	writer messaging.CommitWriter
+
+	published    []*nats.Msg
+	publishError error
+}
+
+func (this *WriterFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	Options.apply(Options.Connector(nil), Options.Now(func() time.Time { return this.now }))(&this.config)
+	this.writer = newWriter(this, false, this.config)
+}
+
+func (this *WriterFixture) TestWhenWritingDispatchWithoutTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+	this.So(this.published, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWriting_PublishMessageWithHeaders() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Topic:           "topic",
+		Headers:         map[string]any{"key": "value", "number": 42},
+		Payload:         []byte("payload"),
+	})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.published, should.Equal, []*nats.Msg{{
+		Subject: "topic",
+		Data:    []byte("payload"),
+		Header: nats.Header{
+			HeaderSourceID:        {"1"},
+			HeaderMessageID:       {"2"},
+			HeaderCorrelationID:   {"3"},
+			HeaderMessageType:     {"message-type"},
+			HeaderContentType:     {"content-type"},
+			HeaderContentEncoding: {"content-encoding"},
+			HeaderTimestamp:       {"2020-01-02T03:04:05.000000006Z"},
+			"key":                 {"value"},
+			"number":              {"42"},
+		},
+	}})
+}
+func (this *WriterFixture) TestWhenWritingWithoutMessageID_OmitDeduplicationHeader() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic"})
+
+	_, contains := this.published[0].Header[HeaderMessageID]
+	this.So(contains, should.BeFalse)
+}
+func (this *WriterFixture) TestWhenPublishFails_ReturnCountWrittenAndError() {
+	this.publishError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic"}, messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.publishError)
+	this.So(len(this.published), should.Equal, 1)
+}
+func (this *WriterFixture) TestWhenWritingTransactionally_BufferUntilCommit() {
+	this.writer = newWriter(this, true, this.config)
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+
+	this.So(count, should.Equal, 2)
+	this.So(err, should.BeNil)
+	this.So(this.published, should.BeEmpty)
+
+	err = this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(len(this.published), should.Equal, 2)
+	this.So(this.published[0].Subject, should.Equal, "a")
+	this.So(this.published[1].Subject, should.Equal, "b")
+}
+func (this *WriterFixture) TestWhenRollingBack_DiscardBufferedMessages() {
+	this.writer = newWriter(this, true, this.config)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	err := this.writer.Rollback()
+	_ = this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.published, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenCommitFails_ReturnErrorAndDiscardBufferedMessages() {
+	this.writer = newWriter(this, true, this.config)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	publishError := errors.New("")
+	this.publishError = publishError
+
+	err := this.writer.Commit()
+	this.publishError = nil
+	_ = this.writer.Commit()
+
+	this.So(err, should.Equal, publishError)
+	this.So(len(this.published), should.Equal, 1)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *WriterFixture) Publish(_ context.Context, message *nats.Msg) error {
+	this.published = append(this.published, message)
+	return this.publishError
+}
+
+func (this *WriterFixture) DeclareStream(_ context.Context, _ string, _ []string) error { panic("nop") }
+func (this *WriterFixture) DeclareConsumer(_ context.Context, _ string, _ adapter.ConsumerConfig) error {
+	panic("nop")
+}
+func (this *WriterFixture) Consume(_ context.Context, _, _ string, _ int) (adapter.Consumer, error) {
+	panic("nop")
+}
+func (this *WriterFixture) Close() error { panic("nop") }
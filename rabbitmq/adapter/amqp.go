@@ -10,8 +10,12 @@ import (
 type amqpConnector struct{}
 
 func (this amqpConnector) Connect(_ context.Context, socket net.Conn, config Config) (Connection, error) {
-	plainAuth := &amqp.PlainAuth{Username: config.Username, Password: config.Password}
-	amqpConfig := amqp.Config{SASL: []amqp.Authentication{plainAuth}, Vhost: config.VirtualHost}
+	var authentication amqp.Authentication = &amqp.PlainAuth{Username: config.Username, Password: config.Password}
+	if config.Mechanism == MechanismExternal {
+		authentication = &amqp.ExternalAuth{}
+	}
+
+	amqpConfig := amqp.Config{SASL: []amqp.Authentication{authentication}, Vhost: config.VirtualHost}
 
 	if connection, err := amqp.Open(socket, amqpConfig); err != nil {
 		return nil, err
@@ -32,30 +36,50 @@ func (this amqpConnection) Channel() (Channel, error) {
 
 type amqpChannel struct{ *amqp.Channel }
 
-func (this amqpChannel) DeclareQueue(name string, replicated bool) error {
+func (this amqpChannel) DeclareQueue(name string, replicated bool, arguments map[string]any) error {
+	table := amqp.Table{"x-queue-type": "classic"}
 	if replicated {
-		return this.declareQueue(name, "quorum")
-	} else {
-		return this.declareQueue(name, "classic")
+		table["x-queue-type"] = "quorum"
 	}
-}
-func (this amqpChannel) declareQueue(name, style string) error {
-	_, err := this.Channel.QueueDeclare(name, true, false, false, false, amqp.Table{"x-queue-type": style})
+
+	for key, value := range arguments {
+		table[key] = value
+	}
+
+	_, err := this.Channel.QueueDeclare(name, true, false, false, false, table)
 	return err
 }
 
-func (this amqpChannel) DeclareExchange(name string) error {
-	return this.Channel.ExchangeDeclare(name, amqp.ExchangeFanout, true, false, false, false, amqp.Table{})
+func (this amqpChannel) DeclareExchange(name, kind string, arguments map[string]any) error {
+	return this.Channel.ExchangeDeclare(name, kind, true, false, false, false, amqp.Table(arguments))
+}
+func (this amqpChannel) BindQueue(queue, exchange, key string, arguments map[string]any) error {
+	return this.Channel.QueueBind(queue, key, exchange, false, amqp.Table(arguments))
+}
+
+func (this amqpChannel) DeclareQueuePassive(name string) error {
+	_, err := this.Channel.QueueDeclarePassive(name, true, false, false, false, nil)
+	return err
+}
+func (this amqpChannel) DeclareExchangePassive(name, kind string) error {
+	return this.Channel.ExchangeDeclarePassive(name, kind, true, false, false, false, nil)
 }
-func (this amqpChannel) BindQueue(queue, exchange string) error {
-	return this.Channel.QueueBind(queue, "", exchange, false, amqp.Table{})
+func (this amqpChannel) DeleteQueue(name string) error {
+	_, err := this.Channel.QueueDelete(name, false, false, false)
+	return err
+}
+func (this amqpChannel) DeleteExchange(name string) error {
+	return this.Channel.ExchangeDelete(name, false, false)
+}
+func (this amqpChannel) UnbindQueue(queue, exchange, key string, arguments map[string]any) error {
+	return this.Channel.QueueUnbind(queue, key, exchange, amqp.Table(arguments))
 }
 
 func (this amqpChannel) BufferCapacity(value uint16) error {
 	return this.Channel.Qos(int(value), 0, false) // false = per-consumer limit
 }
-func (this amqpChannel) Consume(consumerID, queue string) (<-chan amqp.Delivery, error) {
-	return this.Channel.Consume(queue, consumerID, false, false, false, false, amqp.Table{})
+func (this amqpChannel) Consume(consumerID, queue string, arguments map[string]any) (<-chan amqp.Delivery, error) {
+	return this.Channel.Consume(queue, consumerID, false, false, false, false, amqp.Table(arguments))
 }
 func (this amqpChannel) CancelConsumer(consumerID string) error {
 	return this.Channel.Cancel(consumerID, false)
@@ -64,3 +88,6 @@ func (this amqpChannel) CancelConsumer(consumerID string) error {
 func (this amqpChannel) Publish(exchange, key string, envelope amqp.Publishing) error {
 	return this.Channel.Publish(exchange, key, false, false, envelope)
 }
+func (this amqpChannel) PublishMandatory(exchange, key string, envelope amqp.Publishing) error {
+	return this.Channel.Publish(exchange, key, true, false, envelope)
+}
@@ -11,34 +11,73 @@ import (
 func New() Connector { return amqpConnector{} }
 
 type Config struct {
+	Mechanism   string // the SASL mechanism, MechanismPlain unless otherwise specified
 	Username    string
 	Password    string
 	VirtualHost string
 }
 
+// The SASL mechanisms with which to authenticate. EXTERNAL authenticates using the client certificate presented during
+// the TLS handshake.
+const (
+	MechanismPlain    = "PLAIN"
+	MechanismExternal = "EXTERNAL"
+)
+
 type Connector interface {
 	Connect(ctx context.Context, socket net.Conn, config Config) (Connection, error)
 }
 
 type Connection interface {
 	Channel() (Channel, error)
+
+	// UpdateSecret replaces the secret (e.g. an OAuth 2.0 access token) with which the connection was authenticated
+	// such that the broker doesn't close the connection once the original secret expires.
+	UpdateSecret(secret, reason string) error
+
+	// NotifyClose registers the receiver to receive the error with which the broker closes the connection, if any. The
+	// receiver is closed once the connection is closed.
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+
+	// NotifyBlocked registers the receiver to receive notification when the broker blocks (e.g. due to a resource
+	// alarm) and later unblocks the connection from publishing.
+	NotifyBlocked(receiver chan amqp.Blocking) chan amqp.Blocking
 	io.Closer
 }
 
 type Channel interface {
-	DeclareQueue(name string, replicated bool) error
-	DeclareExchange(name string) error
-	BindQueue(queue, exchange string) error
+	DeclareQueue(name string, replicated bool, arguments map[string]any) error
+	DeclareExchange(name, kind string, arguments map[string]any) error
+	BindQueue(queue, exchange, key string, arguments map[string]any) error
+
+	// DeclareQueuePassive and DeclareExchangePassive fail if the queue or exchange does not exist without declaring it.
+	// As with any failure of a declaration, the broker closes the channel when they fail.
+	DeclareQueuePassive(name string) error
+	DeclareExchangePassive(name, kind string) error
+	DeleteQueue(name string) error
+	DeleteExchange(name string) error
+	UnbindQueue(queue, exchange, key string, arguments map[string]any) error
 
 	BufferCapacity(value uint16) error
-	Consume(consumerID, queue string) (<-chan amqp.Delivery, error)
+	Consume(consumerID, queue string, arguments map[string]any) (<-chan amqp.Delivery, error)
 	Ack(deliveryTag uint64, multiple bool) error
+	Nack(deliveryTag uint64, multiple, requeue bool) error
 	CancelConsumer(consumerID string) error
 
 	Publish(exchange, key string, envelope amqp.Publishing) error
+	PublishMandatory(exchange, key string, envelope amqp.Publishing) error
+	NotifyReturn(returns chan amqp.Return) chan amqp.Return
 	Tx() error
 	TxCommit() error
 	TxRollback() error
 
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+	GetNextPublishSeqNo() uint64
+
+	// NotifyClose registers the receiver to receive the error with which the broker closes the channel (or its
+	// connection), if any. The receiver is closed once the channel is closed.
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+
 	io.Closer
 }
@@ -0,0 +1,121 @@
+package rabbitmq
+
+import (
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// closeListener receives the notification of the closure of a channel, or of its connection, by the broker. The broker
+// sends the notification before the channel's deliveries, confirmations, and returns are closed, so the closure is
+// always observable by the time any of those are found to be closed.
+type closeListener struct {
+	mutex         sync.Mutex
+	notifications chan *amqp.Error
+	err           error
+}
+
+type closeNotifier interface {
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+}
+
+func newCloseListener(inner closeNotifier) *closeListener {
+	// at most one notification is sent, after which the channel is closed.
+	return &closeListener{notifications: inner.NotifyClose(make(chan *amqp.Error, 1))}
+}
+
+// Closed returns the channel which is ready when the channel is closed; the result received is to be provided to
+// Receive. Once the closure has been received, the channel returned is nil and is thus never ready.
+func (this *closeListener) Closed() <-chan *amqp.Error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.notifications
+}
+
+// Receive records the notification received from Closed and returns the resulting *ClosedError, if any.
+func (this *closeListener) Receive(notification *amqp.Error, open bool) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.receive(notification, open)
+}
+func (this *closeListener) receive(notification *amqp.Error, open bool) error {
+	if open && notification != nil && this.err == nil {
+		this.err = newClosedError(notification)
+	}
+
+	this.notifications = nil // either closed or the one and only notification has been received
+	return this.err
+}
+
+// Err returns the *ClosedError describing the closure of the channel by the broker, if it has been closed by the broker.
+func (this *closeListener) Err() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	select {
+	case notification, open := <-this.notifications: // a nil channel is never ready
+		return this.receive(notification, open)
+	default:
+		return this.err
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// flowControl tracks whether the broker has blocked the connection from publishing, which it does when a resource
+// alarm (e.g. low memory or disk space) is raised, until the alarm clears. Writers await the connection being unblocked
+// rather than publishing messages the broker won't read.
+type flowControl struct {
+	mutex     sync.Mutex
+	unblocked chan struct{} // closed unless blocked
+	reason    string
+}
+
+func newFlowControl() *flowControl {
+	unblocked := make(chan struct{})
+	close(unblocked)
+	return &flowControl{unblocked: unblocked}
+}
+
+func (this *flowControl) Block(reason string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.reason = reason
+	select {
+	case <-this.unblocked:
+		this.unblocked = make(chan struct{})
+	default: // already blocked
+	}
+}
+func (this *flowControl) Unblock() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.reason = ""
+	select {
+	case <-this.unblocked: // already unblocked
+	default:
+		close(this.unblocked)
+	}
+}
+
+// Unblocked returns a channel which is ready while the connection is not blocked.
+func (this *flowControl) Unblocked() <-chan struct{} {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.unblocked
+}
+
+// Blocked indicates whether the connection is currently blocked and, if so, the reason given by the broker.
+func (this *flowControl) Blocked() (bool, string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	select {
+	case <-this.unblocked:
+		return false, ""
+	default:
+		return true, this.reason
+	}
+}
@@ -0,0 +1,96 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+)
+
+func TestClosuresFixture(t *testing.T) {
+	gunit.Run(new(ClosuresFixture), t)
+}
+
+type ClosuresFixture struct {
+	*gunit.Fixture
+
+	notifications chan *amqp.Error
+	listener      *closeListener
+	flow          *flowControl
+}
+
+func (this *ClosuresFixture) Setup() {
+	this.listener = newCloseListener(this)
+	this.flow = newFlowControl()
+}
+
+func (this *ClosuresFixture) TestWhenNotClosed_NoError() {
+	this.So(this.listener.Err(), should.BeNil)
+	this.So(this.listener.Closed(), should.NotBeNil)
+}
+func (this *ClosuresFixture) TestWhenClosedByBroker_ReturnClosedErrorWithReplyCode() {
+	this.notifications <- &amqp.Error{Code: 404, Reason: "NOT_FOUND", Recover: true}
+	close(this.notifications)
+
+	err := this.listener.Err()
+
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 404, ReplyText: "NOT_FOUND", Recoverable: true})
+	this.So(err.Error(), should.ContainSubstring, "404")
+	this.So(this.listener.Err(), should.Equal, err)
+	this.So(this.listener.Closed(), should.BeNil)
+}
+func (this *ClosuresFixture) TestWhenClosedGracefully_NoError() {
+	close(this.notifications)
+
+	this.So(this.listener.Err(), should.BeNil)
+	this.So(this.listener.Closed(), should.BeNil)
+}
+func (this *ClosuresFixture) TestWhenNotificationReceivedFromClosed_RecordClosedError() {
+	this.notifications <- &amqp.Error{Code: 320, Reason: "CONNECTION_FORCED"}
+	notification, open := <-this.listener.Closed()
+
+	err := this.listener.Receive(notification, open)
+
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 320, ReplyText: "CONNECTION_FORCED"})
+	this.So(this.listener.Err(), should.Equal, err)
+}
+
+func (this *ClosuresFixture) TestFlowInitiallyUnblocked() {
+	blocked, _ := this.flow.Blocked()
+
+	this.So(blocked, should.BeFalse)
+	this.So(isReady(this.flow.Unblocked()), should.BeTrue)
+}
+func (this *ClosuresFixture) TestWhenBlocked_NotReadyUntilUnblocked() {
+	this.flow.Block("low on memory")
+	this.flow.Block("low on disk")
+	blocked, reason := this.flow.Blocked()
+	unblocked := this.flow.Unblocked()
+
+	this.So(blocked, should.BeTrue)
+	this.So(reason, should.Equal, "low on disk")
+	this.So(isReady(unblocked), should.BeFalse)
+
+	this.flow.Unblock()
+	this.flow.Unblock()
+
+	this.So(isReady(unblocked), should.BeTrue)
+	blocked, _ = this.flow.Blocked()
+	this.So(blocked, should.BeFalse)
+}
+func isReady(channel <-chan struct{}) bool {
+	select {
+	case <-channel:
+		return true
+	default:
+		return false
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ClosuresFixture) NotifyClose(notifications chan *amqp.Error) chan *amqp.Error {
+	this.notifications = notifications
+	return notifications
+}
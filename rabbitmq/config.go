@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
 	"net/url"
@@ -17,16 +18,30 @@ func New(options ...option) messaging.Connector {
 }
 
 type configuration struct {
-	Address              *url.URL
-	TLSConfig            *tls.Config
-	Endpoint             brokerEndpoint
-	TLSClient            tlsClientFunc
-	Dialer               netDialer
-	Connector            adapter.Connector
-	Logger               logger
-	Monitor              monitor
-	Now                  func() time.Time
-	TopologyFailurePanic bool
+	Addresses              []*url.URL
+	TLSConfig              *tls.Config
+	Endpoints              []brokerEndpoint
+	RoundRobin             bool
+	UnavailableCooldown    time.Duration
+	ExternalAuth           bool
+	Credentials            func(context.Context) (Credentials, error)
+	RefreshMargin          time.Duration
+	TLSClient              tlsClientFunc
+	Dialer                 netDialer
+	Connector              adapter.Connector
+	Logger                 logger
+	Monitor                monitor
+	Now                    func() time.Time
+	TopologyFailurePanic   bool
+	PublisherConfirms      bool
+	ConfirmTimeout         time.Duration
+	Mandatory              bool
+	ReturnHandler          func(ReturnedDispatch)
+	WriterPoolSize         uint16
+	DelayedMessageExchange bool
+	DelayTiers             []time.Duration
+	ExchangeTypes          map[string]string
+	Bindings               map[bindingTarget][]binding
 }
 
 var Options singleton
@@ -35,14 +50,65 @@ type singleton struct{}
 type option func(*configuration)
 
 func (singleton) Address(value string) option {
-	return func(this *configuration) { this.Address, _ = url.Parse(coalesce(value, defaultAddress)) }
+	return Options.Addresses(value)
 }
 func (singleton) BrokerAddress(value *url.URL) option {
 	if value == nil {
 		return Options.Address(defaultAddress)
 	}
 
-	return func(this *configuration) { this.Address = value }
+	return func(this *configuration) { this.Addresses = []*url.URL{value} }
+}
+
+// Addresses are the URLs of each of the nodes of a broker cluster. The connector attempts to connect to each node in
+// the order specified (or, with RoundRobin, starting with the node after the one previously attempted) until one of
+// them succeeds. Nodes which recently failed are attempted only after all of the others have also failed.
+func (singleton) Addresses(values ...string) option {
+	return func(this *configuration) {
+		this.Addresses = nil
+		for _, value := range values {
+			if parsed, err := url.Parse(coalesce(value, defaultAddress)); err == nil {
+				this.Addresses = append(this.Addresses, parsed)
+			}
+		}
+
+		if len(this.Addresses) == 0 {
+			parsed, _ := url.Parse(defaultAddress)
+			this.Addresses = append(this.Addresses, parsed)
+		}
+	}
+}
+
+// RoundRobin indicates that each connection attempt starts with the next of the Addresses, rather than the first, so
+// that connections are distributed among the nodes of the cluster.
+func (singleton) RoundRobin(value bool) option {
+	return func(this *configuration) { this.RoundRobin = value }
+}
+
+// UnavailableCooldown is the duration for which a node is considered unavailable after failing to connect to it.
+func (singleton) UnavailableCooldown(value time.Duration) option {
+	return func(this *configuration) { this.UnavailableCooldown = value }
+}
+
+// ExternalAuthentication indicates that connections authenticate using the SASL EXTERNAL mechanism, that is, with the
+// client certificate of the TLSConfig presented during the TLS handshake (which requires an "amqps" address) rather than
+// with a username and password.
+func (singleton) ExternalAuthentication(value bool) option {
+	return func(this *configuration) { this.ExternalAuth = value }
+}
+
+// CredentialProvider is invoked on each attempt to connect and provides the credentials with which to authenticate in
+// place of those of the address, e.g. short-lived passwords issued by a secrets manager. When the credentials provided
+// specify an Expiration, such as an OAuth 2.0 access token (provided as the password), the provider is invoked again
+// prior to their expiration and the secret of each open connection is updated such that it remains open.
+func (singleton) CredentialProvider(value func(context.Context) (Credentials, error)) option {
+	return func(this *configuration) { this.Credentials = value }
+}
+
+// CredentialRefreshMargin is the duration prior to the expiration of the credentials provided at which they are
+// refreshed.
+func (singleton) CredentialRefreshMargin(value time.Duration) option {
+	return func(this *configuration) { this.RefreshMargin = value }
 }
 
 func (singleton) TLSConfig(value *tls.Config) option {
@@ -60,9 +126,93 @@ func (singleton) TLSClient(value tlsClientFunc) option {
 func (singleton) PanicOnTopologyError(value bool) option {
 	return func(this *configuration) { this.TopologyFailurePanic = value }
 }
+
+// PublisherConfirms indicates that each CommitWriter uses publisher confirms rather than AMQP transactions, which are
+// slow and poorly supported by quorum queues. Messages written are published immediately and Commit blocks until the
+// broker has confirmed each of them. Because published messages cannot be withdrawn, Rollback has no effect.
+func (singleton) PublisherConfirms(value bool) option {
+	return func(this *configuration) { this.PublisherConfirms = value }
+}
+
+// ConfirmTimeout is the maximum duration Commit awaits the broker's confirmation of the messages written.
+func (singleton) ConfirmTimeout(value time.Duration) option {
+	return func(this *configuration) { this.ConfirmTimeout = value }
+}
+
+// Mandatory indicates that dispatches are published as mandatory such that the broker returns, rather than silently
+// discards, those which cannot be routed to any queue. Each returned dispatch is provided to the ReturnHandler and is
+// reported by the subsequent Commit of a CommitWriter as an *UnroutableError.
+func (singleton) Mandatory(value bool) option {
+	return func(this *configuration) { this.Mandatory = value }
+}
+
+// ReturnHandler receives each dispatch returned by the broker as unroutable when publishing as Mandatory. It is
+// invoked on a goroutine dedicated to the writer's channel as soon as each return is received, so it must be safe for
+// concurrent use and should not block.
+func (singleton) ReturnHandler(value func(ReturnedDispatch)) option {
+	return func(this *configuration) { this.ReturnHandler = value }
+}
+
+// WriterPool indicates that each Writer of a connection shares a pool of at most the number of channels specified,
+// over which concurrent writes are multiplexed, such that a single Writer may be safely used by any number of
+// goroutines. A channel which fails is closed and replaced. The pool's channels are closed along with the connection;
+// closing the Writer itself has no effect. When PublisherConfirms is also specified, the pool's channels are in confirm
+// mode and each pooled write returns only once the broker has confirmed its messages. CommitWriters, being
+// transactional, each continue to use their own channel.
+func (singleton) WriterPool(channels uint16) option {
+	return func(this *configuration) { this.WriterPoolSize = channels }
+}
+
+// DelayedMessageExchange indicates that dispatches with a Delay or DeliveryTime are delayed by the delayed-message
+// exchange plugin, which must be enabled on the broker. When establishing topology, the exchange of each topic is then
+// declared as an ExchangeDelayedMessage exchange routing messages as an exchange of its ExchangeType would.
+func (singleton) DelayedMessageExchange(value bool) option {
+	return func(this *configuration) { this.DelayedMessageExchange = value }
+}
+
+// DelayTiers are the delays supported without the delayed-message exchange plugin. A dispatch with a Delay or
+// DeliveryTime is published to the DelayTier of its topic with the shortest delay not less than that of the dispatch;
+// a dispatch with a delay greater than that of every tier fails to be written with ErrDelayNotSupported. The exchange
+// and queue of each tier must already exist, e.g. as declared using the topology package's DelayTiers. Because the
+// broker removes the expiration of a message when dead-lettering it, the Expiration of a delayed dispatch is ignored.
+func (singleton) DelayTiers(values ...time.Duration) option {
+	return func(this *configuration) { this.DelayTiers = values }
+}
+
+// ExchangeType is the type of exchange (one of ExchangeFanout, ExchangeTopic, ExchangeDirect, or ExchangeHeaders)
+// declared for the topic when establishing topology. Exchanges are declared as fanout exchanges unless otherwise
+// specified.
+func (singleton) ExchangeType(topic, kind string) option {
+	return func(this *configuration) {
+		if this.ExchangeTypes == nil {
+			this.ExchangeTypes = make(map[string]string)
+		}
+		this.ExchangeTypes[topic] = kind
+	}
+}
+
+// Binding adds a binding of the queue (stream) to the exchange of the topic with the routing key (or pattern, e.g.
+// "orders.*.created" for a topic exchange) and arguments (e.g. "x-match" and the header values for a headers exchange)
+// specified. Multiple bindings may be added for the same queue and topic. When no binding is specified, a queue is
+// bound to each of its topics with an empty routing key or, in the case of a topic exchange, with "#".
+func (singleton) Binding(queue, topic, key string, arguments map[string]any) option {
+	return func(this *configuration) {
+		if this.Bindings == nil {
+			this.Bindings = make(map[bindingTarget][]binding)
+		}
+		target := bindingTarget{Queue: queue, Topic: topic}
+		this.Bindings[target] = append(this.Bindings[target], binding{Key: key, Arguments: arguments})
+	}
+}
 func (singleton) Logger(value logger) option {
 	return func(this *configuration) { this.Logger = value }
 }
+
+// Monitor is informed of the activity of connections, readers, and writers. A monitor which also implements
+// BrokerSelected(address string) and BrokerUnavailable(address string, err error) is informed of the broker of each
+// connection; one which also implements ConnectionClosedByBroker(err error) and ConnectionBlocked(active bool, reason
+// string) is informed of why the broker closed a connection and of each time it blocks or unblocks a connection; and
+// one which also implements DeliveryRejected(count uint16, requeue bool, err error) is informed of each rejection.
 func (singleton) Monitor(value monitor) option {
 	return func(this *configuration) { this.Monitor = value }
 }
@@ -75,7 +225,10 @@ func (singleton) apply(options ...option) option {
 			item(this)
 		}
 
-		this.Endpoint = brokerEndpoint{Address: this.Address, TLSConfig: this.TLSConfig}
+		this.Endpoints = make([]brokerEndpoint, 0, len(this.Addresses))
+		for _, address := range this.Addresses {
+			this.Endpoints = append(this.Endpoints, brokerEndpoint{Address: address, TLSConfig: this.TLSConfig})
+		}
 
 		if this.TLSClient == nil {
 			this.TLSClient = func(conn net.Conn, config *tls.Config) tlsConn {
@@ -92,6 +245,9 @@ func (singleton) apply(options ...option) option {
 }
 func (singleton) defaults(options ...option) []option {
 	const defaultTopologyFailurePanic = true
+	const defaultConfirmTimeout = time.Second * 30
+	const defaultUnavailableCooldown = time.Second * 30
+	const defaultRefreshMargin = time.Minute
 	var defaultNow = time.Now
 	var defaultLogger = nop{}
 	var defaultMonitor = nop{}
@@ -115,6 +271,10 @@ func (singleton) defaults(options ...option) []option {
 		Options.TLSConfig(defaultTLS),
 		Options.Connector(adapter.New()),
 		Options.PanicOnTopologyError(defaultTopologyFailurePanic),
+		Options.ConfirmTimeout(defaultConfirmTimeout),
+		Options.UnavailableCooldown(defaultUnavailableCooldown),
+		Options.CredentialRefreshMargin(defaultRefreshMargin),
+		Options.ReturnHandler(func(ReturnedDispatch) {}),
 		Options.Logger(defaultLogger),
 		Options.Monitor(defaultMonitor),
 		Options.Now(defaultNow),
@@ -131,10 +291,15 @@ type nop struct{}
 
 func (nop) Printf(_ string, _ ...any) {}
 
-func (nop) ConnectionOpened(_ error)               {}
-func (nop) ConnectionClosed()                      {}
-func (nop) DispatchPublished()                     {}
-func (nop) DeliveryReceived()                      {}
-func (nop) DeliveryAcknowledged(_ uint16, _ error) {}
-func (nop) TransactionCommitted(_ error)           {}
-func (nop) TransactionRolledBack(_ error)          {}
+func (nop) ConnectionOpened(_ error)                   {}
+func (nop) BrokerSelected(_ string)                    {}
+func (nop) BrokerUnavailable(_ string, _ error)        {}
+func (nop) ConnectionClosed()                          {}
+func (nop) ConnectionClosedByBroker(_ error)           {}
+func (nop) ConnectionBlocked(_ bool, _ string)         {}
+func (nop) DispatchPublished()                         {}
+func (nop) DeliveryReceived()                          {}
+func (nop) DeliveryAcknowledged(_ uint16, _ error)     {}
+func (nop) DeliveryRejected(_ uint16, _ bool, _ error) {}
+func (nop) TransactionCommitted(_ error)               {}
+func (nop) TransactionRolledBack(_ error)              {}
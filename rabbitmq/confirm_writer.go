@@ -0,0 +1,167 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/rabbitmq/adapter"
+)
+
+// confirmWriter publishes each message immediately on a channel in confirm mode and tracks the delivery tag assigned
+// by the broker to each. Commit awaits the confirmation of every outstanding delivery tag. Unlike AMQP transactions,
+// messages published cannot be withdrawn, so Rollback merely stops tracking the outstanding confirmations.
+type confirmWriter struct {
+	writer  defaultWriter
+	inner   adapter.Channel
+	timeout time.Duration
+	logger  logger
+	monitor monitor
+
+	mutex         sync.Mutex
+	confirmations chan amqp.Confirmation
+	outstanding   map[uint64]bool // true if awaited by Commit, false if discarded but yet to be confirmed
+	awaiting      int
+	rejected      bool
+}
+
+func newConfirmWriter(inner adapter.Channel, flow *flowControl, config configuration) messaging.CommitWriter {
+	// the broker's confirmations are dispatched synchronously by the connection, so the capacity of the channel bounds
+	// the number of unconfirmed messages which may be outstanding at any time without stalling the connection.
+	confirmations := inner.NotifyPublish(make(chan amqp.Confirmation, maxOutstandingConfirms))
+	return &confirmWriter{
+		writer:        newWriter(inner, flow, true, config).(defaultWriter),
+		inner:         inner,
+		timeout:       config.ConfirmTimeout,
+		logger:        config.Logger,
+		monitor:       config.Monitor,
+		confirmations: confirmations,
+		outstanding:   make(map[uint64]bool),
+	}
+}
+
+func (this *confirmWriter) Write(ctx context.Context, messages ...messaging.Dispatch) (count int, err error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, message := range messages {
+		if err = this.await(ctx, this.available); err != nil {
+			return count, err
+		}
+
+		var written int
+		deliveryTag := this.inner.GetNextPublishSeqNo()
+		written, err = this.writer.Write(ctx, message)
+		if count += written; err != nil {
+			return count, err
+		}
+
+		this.outstanding[deliveryTag] = true
+		this.awaiting++
+	}
+
+	return count, nil
+}
+
+// Commit blocks until the broker has confirmed each of the messages written since the previous Commit or Rollback.
+func (this *confirmWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	err := this.await(context.Background(), this.confirmed)
+	if err == nil && this.rejected {
+		err = ErrPublishNotAcked
+	}
+
+	if err == nil {
+		err = this.writer.collectReturns() // returns are received before the confirmation of the returned dispatch
+	} else {
+		this.writer.discardReturns()
+	}
+	this.reset()
+
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to confirm messages published [%s].", err)
+	}
+
+	this.monitor.TransactionCommitted(err)
+	return err
+}
+
+// Rollback is not supported by publisher confirms as the messages written have already been published. It is safe to
+// call, however, as it simply discards the outstanding confirmations such that they are not reported by a later Commit.
+func (this *confirmWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.awaiting > 0 {
+		this.logger.Printf("[WARN] Unable to rollback messages already published in confirm mode; rollback is not supported.")
+	}
+
+	this.reset()
+	this.writer.discardReturns()
+	this.monitor.TransactionRolledBack(nil)
+	return nil
+}
+func (this *confirmWriter) reset() {
+	for deliveryTag := range this.outstanding {
+		this.outstanding[deliveryTag] = false
+	}
+
+	this.awaiting = 0
+	this.rejected = false
+}
+
+// await receives confirmations from the broker until the condition is satisfied.
+func (this *confirmWriter) await(ctx context.Context, condition func() bool) error {
+	if condition() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, this.timeout)
+	defer cancel()
+
+	for !condition() {
+		select {
+		case confirmation, open := <-this.confirmations:
+			if !open {
+				return coalesceError(this.writer.closes.Err(), amqp.ErrClosed)
+			}
+			this.confirm(confirmation)
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrConfirmTimeout
+			}
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+func (this *confirmWriter) available() bool { return len(this.outstanding) < maxOutstandingConfirms }
+func (this *confirmWriter) confirmed() bool { return this.awaiting == 0 }
+func (this *confirmWriter) confirm(confirmation amqp.Confirmation) {
+	awaited, contains := this.outstanding[confirmation.DeliveryTag]
+	if !contains {
+		return
+	}
+
+	delete(this.outstanding, confirmation.DeliveryTag)
+	if !awaited {
+		return // previously discarded by Rollback or an unsuccessful Commit
+	}
+
+	this.awaiting--
+	if !confirmation.Ack {
+		this.rejected = true
+	}
+}
+
+func (this *confirmWriter) Close() error {
+	return this.inner.Close()
+}
+
+const maxOutstandingConfirms = 1024
@@ -0,0 +1,251 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestConfirmWriterFixture(t *testing.T) {
+	gunit.Run(new(ConfirmWriterFixture), t)
+}
+
+type ConfirmWriterFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	writer messaging.CommitWriter
+
+	confirmations   chan amqp.Confirmation
+	closes          chan *amqp.Error
+	returns         chan amqp.Return
+	mandatoryCount  int
+	publishCount    uint64
+	publishError    error
+	closeError      error
+	committed       []error
+	rolledBackCalls int
+}
+
+func (this *ConfirmWriterFixture) Setup() {
+	this.ctx = context.Background()
+	this.initializeWriter(false)
+}
+func (this *ConfirmWriterFixture) initializeWriter(mandatory bool) {
+	config := configuration{}
+	Options.apply(
+		Options.ConfirmTimeout(time.Millisecond*10),
+		Options.Monitor(this),
+		Options.Mandatory(mandatory),
+	)(&config)
+	this.writer = newConfirmWriter(this, newFlowControl(), config)
+}
+
+func (this *ConfirmWriterFixture) TestWhenWriting_PublishImmediately() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+
+	this.So(count, should.Equal, 2)
+	this.So(err, should.BeNil)
+	this.So(this.publishCount, should.Equal, 2)
+}
+func (this *ConfirmWriterFixture) TestWhenWritingWithoutTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+}
+func (this *ConfirmWriterFixture) TestWhenPublishFails_ReturnErrorAndDoNotAwaitConfirmation() {
+	this.publishError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.publishError)
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *ConfirmWriterFixture) TestWhenCommittingWithoutWrites_ReturnImmediately() {
+	err := this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.committed, should.Equal, []error{nil})
+}
+func (this *ConfirmWriterFixture) TestWhenAllWritesAcknowledged_CommitSucceeds() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 2, Ack: true}
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+
+	err := this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.committed, should.Equal, []error{nil})
+}
+func (this *ConfirmWriterFixture) TestWhenAnyWriteNotAcknowledged_CommitFails() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 1, Ack: false}
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 2, Ack: true}
+
+	err := this.writer.Commit()
+
+	this.So(err, should.Equal, ErrPublishNotAcked)
+	this.So(this.committed, should.Equal, []error{ErrPublishNotAcked})
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *ConfirmWriterFixture) TestWhenConfirmationsNotReceivedInTime_CommitFails() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	err := this.writer.Commit()
+
+	this.So(err, should.Equal, ErrConfirmTimeout)
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *ConfirmWriterFixture) TestWhenConfirmationChannelClosed_CommitFails() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	close(this.confirmations)
+
+	err := this.writer.Commit()
+
+	this.So(err, should.Equal, amqp.ErrClosed)
+}
+func (this *ConfirmWriterFixture) TestWhenChannelClosedByBrokerAwaitingConfirmation_CommitFailsWithClosedError() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	this.closes <- &amqp.Error{Code: 406, Reason: "PRECONDITION_FAILED"}
+	close(this.confirmations)
+
+	err := this.writer.Commit()
+
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 406, ReplyText: "PRECONDITION_FAILED"})
+}
+func (this *ConfirmWriterFixture) TestWhenRollingBack_DiscardOutstandingConfirmations() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	err := this.writer.Rollback()
+
+	this.So(err, should.BeNil)
+	this.So(this.rolledBackCalls, should.Equal, 1)
+
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "b"})
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 1, Ack: false} // discarded
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 2, Ack: true}
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *ConfirmWriterFixture) TestWhenTooManyConfirmationsOutstanding_AwaitConfirmationBeforePublishing() {
+	for i := 0; i < maxOutstandingConfirms; i++ {
+		_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	}
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, ErrConfirmTimeout)
+	this.So(this.publishCount, should.Equal, maxOutstandingConfirms)
+
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+	count, err = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+}
+func (this *ConfirmWriterFixture) TestWhenMandatoryDispatchReturned_CommitFailsWithUnroutableError() {
+	this.initializeWriter(true)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a", MessageID: 42})
+	this.returns <- amqp.Return{Exchange: "a", MessageId: "42", ReplyCode: 312, ReplyText: "NO_ROUTE"}
+	this.confirmations <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+
+	err := this.writer.Commit()
+
+	this.So(this.mandatoryCount, should.Equal, 1)
+	this.So(err, should.Equal, &UnroutableError{Returned: []ReturnedDispatch{
+		{Topic: "a", MessageID: 42, ReplyCode: 312, ReplyText: "NO_ROUTE"},
+	}})
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *ConfirmWriterFixture) TestWhenMandatoryDispatchReturnedAndRolledBack_ReturnsDiscarded() {
+	this.initializeWriter(true)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	this.returns <- amqp.Return{Exchange: "a"}
+
+	_ = this.writer.Rollback()
+
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *ConfirmWriterFixture) TestWhenClosing_CloseUnderlyingChannel() {
+	this.closeError = errors.New("")
+
+	err := this.writer.Close()
+
+	this.So(err, should.Equal, this.closeError)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ConfirmWriterFixture) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	this.confirmations = confirm
+	return confirm
+}
+func (this *ConfirmWriterFixture) NotifyClose(closes chan *amqp.Error) chan *amqp.Error {
+	this.closes = closes
+	return closes
+}
+func (this *ConfirmWriterFixture) NotifyReturn(returns chan amqp.Return) chan amqp.Return {
+	this.returns = returns
+	return returns
+}
+func (this *ConfirmWriterFixture) GetNextPublishSeqNo() uint64 { return this.publishCount + 1 }
+func (this *ConfirmWriterFixture) PublishMandatory(exchange, key string, envelope amqp.Publishing) error {
+	this.mandatoryCount++
+	return this.Publish(exchange, key, envelope)
+}
+func (this *ConfirmWriterFixture) Publish(_, _ string, _ amqp.Publishing) error {
+	if this.publishError != nil {
+		return this.publishError
+	}
+
+	this.publishCount++
+	return nil
+}
+func (this *ConfirmWriterFixture) Close() error { return this.closeError }
+
+func (this *ConfirmWriterFixture) DeclareQueue(name string, replicated bool, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *ConfirmWriterFixture) DeclareExchange(name, kind string, _ map[string]any) error {
+	panic("nop")
+}
+func (this *ConfirmWriterFixture) BindQueue(queue, exchange, key string, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *ConfirmWriterFixture) BufferCapacity(value uint16) error { panic("nop") }
+func (this *ConfirmWriterFixture) Consume(_, _ string, _ map[string]any) (<-chan amqp.Delivery, error) {
+	panic("nop")
+}
+func (this *ConfirmWriterFixture) Ack(deliveryTag uint64, multiple bool) error { panic("nop") }
+func (this *ConfirmWriterFixture) CancelConsumer(consumerID string) error      { panic("nop") }
+func (this *ConfirmWriterFixture) Tx() error                                   { panic("nop") }
+func (this *ConfirmWriterFixture) TxCommit() error                             { panic("nop") }
+func (this *ConfirmWriterFixture) TxRollback() error                           { panic("nop") }
+func (this *ConfirmWriterFixture) Confirm(noWait bool) error                   { panic("nop") }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ConfirmWriterFixture) TransactionCommitted(err error) {
+	this.committed = append(this.committed, err)
+}
+func (this *ConfirmWriterFixture) TransactionRolledBack(_ error)               { this.rolledBackCalls++ }
+func (this *ConfirmWriterFixture) ConnectionOpened(_ error)                    {}
+func (this *ConfirmWriterFixture) ConnectionClosed()                           {}
+func (this *ConfirmWriterFixture) DispatchPublished()                          {}
+func (this *ConfirmWriterFixture) DeliveryReceived()                           {}
+func (this *ConfirmWriterFixture) DeliveryAcknowledged(uint16, error)          {}
+func (this *ConfirmWriterFixture) Nack(uint64, bool, bool) error               { panic("nop") }
+func (this *ConfirmWriterFixture) DeclareQueuePassive(string) error            { panic("nop") }
+func (this *ConfirmWriterFixture) DeclareExchangePassive(string, string) error { panic("nop") }
+func (this *ConfirmWriterFixture) DeleteQueue(string) error                    { panic("nop") }
+func (this *ConfirmWriterFixture) DeleteExchange(string) error                 { panic("nop") }
+func (this *ConfirmWriterFixture) UnbindQueue(string, string, string, map[string]any) error {
+	panic("nop")
+}
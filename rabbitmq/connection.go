@@ -3,24 +3,97 @@ package rabbitmq
 import (
 	"context"
 	"sync"
+	"time"
 
+	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/smarty/messaging/v3"
 	"github.com/smarty/messaging/v3/rabbitmq/adapter"
 )
 
 type defaultConnection struct {
-	inner   adapter.Connection
-	config  configuration
-	logger  logger
-	monitor monitor
-	closer  sync.Once
+	inner     adapter.Connection
+	config    configuration
+	logger    logger
+	monitor   monitor
+	refresher *credentialRefresher
+	flow      *flowControl
+	pool      *writerPool
+	closer    sync.Once
+	reporter  sync.Once
 }
 
-func newConnection(inner adapter.Connection, config configuration) messaging.Connection {
+// newConnection wraps the underlying connection. When the expiration of the credentials with which the connection was
+// authenticated is specified, the credentials are refreshed prior to their expiration for as long as it remains open.
+func newConnection(inner adapter.Connection, expiration time.Time, config configuration) messaging.Connection {
 	// NOTE: using pointer type to allow for pointer equality check
 	config.Monitor.ConnectionOpened(nil)
-	return &defaultConnection{inner: inner, config: config, logger: config.Logger, monitor: config.Monitor}
+	this := &defaultConnection{
+		inner:   inner,
+		config:  config,
+		logger:  config.Logger,
+		monitor: config.Monitor,
+		flow:    newFlowControl(),
+	}
+	if config.WriterPoolSize > 0 {
+		this.pool = newWriterPool(func() (messaging.CommitWriter, error) { return this.writer(config.PublisherConfirms) }, config.WriterPoolSize, config)
+	}
+	if !expiration.IsZero() && config.Credentials != nil {
+		this.refresher = newCredentialRefresher(inner, expiration, config)
+	}
+
+	// notifications are sent synchronously by the connection, so they are received by a dedicated goroutine which exits
+	// once the connection is closed.
+	closes := inner.NotifyClose(make(chan *amqp.Error, 1))
+	blocks := inner.NotifyBlocked(make(chan amqp.Blocking, 1))
+	go this.watch(closes, blocks)
+
+	return this
+}
+func (this *defaultConnection) watch(closes chan *amqp.Error, blocks chan amqp.Blocking) {
+	for {
+		select {
+		case notification, open := <-closes:
+			if open && notification != nil {
+				err := newClosedError(notification)
+				this.logger.Printf("[WARN] AMQP connection closed by the broker [%s].", err)
+				this.stopRefreshing()
+				this.reportClosed(err)
+			}
+			return
+		case blocking, open := <-blocks:
+			if !open {
+				blocks = nil // a nil channel is never ready
+			} else if blocking.Active {
+				this.logger.Printf("[WARN] AMQP connection blocked by the broker [%s], pausing writers...", blocking.Reason)
+				this.flow.Block(blocking.Reason)
+				this.reportBlocked(true, blocking.Reason)
+			} else {
+				this.logger.Printf("[INFO] AMQP connection unblocked by the broker, resuming writers.")
+				this.flow.Unblock()
+				this.reportBlocked(false, "")
+			}
+		}
+	}
+}
+func (this *defaultConnection) stopRefreshing() {
+	if this.refresher != nil {
+		this.refresher.Close()
+	}
+}
+func (this *defaultConnection) reportClosed(err error) {
+	this.reporter.Do(func() {
+		this.monitor.ConnectionClosed()
+		if extended, ok := this.monitor.(connectionMonitor); ok && err != nil {
+			extended.ConnectionClosedByBroker(err)
+		}
+	})
+}
+func (this *defaultConnection) reportBlocked(active bool, reason string) {
+	if extended, ok := this.monitor.(connectionMonitor); ok {
+		extended.ConnectionBlocked(active, reason)
+	}
 }
+
 func (this *defaultConnection) Reader(_ context.Context) (messaging.Reader, error) {
 	if channel, err := this.inner.Channel(); err != nil {
 		this.logger.Printf("[WARN] Unable able open read channel [%s].", err)
@@ -31,6 +104,10 @@ func (this *defaultConnection) Reader(_ context.Context) (messaging.Reader, erro
 }
 
 func (this *defaultConnection) Writer(_ context.Context) (messaging.Writer, error) {
+	if this.pool != nil {
+		return pooledWriter{writerPool: this.pool}, nil
+	}
+
 	return this.writer(false)
 }
 func (this *defaultConnection) CommitWriter(_ context.Context) (messaging.CommitWriter, error) {
@@ -44,7 +121,11 @@ func (this *defaultConnection) writer(transactional bool) (messaging.CommitWrite
 	}
 
 	if !transactional {
-		return newWriter(channel, this.config), nil
+		return newWriter(channel, this.flow, false, this.config), nil
+	}
+
+	if this.config.PublisherConfirms {
+		return this.confirmWriter(channel)
 	}
 
 	if err = channel.Tx(); err != nil {
@@ -52,13 +133,27 @@ func (this *defaultConnection) writer(transactional bool) (messaging.CommitWrite
 		return nil, err
 	}
 
-	return newWriter(channel, this.config), nil
+	return newWriter(channel, this.flow, true, this.config), nil
+}
+
+func (this *defaultConnection) confirmWriter(channel adapter.Channel) (messaging.CommitWriter, error) {
+	if err := channel.Confirm(false); err != nil {
+		this.logger.Printf("[WARN] Unable to place write channel into confirm mode [%s].", err)
+		_ = channel.Close()
+		return nil, err
+	}
+
+	return newConfirmWriter(channel, this.flow, this.config), nil
 }
 
 func (this *defaultConnection) Close() (err error) {
 	this.closer.Do(func() {
+		this.stopRefreshing()
+		if this.pool != nil {
+			_ = this.pool.Close()
+		}
 		err = this.inner.Close()
-		this.monitor.ConnectionClosed()
+		this.reportClosed(nil)
 	})
 
 	return err
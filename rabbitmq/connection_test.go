@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/smarty/gunit"
@@ -25,10 +26,30 @@ type ConnectionFixture struct {
 	channelError error
 	closeError   error
 	txCalls      int
+
+	confirmError  error
+	confirmCalls  int
+	confirmNoWait bool
+	notifyCalls   int
+
+	closes []chan *amqp.Error // the first of which is that of the connection, followed by those of its channels
+	blocks chan amqp.Blocking
+
+	closures      chan struct{}
+	closedReasons chan error
+	blockedStates chan bool
 }
 
 func (this *ConnectionFixture) Setup() {
-	this.connection = newConnection(this, configuration{Monitor: nop{}, Logger: nop{}})
+	this.closures = make(chan struct{}, 4)
+	this.closedReasons = make(chan error, 4)
+	this.blockedStates = make(chan bool, 4)
+	this.connection = newConnection(this, time.Time{}, configuration{Monitor: nop{}, Logger: nop{}})
+}
+
+func (this *ConnectionFixture) initializeMonitoredConnection() {
+	this.closes = nil
+	this.connection = newConnection(this, time.Time{}, configuration{Monitor: this, Logger: nop{}})
 }
 
 func (this *ConnectionFixture) TestWhenOpeningReader_OpenAChannelAndReturnReader() {
@@ -72,6 +93,38 @@ func (this *ConnectionFixture) TestWhenMarkingChannelAsTransactionalFails_Return
 	this.So(this.txCalls, should.Equal, 1)
 }
 
+func (this *ConnectionFixture) TestWhenOpeningCommitWriterWithPublisherConfirms_OpenAConfirmChannelAndReturnWriter() {
+	this.connection = newConnection(this, time.Time{}, configuration{Monitor: nop{}, Logger: nop{}, PublisherConfirms: true})
+
+	writer, err := this.connection.CommitWriter(context.Background())
+
+	this.So(writer, should.HaveSameTypeAs, &confirmWriter{})
+	this.So(err, should.BeNil)
+	this.So(this.confirmCalls, should.Equal, 1)
+	this.So(this.confirmNoWait, should.BeFalse)
+	this.So(this.notifyCalls, should.Equal, 1)
+	this.So(this.txCalls, should.Equal, 0)
+}
+func (this *ConnectionFixture) TestWhenPlacingChannelIntoConfirmModeFails_ReturnUnderlyingError() {
+	this.connection = newConnection(this, time.Time{}, configuration{Monitor: nop{}, Logger: nop{}, PublisherConfirms: true})
+	this.confirmError = errors.New("")
+
+	writer, err := this.connection.CommitWriter(context.Background())
+
+	this.So(writer, should.BeNil)
+	this.So(err, should.Equal, this.confirmError)
+	this.So(this.notifyCalls, should.Equal, 0)
+}
+func (this *ConnectionFixture) TestWhenOpeningWriterWithPublisherConfirms_DoNotUseConfirmMode() {
+	this.connection = newConnection(this, time.Time{}, configuration{Monitor: nop{}, Logger: nop{}, PublisherConfirms: true})
+
+	writer, err := this.connection.Writer(context.Background())
+
+	this.So(writer, should.HaveSameTypeAs, defaultWriter{})
+	this.So(err, should.BeNil)
+	this.So(this.confirmCalls, should.Equal, 0)
+}
+
 func (this *ConnectionFixture) TestWhenOpeningWriter_OpenATransactionalChannelAndReturnWriter() {
 	writer, err := this.connection.Writer(context.Background())
 
@@ -87,6 +140,25 @@ func (this *ConnectionFixture) TestWhenOpeningChannelForWriterFails_ReturnUnderl
 	this.So(err, should.Equal, this.channelError)
 }
 
+func (this *ConnectionFixture) TestWhenOpeningWriterWithPool_ShareThePoolUntilConnectionClosed() {
+	this.connection = newConnection(this, time.Time{}, configuration{Monitor: nop{}, Logger: nop{}, WriterPoolSize: 4})
+	pool := this.connection.(*defaultConnection).pool
+
+	writer1, err1 := this.connection.Writer(context.Background())
+	writer2, err2 := this.connection.Writer(context.Background())
+	_ = writer1.Close()
+
+	this.So(err1, should.BeNil)
+	this.So(err2, should.BeNil)
+	this.So(writer1, should.Equal, pooledWriter{writerPool: pool})
+	this.So(writer2, should.Equal, pooledWriter{writerPool: pool})
+	this.So(cap(pool.channels), should.Equal, 4)
+
+	_ = this.connection.Close()
+	_, err := writer2.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+	this.So(err, should.Equal, ErrWriterPoolClosed)
+}
+
 func (this *ConnectionFixture) TestWhenClosing_InvokeUnderlyingConnection() {
 	this.closeError = errors.New("")
 
@@ -94,23 +166,140 @@ func (this *ConnectionFixture) TestWhenClosing_InvokeUnderlyingConnection() {
 
 	this.So(err, should.Equal, this.closeError)
 }
+func (this *ConnectionFixture) TestWhenClosing_ReportClosedWithoutReasonOnce() {
+	this.initializeMonitoredConnection()
+
+	_ = this.connection.Close()
+	_ = this.connection.Close()
+	close(this.closes[0])
+
+	<-this.closures
+	this.So(len(this.closures), should.Equal, 0)
+	this.So(len(this.closedReasons), should.Equal, 0)
+}
+func (this *ConnectionFixture) TestWhenClosedByBroker_ReportClosedWithReason() {
+	this.initializeMonitoredConnection()
+
+	this.closes[0] <- &amqp.Error{Code: 320, Reason: "CONNECTION_FORCED", Server: true}
+
+	this.So(<-this.closedReasons, should.Equal, &ClosedError{ReplyCode: 320, ReplyText: "CONNECTION_FORCED"})
+	_ = this.connection.Close()
+	this.So(len(this.closures), should.Equal, 1)
+	this.So(len(this.closedReasons), should.Equal, 0)
+}
+func (this *ConnectionFixture) TestWhenMonitorLacksOptionalCallbacks_StillPauseWritersAndReportClosed() {
+	this.closes = nil
+	this.connection = newConnection(this, time.Time{}, configuration{Monitor: struct{ monitor }{this}, Logger: nop{}})
+	flow := this.connection.(*defaultConnection).flow
+
+	this.blocks <- amqp.Blocking{Active: true, Reason: "low on memory"}
+	select {
+	case <-flow.Unblocked():
+		this.blocks <- amqp.Blocking{Active: true, Reason: "low on memory"} // accepted only once the first is received
+	default:
+	}
+	this.closes[0] <- &amqp.Error{Code: 320, Reason: "CONNECTION_FORCED", Server: true}
+
+	<-this.closures
+	blocked, _ := flow.Blocked()
+	this.So(blocked, should.BeTrue)
+	this.So(len(this.blockedStates), should.Equal, 0)
+	this.So(len(this.closedReasons), should.Equal, 0)
+}
+func (this *ConnectionFixture) TestWhenBlockedByBroker_PauseWritersUntilUnblocked() {
+	this.initializeMonitoredConnection()
+	flow := this.connection.(*defaultConnection).flow
+
+	this.blocks <- amqp.Blocking{Active: true, Reason: "low on memory"}
+	this.So(<-this.blockedStates, should.BeTrue)
+	blocked, reason := flow.Blocked()
+	this.So(blocked, should.BeTrue)
+	this.So(reason, should.Equal, "low on memory")
+
+	this.blocks <- amqp.Blocking{Active: false}
+	this.So(<-this.blockedStates, should.BeFalse)
+	blocked, _ = flow.Blocked()
+	this.So(blocked, should.BeFalse)
+}
+func (this *ConnectionFixture) TestWhenCredentialsExpire_RefreshUntilClosed() {
+	config := configuration{}
+	Options.apply(Options.CredentialProvider(func(context.Context) (Credentials, error) {
+		panic("nop")
+	}))(&config)
+	this.connection = newConnection(this, time.Now().Add(time.Hour), config)
+
+	this.So(this.connection.(*defaultConnection).refresher, should.NotBeNil)
+	_ = this.connection.Close()
+	this.So(this.connection.(*defaultConnection).refresher.ctx.Err(), should.NotBeNil)
+}
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (this *ConnectionFixture) Channel() (adapter.Channel, error) { return this, this.channelError }
 func (this *ConnectionFixture) Close() error                      { return this.closeError }
+func (this *ConnectionFixture) NotifyClose(closes chan *amqp.Error) chan *amqp.Error {
+	this.closes = append(this.closes, closes)
+	return closes
+}
+func (this *ConnectionFixture) NotifyBlocked(blocks chan amqp.Blocking) chan amqp.Blocking {
+	this.blocks = blocks
+	return blocks
+}
+func (this *ConnectionFixture) UpdateSecret(_, _ string) error { panic("nop") }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ConnectionFixture) ConnectionClosed()                  { this.closures <- struct{}{} }
+func (this *ConnectionFixture) ConnectionClosedByBroker(err error) { this.closedReasons <- err }
+func (this *ConnectionFixture) ConnectionBlocked(active bool, _ string) {
+	this.blockedStates <- active
+}
+func (this *ConnectionFixture) ConnectionOpened(error)             {}
+func (this *ConnectionFixture) DispatchPublished()                 {}
+func (this *ConnectionFixture) DeliveryReceived()                  {}
+func (this *ConnectionFixture) DeliveryAcknowledged(uint16, error) {}
+func (this *ConnectionFixture) TransactionCommitted(error)         {}
+func (this *ConnectionFixture) TransactionRolledBack(error)        {}
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (this *ConnectionFixture) Tx() error { this.txCalls++; return this.txError }
+func (this *ConnectionFixture) Confirm(noWait bool) error {
+	this.confirmCalls++
+	this.confirmNoWait = noWait
+	return this.confirmError
+}
+func (this *ConnectionFixture) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	this.notifyCalls++
+	return confirm
+}
 
-func (this *ConnectionFixture) DeclareQueue(name string, replicated bool) error   { panic("nop") }
-func (this *ConnectionFixture) DeclareExchange(name string) error                 { panic("nop") }
-func (this *ConnectionFixture) BindQueue(queue, exchange string) error            { panic("nop") }
-func (this *ConnectionFixture) BufferCapacity(value uint16) error                 { panic("nop") }
-func (this *ConnectionFixture) Consume(_, _ string) (<-chan amqp.Delivery, error) { panic("nop") }
-func (this *ConnectionFixture) Ack(deliveryTag uint64, multiple bool) error       { panic("nop") }
-func (this *ConnectionFixture) CancelConsumer(consumerID string) error            { panic("nop") }
-func (this *ConnectionFixture) Publish(_, _ string, _ amqp.Publishing) error      { panic("nop") }
-func (this *ConnectionFixture) TxCommit() error                                   { panic("nop") }
-func (this *ConnectionFixture) TxRollback() error                                 { panic("nop") }
+func (this *ConnectionFixture) DeclareQueue(name string, replicated bool, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *ConnectionFixture) DeclareExchange(name, kind string, _ map[string]any) error {
+	panic("nop")
+}
+func (this *ConnectionFixture) BindQueue(queue, exchange, key string, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *ConnectionFixture) BufferCapacity(value uint16) error { panic("nop") }
+func (this *ConnectionFixture) Consume(_, _ string, _ map[string]any) (<-chan amqp.Delivery, error) {
+	panic("nop")
+}
+func (this *ConnectionFixture) Ack(deliveryTag uint64, multiple bool) error           { panic("nop") }
+func (this *ConnectionFixture) CancelConsumer(consumerID string) error                { panic("nop") }
+func (this *ConnectionFixture) Publish(_, _ string, _ amqp.Publishing) error          { panic("nop") }
+func (this *ConnectionFixture) TxCommit() error                                       { panic("nop") }
+func (this *ConnectionFixture) TxRollback() error                                     { panic("nop") }
+func (this *ConnectionFixture) GetNextPublishSeqNo() uint64                           { panic("nop") }
+func (this *ConnectionFixture) PublishMandatory(_, _ string, _ amqp.Publishing) error { panic("nop") }
+func (this *ConnectionFixture) NotifyReturn(chan amqp.Return) chan amqp.Return        { panic("nop") }
+func (this *ConnectionFixture) Nack(uint64, bool, bool) error                         { panic("nop") }
+func (this *ConnectionFixture) DeclareQueuePassive(string) error                      { panic("nop") }
+func (this *ConnectionFixture) DeclareExchangePassive(string, string) error           { panic("nop") }
+func (this *ConnectionFixture) DeleteQueue(string) error                              { panic("nop") }
+func (this *ConnectionFixture) DeleteExchange(string) error                           { panic("nop") }
+func (this *ConnectionFixture) UnbindQueue(string, string, string, map[string]any) error {
+	panic("nop")
+}
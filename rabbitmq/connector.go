@@ -5,71 +5,187 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/smarty/messaging/v3"
 	"github.com/smarty/messaging/v3/rabbitmq/adapter"
 )
 
 type defaultConnector struct {
-	inner   adapter.Connector
-	dialer  netDialer
-	broker  brokerEndpoint
-	config  configuration
-	monitor monitor
-	logger  logger
-
-	active []messaging.Connection
-	mutex  sync.Mutex
+	inner      adapter.Connector
+	dialer     netDialer
+	brokers    []brokerEndpoint
+	roundRobin bool
+	cooldown   time.Duration
+	now        func() time.Time
+	config     configuration
+	monitor    monitor
+	logger     logger
+
+	active      []messaging.Connection
+	next        int
+	unavailable []time.Time // the time until which the broker at each index is considered unavailable
+	mutex       sync.Mutex
 }
 
 func newConnector(config configuration) messaging.Connector {
 	return &defaultConnector{
-		inner:   config.Connector,
-		dialer:  config.Dialer,
-		broker:  config.Endpoint,
-		config:  config,
-		monitor: config.Monitor,
-		logger:  config.Logger,
+		inner:       config.Connector,
+		dialer:      config.Dialer,
+		brokers:     config.Endpoints,
+		roundRobin:  config.RoundRobin,
+		cooldown:    config.UnavailableCooldown,
+		now:         config.Now,
+		config:      config,
+		monitor:     config.Monitor,
+		logger:      config.Logger,
+		unavailable: make([]time.Time, len(config.Endpoints)),
 	}
 }
 
+// Connect obtains the credentials, if provided, once and then attempts each broker in turn. Only brokers which could
+// not be dialed or which failed the handshake are considered unavailable; neither a failure to obtain credentials nor
+// the cancellation of the context reflects upon the broker being attempted.
 func (this *defaultConnector) Connect(ctx context.Context) (messaging.Connection, error) {
-	hostAddress, config := this.configuration()
+	credentials, err := this.credentials(ctx)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to obtain credentials to connect [%s].", err)
+		this.monitor.ConnectionOpened(err)
+		return nil, err
+	}
+
+	for _, index := range this.candidates() {
+		var amqpConnection adapter.Connection
+		if amqpConnection, err = this.connect(ctx, this.brokers[index], credentials); err == nil {
+			this.markAvailable(index)
+			return this.track(amqpConnection, credentials), nil
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		this.markUnavailable(index, err)
+	}
+
+	this.monitor.ConnectionOpened(err)
+	return nil, err
+}
+func (this *defaultConnector) credentials(ctx context.Context) (*Credentials, error) {
+	if this.config.Credentials == nil {
+		return nil, nil
+	}
+
+	credentials, err := this.config.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentials, nil
+}
+func (this *defaultConnector) connect(ctx context.Context, broker brokerEndpoint, credentials *Credentials) (adapter.Connection, error) {
+	hostAddress := broker.Address.Host
+	config := this.configuration(broker, credentials)
 
 	var encryption = "plaintext"
-	if this.broker.Address.Scheme == "amqps" {
+	if broker.Address.Scheme == "amqps" {
 		encryption = "encrypted"
 	}
 
-	this.logger.Printf("[INFO] Establishing [%s] AMQP connection with user [%s] to [%s://%s] using virtual host [%s]...", encryption, config.Username, this.broker.Address.Scheme, hostAddress, config.VirtualHost)
+	this.logger.Printf("[INFO] Establishing [%s] AMQP connection with user [%s] to [%s://%s] using virtual host [%s]...", encryption, config.Username, broker.Address.Scheme, hostAddress, config.VirtualHost)
 	socket, err := this.dialer.DialContext(ctx, "tcp", hostAddress)
 	if err != nil {
-		this.logger.Printf("[WARN] Unable to connect [%s].", err)
-		this.monitor.ConnectionOpened(err)
+		this.logger.Printf("[WARN] Unable to connect to [%s] [%s].", hostAddress, err)
 		return nil, err
 	}
 
 	amqpConnection, err := this.inner.Connect(ctx, socket, config)
 	if err != nil {
-		this.logger.Printf("[WARN] Unable to connect [%s].", err)
-		this.config.Monitor.ConnectionOpened(err)
+		this.logger.Printf("[WARN] Unable to connect to [%s] [%s].", hostAddress, err)
 		return nil, err
 	}
 
-	this.logger.Printf("[INFO] Established [%s] AMQP connection with user [%s] to [%s://%s] using virtual host [%s].", encryption, config.Username, this.broker.Address.Scheme, hostAddress, config.VirtualHost)
+	this.logger.Printf("[INFO] Established [%s] AMQP connection with user [%s] to [%s://%s] using virtual host [%s].", encryption, config.Username, broker.Address.Scheme, hostAddress, config.VirtualHost)
+	if extended, ok := this.monitor.(brokerMonitor); ok {
+		extended.BrokerSelected(hostAddress)
+	}
+	return amqpConnection, nil
+}
+func (this *defaultConnector) track(amqpConnection adapter.Connection, credentials *Credentials) messaging.Connection {
+	var expiration time.Time
+	if credentials != nil {
+		expiration = credentials.Expiration
+	}
+
 	this.mutex.Lock()
 	defer this.mutex.Unlock()
-	this.active = append(this.active, newConnection(amqpConnection, this.config))
-	return this.active[len(this.active)-1], nil
+	this.active = append(this.active, newConnection(amqpConnection, expiration, this.config))
+	return this.active[len(this.active)-1]
 }
-func (this *defaultConnector) configuration() (string, adapter.Config) {
-	query := this.broker.Address.Query()
-	username, password := parseAuthentication(this.broker.Address.User, query.Get("username"), query.Get("password"))
-	return this.broker.Address.Host, adapter.Config{
+
+// candidates returns the index of each broker in the order in which connecting should be attempted: those available
+// (starting with the first or, with round-robin, the next broker) followed by those which recently failed.
+func (this *defaultConnector) candidates() []int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	start := 0
+	if this.roundRobin {
+		start = this.next
+		this.next = (this.next + 1) % len(this.brokers)
+	}
+
+	now := this.now()
+	available := make([]int, 0, len(this.brokers))
+	var unavailable []int
+	for i := range this.brokers {
+		index := (start + i) % len(this.brokers)
+		if now.Before(this.unavailable[index]) {
+			unavailable = append(unavailable, index)
+		} else {
+			available = append(available, index)
+		}
+	}
+
+	return append(available, unavailable...)
+}
+func (this *defaultConnector) markAvailable(index int) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.unavailable[index] = time.Time{}
+}
+func (this *defaultConnector) markUnavailable(index int, err error) {
+	this.mutex.Lock()
+	this.unavailable[index] = this.now().Add(this.cooldown)
+	this.mutex.Unlock()
+
+	if extended, ok := this.monitor.(brokerMonitor); ok {
+		extended.BrokerUnavailable(this.brokers[index].Address.Host, err)
+	}
+}
+
+// configuration returns the configuration with which to connect to the broker using the credentials of its address or,
+// if obtained, of the credential provider.
+func (this *defaultConnector) configuration(broker brokerEndpoint, credentials *Credentials) adapter.Config {
+	query := broker.Address.Query()
+	username, password := parseAuthentication(broker.Address.User, query.Get("username"), query.Get("password"))
+	config := adapter.Config{
+		Mechanism:   adapter.MechanismPlain,
 		Username:    username,
 		Password:    password,
-		VirtualHost: parseVirtualHost(this.broker.Address.Path),
+		VirtualHost: parseVirtualHost(broker.Address.Path),
 	}
+
+	if this.config.ExternalAuth {
+		config.Mechanism = adapter.MechanismExternal
+	}
+
+	if credentials != nil {
+		config.Username = coalesce(credentials.Username, config.Username)
+		config.Password = credentials.Password
+	}
+
+	return config
 }
 func parseAuthentication(info *url.Userinfo, queryUsername, queryPassword string) (string, string) {
 	if info == nil {
@@ -3,7 +3,12 @@ package rabbitmq
 import (
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"net/url"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/messaging/v3"
 )
 
 type brokerEndpoint struct {
@@ -11,6 +16,23 @@ type brokerEndpoint struct {
 	TLSConfig *tls.Config
 }
 
+// Credentials are the username and password (or, with OAuth 2.0, the access token) with which to authenticate. When
+// specified, the Expiration indicates when the password must be refreshed.
+type Credentials struct {
+	Username   string
+	Password   string
+	Expiration time.Time
+}
+
+type bindingTarget struct {
+	Queue string
+	Topic string
+}
+type binding struct {
+	Key       string
+	Arguments map[string]any
+}
+
 type monitor interface {
 	ConnectionOpened(error)
 	ConnectionClosed()
@@ -20,11 +42,111 @@ type monitor interface {
 	TransactionCommitted(error)
 	TransactionRolledBack(error)
 }
+
+// brokerMonitor is optionally implemented by a monitor to be informed of the broker with which each connection is
+// established and of each broker which could not be reached when connecting to a cluster.
+type brokerMonitor interface {
+	BrokerSelected(address string)
+	BrokerUnavailable(address string, err error)
+}
+
+// connectionMonitor is optionally implemented by a monitor to be informed of the reason the broker closed a connection
+// (in addition to ConnectionClosed) and of each time the broker blocks or unblocks a connection.
+type connectionMonitor interface {
+	ConnectionClosedByBroker(err error)
+	ConnectionBlocked(active bool, reason string)
+}
+
+// deliveryMonitor is optionally implemented by a monitor to be informed of each time deliveries are rejected, as it is
+// of each time deliveries are acknowledged.
+type deliveryMonitor interface {
+	DeliveryRejected(count uint16, requeue bool, err error)
+}
+
 type logger interface {
 	Printf(format string, args ...any)
 }
 
 var (
-	ErrAlreadyExclusive = errors.New("unable to open additional stream, an exclusive stream already exists")
-	ErrMultipleStreams  = errors.New("unable to open exclusive stream, another stream already exists")
+	ErrAlreadyExclusive     = errors.New("unable to open additional stream, an exclusive stream already exists")
+	ErrMultipleStreams      = errors.New("unable to open exclusive stream, another stream already exists")
+	ErrPublishNotAcked      = errors.New("the broker rejected one or more of the messages published")
+	ErrConfirmTimeout       = errors.New("timeout awaiting broker confirmation of the messages published")
+	ErrWriterPoolClosed     = errors.New("unable to write, the connection of the pooled writer has been closed")
+	ErrDelayNotSupported    = errors.New("unable to write dispatch, no delay tier (or delayed-message exchange) supports the delay")
+	ErrStreamQueueArguments = errors.New("unable to open stream, queue arguments are not supported by stream queues")
+)
+
+// StreamOffsetSequence is the StreamOffset with which a stream queue is read from the offset indicated by Sequence
+// even when it is zero, which would otherwise indicate that no Sequence was specified.
+const StreamOffsetSequence = "sequence"
+
+// The types of exchange which may be declared for a topic.
+const (
+	ExchangeFanout  = "fanout"
+	ExchangeTopic   = "topic"
+	ExchangeDirect  = "direct"
+	ExchangeHeaders = "headers"
+
+	// ExchangeDelayedMessage is the type of exchange provided by the delayed-message exchange plugin, which routes
+	// each message as an exchange of its "x-delayed-type" argument would once the message's delay has elapsed.
+	ExchangeDelayedMessage = "x-delayed-message"
 )
+
+// HeaderRoutingKey is the dispatch header which, when present, specifies the routing key with which the dispatch is
+// published. The header itself is not published. Without it, the Partition of the dispatch, if any, is used.
+const HeaderRoutingKey = messaging.HeaderRoutingKey
+
+// ReturnedDispatch describes a dispatch published as mandatory which the broker returned because it could not be routed
+// to any queue.
+type ReturnedDispatch struct {
+	Topic       string
+	RoutingKey  string
+	MessageID   uint64
+	MessageType string
+	ReplyCode   uint16
+	ReplyText   string
+}
+
+// UnroutableError is returned by Commit when one or more of the dispatches written were returned by the broker as
+// unroutable. The transaction is nevertheless committed; the returned dispatches were discarded by the broker.
+type UnroutableError struct {
+	Returned []ReturnedDispatch
+}
+
+func (this *UnroutableError) Error() string {
+	first := this.Returned[0]
+	return fmt.Sprintf("the broker returned %d unroutable dispatch(es), including message [%d] to topic [%s]: %s",
+		len(this.Returned), first.MessageID, first.Topic, first.ReplyText)
+}
+
+// MissingTopologyError indicates that the queue or exchange named, which is expected to exist when verifying the
+// topology of a stream, does not exist.
+type MissingTopologyError struct {
+	Kind   string // "queue" or "exchange"
+	Name   string
+	Stream string
+	inner  error
+}
+
+func (this *MissingTopologyError) Error() string {
+	return fmt.Sprintf("the %s [%s] required by stream [%s] does not exist", this.Kind, this.Name, this.Stream)
+}
+func (this *MissingTopologyError) Unwrap() error { return this.inner }
+
+// ClosedError indicates that the broker closed the channel or its connection, e.g. because a queue or exchange did not
+// exist (reply code 404), access was refused (403), or the connection was forced closed by an operator (320). Reads,
+// writes, and acknowledgements against the closed channel fail with this error; a new connection must be established.
+type ClosedError struct {
+	ReplyCode   int
+	ReplyText   string
+	Recoverable bool // whether the broker indicated the condition may be temporary
+}
+
+func newClosedError(source *amqp.Error) *ClosedError {
+	return &ClosedError{ReplyCode: source.Code, ReplyText: source.Reason, Recoverable: source.Recover}
+}
+func (this *ClosedError) Error() string {
+	return fmt.Sprintf("the broker closed the channel with reply code [%d]: %s", this.ReplyCode, this.ReplyText)
+}
+func (this *ClosedError) Unwrap() error { return amqp.ErrClosed }
@@ -0,0 +1,101 @@
+package rabbitmq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3/rabbitmq/adapter"
+)
+
+// credentialRefresher invokes the credential provider prior to the expiration of the credentials with which the
+// connection was authenticated and updates the secret of the connection with the password (or token) provided. It
+// continues doing so for as long as the credentials provided specify an expiration and the connection remains open.
+type credentialRefresher struct {
+	inner    adapter.Connection
+	provider func(context.Context) (Credentials, error)
+	margin   time.Duration
+	now      func() time.Time
+	logger   logger
+
+	ctx      context.Context
+	shutdown context.CancelFunc
+	mutex    sync.Mutex
+	timer    *time.Timer
+}
+
+func newCredentialRefresher(inner adapter.Connection, expiration time.Time, config configuration) *credentialRefresher {
+	ctx, shutdown := context.WithCancel(context.Background())
+	this := &credentialRefresher{
+		inner:    inner,
+		provider: config.Credentials,
+		margin:   config.RefreshMargin,
+		now:      config.Now,
+		logger:   config.Logger,
+		ctx:      ctx,
+		shutdown: shutdown,
+	}
+
+	this.schedule(expiration)
+	return this
+}
+
+// schedule refreshes the credentials at the margin prior to their expiration. Credentials whose remaining lifetime is
+// no longer than the margin are instead refreshed once half of their remaining lifetime (but no less than a second) has
+// elapsed, such that a provider of short-lived credentials does not cause them to be refreshed continuously.
+func (this *credentialRefresher) schedule(expiration time.Time) {
+	remaining := expiration.Sub(this.now())
+	delay := remaining - this.margin
+	if delay <= 0 {
+		this.logger.Printf("[WARN] AMQP connection credentials expire in [%s], sooner than the refresh margin [%s].", remaining, this.margin)
+		delay = max(remaining/2, minimumCredentialRefreshDelay)
+	}
+
+	this.scheduleAfter(delay)
+}
+func (this *credentialRefresher) scheduleAfter(delay time.Duration) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.ctx.Err() != nil {
+		return
+	}
+
+	this.timer = time.AfterFunc(max(delay, 0), this.refresh)
+}
+func (this *credentialRefresher) refresh() {
+	credentials, err := this.provider(this.ctx)
+	if err == nil {
+		err = this.inner.UpdateSecret(credentials.Password, "credentials refreshed")
+	}
+
+	if this.ctx.Err() != nil {
+		return // closed while refreshing
+	}
+
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to refresh AMQP connection credentials [%s], retrying...", err)
+		this.scheduleAfter(credentialRetryDelay)
+		return
+	}
+
+	this.logger.Printf("[INFO] Refreshed AMQP connection credentials.")
+	if !credentials.Expiration.IsZero() {
+		this.schedule(credentials.Expiration)
+	}
+}
+
+func (this *credentialRefresher) Close() {
+	this.shutdown()
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.timer != nil {
+		this.timer.Stop()
+	}
+}
+
+const (
+	credentialRetryDelay          = time.Second * 5
+	minimumCredentialRefreshDelay = time.Second
+)
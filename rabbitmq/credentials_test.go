@@ -0,0 +1,163 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3/rabbitmq/adapter"
+)
+
+func TestCredentialRefresherFixture(t *testing.T) {
+	gunit.Run(new(CredentialRefresherFixture), t)
+}
+
+type CredentialRefresherFixture struct {
+	*gunit.Fixture
+
+	now       time.Time
+	refresher *credentialRefresher
+
+	provided      []Credentials
+	providerError error
+	providerCalls int
+	updated       chan string
+	updateReason  string
+	updateError   error
+	logged        chan string
+}
+
+func (this *CredentialRefresherFixture) Setup() {
+	this.now = time.Now()
+	this.updated = make(chan string, 16)
+	this.logged = make(chan string, 16)
+}
+func (this *CredentialRefresherFixture) Teardown() {
+	if this.refresher != nil {
+		this.refresher.Close()
+	}
+}
+func (this *CredentialRefresherFixture) initializeRefresher(expiration time.Time) {
+	config := configuration{}
+	Options.apply(
+		Options.CredentialProvider(this.provide),
+		Options.CredentialRefreshMargin(time.Minute),
+		Options.Now(func() time.Time { return this.now }),
+		Options.Logger(this),
+	)(&config)
+	this.refresher = newCredentialRefresher(this, expiration, config)
+}
+
+func (this *CredentialRefresherFixture) TestWhenCredentialsAboutToExpire_UpdateSecretWithCredentialsProvided() {
+	this.provided = []Credentials{{Password: "token-1"}}
+
+	this.initializeRefresher(this.now.Add(time.Minute + time.Millisecond))
+
+	this.So(this.awaitUpdate(), should.Equal, "token-1")
+	this.So(this.updateReason, should.NotBeEmpty)
+}
+func (this *CredentialRefresherFixture) TestWhenRefreshedCredentialsExpire_RefreshAgain() {
+	this.provided = []Credentials{
+		{Password: "token-1", Expiration: this.now.Add(time.Minute + time.Millisecond)},
+		{Password: "token-2", Expiration: this.now.Add(time.Hour)},
+	}
+
+	this.initializeRefresher(this.now.Add(time.Minute + time.Millisecond))
+
+	this.So(this.awaitUpdate(), should.Equal, "token-1")
+	this.So(this.awaitUpdate(), should.Equal, "token-2")
+}
+func (this *CredentialRefresherFixture) TestWhenCredentialsNotYetAboutToExpire_DoNotRefresh() {
+	this.initializeRefresher(this.now.Add(time.Hour))
+
+	this.So(this.providerCalls, should.Equal, 0)
+}
+func (this *CredentialRefresherFixture) TestWhenLifetimeShorterThanMargin_WarnAndDelayRefresh() {
+	this.provided = []Credentials{{Password: "token-1"}}
+
+	this.initializeRefresher(this.now.Add(time.Second * 30))
+
+	this.So(this.awaitLog(), should.ContainSubstring, "sooner than the refresh margin")
+	this.So(len(this.updated), should.Equal, 0)
+	this.So(this.refresher.timer.Stop(), should.BeTrue) // refresh pending after half of the remaining lifetime
+}
+func (this *CredentialRefresherFixture) TestWhenAlreadyExpired_WarnAndDelayRefreshByMinimum() {
+	this.provided = []Credentials{{Password: "token-1"}}
+
+	this.initializeRefresher(this.now.Add(-time.Hour))
+
+	this.So(this.awaitLog(), should.ContainSubstring, "sooner than the refresh margin")
+	this.So(len(this.updated), should.Equal, 0)
+	this.So(this.refresher.timer.Stop(), should.BeTrue)
+}
+func (this *CredentialRefresherFixture) TestWhenProviderFails_LogAndDoNotUpdateSecret() {
+	this.providerError = errors.New("provider failure")
+
+	this.initializeRefresher(this.now.Add(time.Minute + time.Millisecond))
+
+	this.So(this.awaitLog(), should.ContainSubstring, "provider failure")
+	this.So(len(this.updated), should.Equal, 0)
+}
+func (this *CredentialRefresherFixture) TestWhenUpdatingSecretFails_Log() {
+	this.provided = []Credentials{{Password: "token-1"}}
+	this.updateError = errors.New("update failure")
+
+	this.initializeRefresher(this.now.Add(time.Minute + time.Millisecond))
+
+	this.So(this.awaitLog(), should.ContainSubstring, "update failure")
+}
+
+func (this *CredentialRefresherFixture) awaitUpdate() string {
+	select {
+	case secret := <-this.updated:
+		return secret
+	case <-time.After(time.Millisecond * 250):
+		return ""
+	}
+}
+func (this *CredentialRefresherFixture) awaitLog() string {
+	select {
+	case message := <-this.logged:
+		return message
+	case <-time.After(time.Millisecond * 250):
+		return ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *CredentialRefresherFixture) provide(_ context.Context) (Credentials, error) {
+	if this.providerCalls++; this.providerError != nil {
+		return Credentials{}, this.providerError
+	}
+
+	credentials := this.provided[0]
+	this.provided = this.provided[1:]
+	return credentials, nil
+}
+func (this *CredentialRefresherFixture) UpdateSecret(secret, reason string) error {
+	this.updateReason = reason
+	if this.updateError != nil {
+		return this.updateError
+	}
+
+	this.updated <- secret
+	return nil
+}
+func (this *CredentialRefresherFixture) Printf(format string, args ...any) {
+	this.logged <- fmt.Sprintf(format, args...)
+}
+
+func (this *CredentialRefresherFixture) Channel() (adapter.Channel, error) { panic("nop") }
+func (this *CredentialRefresherFixture) Close() error                      { panic("nop") }
+func (this *CredentialRefresherFixture) NotifyClose(chan *amqp.Error) chan *amqp.Error {
+	panic("nop")
+}
+func (this *CredentialRefresherFixture) NotifyBlocked(chan amqp.Blocking) chan amqp.Blocking {
+	panic("nop")
+}
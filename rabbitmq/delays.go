@@ -0,0 +1,66 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/messaging/v3"
+)
+
+// delayRouter determines the exchange to which each dispatch is published such that it's delivered no sooner than its
+// delay. Using the delayed-message exchange plugin, the dispatch is published to the exchange of its topic (which must
+// be of type ExchangeDelayedMessage) with the "x-delay" header. Otherwise, the dispatch is published to the delay tier
+// of its topic with the shortest delay not less than that of the dispatch; each tier is a fanout exchange bound to a
+// queue whose messages expire after the delay of the tier and are then dead-lettered to the exchange of the topic using
+// their original routing key. Because messages of a queue expire in order, each tier has a single, fixed delay.
+type delayRouter struct {
+	plugin bool
+	tiers  []time.Duration // ascending
+}
+
+func newDelayRouter(config configuration) delayRouter {
+	tiers := slices.Clone(config.DelayTiers)
+	slices.Sort(tiers)
+	return delayRouter{plugin: config.DelayedMessageExchange, tiers: tiers}
+}
+
+// Route returns the exchange to which the dispatch is published, updating the publishing with the delay, if any.
+func (this delayRouter) Route(dispatch messaging.Dispatch, publishing *amqp.Publishing, now time.Time) (string, error) {
+	delay := dispatch.Delay
+	if !dispatch.DeliveryTime.IsZero() {
+		delay = dispatch.DeliveryTime.Sub(now)
+	}
+
+	if delay <= 0 {
+		return dispatch.Topic, nil
+	}
+
+	if this.plugin {
+		publishing.Headers = maps.Clone(publishing.Headers)
+		if publishing.Headers == nil {
+			publishing.Headers = make(amqp.Table, 1)
+		}
+		publishing.Headers[headerDelay] = delay.Milliseconds()
+		return dispatch.Topic, nil
+	}
+
+	for _, tier := range this.tiers {
+		if tier >= delay {
+			// the broker removes the expiration of a message when dead-lettering it, so it cannot also be applied
+			publishing.Expiration = ""
+			return DelayTier(dispatch.Topic, tier), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: [%s] for topic [%s]", ErrDelayNotSupported, delay, dispatch.Topic)
+}
+
+// DelayTier is the name of both the exchange and the queue of the delay tier of the topic specified.
+func DelayTier(topic string, delay time.Duration) string {
+	return fmt.Sprintf("%s.delay.%s", topic, delay)
+}
+
+const headerDelay = "x-delay"
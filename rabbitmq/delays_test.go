@@ -0,0 +1,86 @@
+package rabbitmq
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestDelayRouterFixture(t *testing.T) {
+	gunit.Run(new(DelayRouterFixture), t)
+}
+
+type DelayRouterFixture struct {
+	*gunit.Fixture
+
+	now        time.Time
+	publishing amqp.Publishing
+}
+
+func (this *DelayRouterFixture) Setup() {
+	this.now = time.Now()
+	this.publishing = amqp.Publishing{Expiration: "60"}
+}
+
+func (this *DelayRouterFixture) TestWhenNotDelayed_PublishToTopic() {
+	router := newDelayRouter(configuration{})
+
+	exchange, err := router.Route(messaging.Dispatch{Topic: "orders"}, &this.publishing, this.now)
+
+	this.So(err, should.BeNil)
+	this.So(exchange, should.Equal, "orders")
+	this.So(this.publishing, should.Equal, amqp.Publishing{Expiration: "60"})
+}
+func (this *DelayRouterFixture) TestWhenDeliveryTimeHasPassed_PublishToTopic() {
+	router := newDelayRouter(configuration{})
+	dispatch := messaging.Dispatch{Topic: "orders", Delay: time.Hour, DeliveryTime: this.now.Add(-time.Second)}
+
+	exchange, err := router.Route(dispatch, &this.publishing, this.now)
+
+	this.So(err, should.BeNil)
+	this.So(exchange, should.Equal, "orders")
+}
+func (this *DelayRouterFixture) TestWhenDelayedWithoutTiersOrPlugin_ReturnError() {
+	router := newDelayRouter(configuration{})
+
+	exchange, err := router.Route(messaging.Dispatch{Topic: "orders", Delay: time.Second}, &this.publishing, this.now)
+
+	this.So(exchange, should.BeEmpty)
+	this.So(errors.Is(err, ErrDelayNotSupported), should.BeTrue)
+}
+func (this *DelayRouterFixture) TestWhenDelayedUsingPlugin_PublishToTopicWithDelayHeader() {
+	router := newDelayRouter(configuration{DelayedMessageExchange: true, DelayTiers: []time.Duration{time.Hour}})
+	headers := amqp.Table{"other": "value"}
+	this.publishing.Headers = headers
+
+	exchange, err := router.Route(messaging.Dispatch{Topic: "orders", Delay: time.Minute}, &this.publishing, this.now)
+
+	this.So(err, should.BeNil)
+	this.So(exchange, should.Equal, "orders")
+	this.So(this.publishing.Headers, should.Equal, amqp.Table{"other": "value", "x-delay": int64(60_000)})
+	this.So(this.publishing.Expiration, should.Equal, "60")
+	this.So(headers, should.HaveLength, 1) // the dispatch's headers are unmodified
+}
+func (this *DelayRouterFixture) TestWhenScheduledUsingTiers_PublishToShortestSufficientTier() {
+	router := newDelayRouter(configuration{DelayTiers: []time.Duration{time.Hour, time.Second * 10, time.Minute}})
+	dispatch := messaging.Dispatch{Topic: "orders", DeliveryTime: this.now.Add(time.Second * 10)}
+
+	exchange, err := router.Route(dispatch, &this.publishing, this.now)
+
+	this.So(err, should.BeNil)
+	this.So(exchange, should.Equal, "orders.delay.10s")
+	this.So(this.publishing.Expiration, should.BeEmpty)
+}
+func (this *DelayRouterFixture) TestWhenDelayExceedsEveryTier_ReturnError() {
+	router := newDelayRouter(configuration{DelayTiers: []time.Duration{time.Minute}})
+
+	_, err := router.Route(messaging.Dispatch{Topic: "orders", Delay: time.Minute + 1}, &this.publishing, this.now)
+
+	this.So(errors.Is(err, ErrDelayNotSupported), should.BeTrue)
+	this.So(err.Error(), should.ContainSubstring, "orders")
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"sync"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/smarty/messaging/v3"
@@ -36,6 +38,10 @@ func (this *defaultReader) Stream(_ context.Context, settings messaging.StreamCo
 	if settings.ExclusiveStream && len(this.streams) > 0 {
 		return nil, ErrMultipleStreams
 	}
+	if settings.StreamQueue && settings.QueueArguments != (messaging.QueueArguments{}) {
+		this.logger.Printf("[WARN] Unable to open stream [%s], a stream queue does not support queue arguments.", settings.StreamName)
+		return nil, ErrStreamQueueArguments // the broker would reject (or ignore) them
+	}
 
 	if err := this.establishTopology(settings); err != nil {
 		_ = this.inner.Close()
@@ -49,7 +55,7 @@ func (this *defaultReader) Stream(_ context.Context, settings messaging.StreamCo
 	}
 
 	streamID := strconv.FormatUint(this.counter, 10)
-	deliveries, err := this.inner.Consume(streamID, settings.StreamName)
+	deliveries, err := this.inner.Consume(streamID, settings.StreamName, consumerArguments(settings))
 	if err != nil {
 		this.logger.Printf("[WARN] Unable to open consumer for stream (channel) [%s]:", settings.StreamName, err)
 		_ = this.inner.Close()
@@ -65,22 +71,32 @@ func (this *defaultReader) Stream(_ context.Context, settings messaging.StreamCo
 }
 func (this *defaultReader) establishTopology(config messaging.StreamConfig) error {
 	if !config.EstablishTopology {
-		return nil
+		return this.verifyTopology(config)
+	}
+
+	if err := this.establishDeadLetterTopology(config); err != nil {
+		return err
 	}
 
-	if err := this.inner.DeclareQueue(config.StreamName, config.StreamReplication); err != nil {
+	arguments := queueArguments(config.QueueArguments)
+	if config.StreamQueue {
+		arguments["x-queue-type"] = "stream"
+	}
+	if err := this.inner.DeclareQueue(config.StreamName, config.StreamReplication, arguments); err != nil {
 		this.logger.Printf("[WARN] Unable to establish topology, queue declaration failed [%s].", err)
 		return err
 	}
 
 	for _, topic := range config.Topics {
-		if err := this.inner.DeclareExchange(topic); err != nil {
+		if err := this.declareExchange(topic); err != nil {
 			this.logger.Printf("[WARN] Unable to establish topology for subscriber on stream [%s]; exchange declaration failed for topic [%s]: %s", config.StreamName, topic, err)
 			return err
 		}
-		if err := this.inner.BindQueue(config.StreamName, topic); err != nil {
-			this.logger.Printf("[WARN] Unable to establish topology for subscriber on stream [%s]; stream (queue) binding failed [%s]: %s", config.StreamName, topic, err)
-			return err
+		for _, item := range this.bindings(config.StreamName, topic) {
+			if err := this.inner.BindQueue(config.StreamName, topic, item.Key, item.Arguments); err != nil {
+				this.logger.Printf("[WARN] Unable to establish topology for subscriber on stream [%s]; stream (queue) binding failed [%s]: %s", config.StreamName, topic, err)
+				return err
+			}
 		}
 	}
 
@@ -89,7 +105,7 @@ func (this *defaultReader) establishTopology(config messaging.StreamConfig) erro
 			continue
 		}
 
-		if err := this.inner.DeclareExchange(topic); err != nil {
+		if err := this.declareExchange(topic); err != nil {
 			this.logger.Printf("[WARN] Unable to establish general topology of available topics; exchange declaration failed for topic [%s]: %s", topic, err)
 			return err
 		}
@@ -98,6 +114,160 @@ func (this *defaultReader) establishTopology(config messaging.StreamConfig) erro
 	return nil
 }
 
+// declareExchange declares the exchange of the topic which, when using the delayed-message exchange plugin, routes
+// messages as an exchange of its configured type would once their delay has elapsed.
+func (this *defaultReader) declareExchange(topic string) error {
+	if !this.config.DelayedMessageExchange {
+		return this.inner.DeclareExchange(topic, this.exchangeType(topic), nil)
+	}
+
+	return this.inner.DeclareExchange(topic, ExchangeDelayedMessage, map[string]any{"x-delayed-type": this.exchangeType(topic)})
+}
+
+func (this *defaultReader) establishDeadLetterTopology(config messaging.StreamConfig) error {
+	exchange := config.QueueArguments.DeadLetterExchange
+	if len(exchange) == 0 {
+		return nil
+	}
+
+	queue := coalesce(config.QueueArguments.DeadLetterQueue, exchange)
+	if err := this.inner.DeclareExchange(exchange, this.exchangeType(exchange), nil); err != nil {
+		this.logger.Printf("[WARN] Unable to establish topology for stream [%s]; dead-letter exchange declaration failed [%s]: %s", config.StreamName, exchange, err)
+		return err
+	}
+	if err := this.inner.DeclareQueue(queue, config.StreamReplication, nil); err != nil {
+		this.logger.Printf("[WARN] Unable to establish topology for stream [%s]; dead-letter queue declaration failed [%s]: %s", config.StreamName, queue, err)
+		return err
+	}
+	for _, item := range this.bindings(queue, exchange) {
+		if err := this.inner.BindQueue(queue, exchange, item.Key, item.Arguments); err != nil {
+			this.logger.Printf("[WARN] Unable to establish topology for stream [%s]; dead-letter queue binding failed [%s]: %s", config.StreamName, queue, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyTopology passively declares the queue and exchanges of the stream, which requires no permission to configure
+// them. The broker closes the channel upon the first which does not exist, so verification stops at that point.
+func (this *defaultReader) verifyTopology(config messaging.StreamConfig) error {
+	if !config.VerifyTopology {
+		return nil
+	}
+
+	if exchange := config.QueueArguments.DeadLetterExchange; len(exchange) > 0 {
+		if err := this.verifyExchange(config.StreamName, exchange); err != nil {
+			return err
+		}
+		if err := this.verifyQueue(config.StreamName, coalesce(config.QueueArguments.DeadLetterQueue, exchange)); err != nil {
+			return err
+		}
+	}
+
+	if err := this.verifyQueue(config.StreamName, config.StreamName); err != nil {
+		return err
+	}
+
+	for _, topic := range append(slices.Clone(config.Topics), config.AvailableTopics...) {
+		if len(topic) == 0 {
+			continue
+		}
+
+		if err := this.verifyExchange(config.StreamName, topic); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+func (this *defaultReader) verifyQueue(stream, queue string) error {
+	return this.verified(stream, kindQueue, queue, this.inner.DeclareQueuePassive(queue))
+}
+func (this *defaultReader) verifyExchange(stream, exchange string) error {
+	return this.verified(stream, kindExchange, exchange, this.inner.DeclareExchangePassive(exchange, this.exchangeType(exchange)))
+}
+func (this *defaultReader) verified(stream, kind, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	this.logger.Printf("[WARN] Unable to verify topology for stream [%s]; %s [%s] verification failed: %s", stream, kind, name, err)
+	if brokerError, ok := err.(*amqp.Error); ok && brokerError.Code == http.StatusNotFound {
+		return &MissingTopologyError{Kind: kind, Name: name, Stream: stream, inner: err}
+	}
+
+	return err
+}
+
+func queueArguments(config messaging.QueueArguments) map[string]any {
+	arguments := make(map[string]any)
+	if len(config.DeadLetterExchange) > 0 {
+		arguments["x-dead-letter-exchange"] = config.DeadLetterExchange
+	}
+	if len(config.DeadLetterRoutingKey) > 0 {
+		arguments["x-dead-letter-routing-key"] = config.DeadLetterRoutingKey
+	}
+	if config.MessageTTL > 0 {
+		arguments["x-message-ttl"] = config.MessageTTL.Milliseconds()
+	}
+	if config.MaxLength > 0 {
+		arguments["x-max-length"] = int64(config.MaxLength)
+	}
+	if len(config.Overflow) > 0 {
+		arguments["x-overflow"] = config.Overflow
+	}
+	if config.DeliveryLimit > 0 {
+		arguments["x-delivery-limit"] = int64(config.DeliveryLimit)
+	}
+	if config.SingleActiveConsumer {
+		arguments["x-single-active-consumer"] = true
+	}
+	if config.MaxPriority > 0 {
+		arguments["x-max-priority"] = int64(config.MaxPriority)
+	}
+
+	return arguments
+}
+
+// consumerArguments specifies the offset from which a stream queue is consumed, being the Sequence, if any (or if
+// explicitly requested using StreamOffsetSequence), otherwise the StreamOffset, which is either one of "first",
+// "last", or "next", or a timestamp.
+func consumerArguments(config messaging.StreamConfig) map[string]any {
+	if !config.StreamQueue {
+		return nil
+	}
+
+	if config.Sequence > 0 || config.StreamOffset == StreamOffsetSequence {
+		return map[string]any{"x-stream-offset": int64(config.Sequence)}
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339Nano, config.StreamOffset); err == nil {
+		return map[string]any{"x-stream-offset": timestamp}
+	}
+
+	return map[string]any{"x-stream-offset": coalesce(config.StreamOffset, defaultStreamOffset)}
+}
+
+func (this *defaultReader) exchangeType(topic string) string {
+	if kind, contains := this.config.ExchangeTypes[topic]; contains {
+		return kind
+	}
+
+	return ExchangeFanout
+}
+func (this *defaultReader) bindings(queue, topic string) []binding {
+	if bindings := this.config.Bindings[bindingTarget{Queue: queue, Topic: topic}]; len(bindings) > 0 {
+		return bindings
+	}
+
+	if this.exchangeType(topic) == ExchangeTopic {
+		return []binding{{Key: "#"}} // every routing key
+	}
+
+	return []binding{{}}
+}
+
 func (this *defaultReader) tryPanic(err error) error {
 	if err == nil || !this.config.TopologyFailurePanic {
 		return err
@@ -122,3 +292,10 @@ func (this *defaultReader) Close() error {
 	this.streams = this.streams[0:0]
 	return this.inner.Close()
 }
+
+const (
+	defaultStreamOffset = "next"
+
+	kindQueue    = "queue"
+	kindExchange = "exchange"
+)
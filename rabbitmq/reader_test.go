@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/smarty/gunit"
@@ -23,16 +24,27 @@ type ReaderFixture struct {
 	reader messaging.Reader
 
 	declareQueueName       string
+	declareQueueNames      []string
+	declareQueueReplicated []bool
+	declareQueueArguments  []map[string]any
 	declareQueueError      error
 	declareExchangeNames   []string
+	declareExchangeKinds   []string
+	declareExchangeArgs    []map[string]any
 	declareExchangeError   error
 	bindQueueQueueNames    []string
 	bindQueueExchangeNames []string
+	bindQueueKeys          []string
+	bindQueueArguments     []map[string]any
 	bindQueueError         error
+	passiveQueueNames      []string
+	passiveExchangeNames   []string
+	passiveErrors          map[string]error
 	bufferCapacityValue    uint16
 	bufferCapacityError    error
 	consumeConsumerID      string
 	consumeQueue           string
+	consumeArguments       map[string]any
 	consumeChannel         chan amqp.Delivery
 	consumeError           error
 	callsToClose           int
@@ -41,11 +53,13 @@ type ReaderFixture struct {
 
 func (this *ReaderFixture) Setup() {
 	this.consumeChannel = make(chan amqp.Delivery, 4)
+	this.passiveErrors = make(map[string]error)
 	this.initializeReader()
 }
-func (this *ReaderFixture) initializeReader() {
+func (this *ReaderFixture) initializeReader(options ...option) {
 	config := configuration{}
-	Options.apply(Options.PanicOnTopologyError(this.configPanicOnTopologyFailure))(&config)
+	options = append(options, Options.PanicOnTopologyError(this.configPanicOnTopologyFailure))
+	Options.apply(options...)(&config)
 	this.reader = newReader(this, config)
 }
 
@@ -63,8 +77,10 @@ func (this *ReaderFixture) TestWhenEstablishingAStream_StartConsumerOnFromUnderl
 
 	this.So(this.declareQueueName, should.Equal, "queue")
 	this.So(this.declareExchangeNames, should.Equal, []string{"topic1", "topic2"})
+	this.So(this.declareExchangeKinds, should.Equal, []string{ExchangeFanout, ExchangeFanout})
 	this.So(this.bindQueueQueueNames, should.Equal, []string{"queue", "queue"})
 	this.So(this.bindQueueExchangeNames, should.Equal, []string{"topic1", "topic2"})
+	this.So(this.bindQueueKeys, should.Equal, []string{"", ""})
 	this.So(this.bufferCapacityValue, should.Equal, 2)
 	this.So(this.consumeConsumerID, should.Equal, "0")
 	this.So(this.consumeQueue, should.Equal, "queue")
@@ -144,6 +160,61 @@ func (this *ReaderFixture) TestWhenTopologyRedeclarationConflictOccurs_CloseChan
 	this.So(this.callsToClose, should.Equal, 1)
 }
 
+func (this *ReaderFixture) TestWhenVerifyingTopology_PassivelyDeclareQueuesAndExchangesWithoutEstablishingThem() {
+	stream, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		VerifyTopology:  true,
+		StreamName:      "queue",
+		Topics:          []string{"topic1"},
+		AvailableTopics: []string{"topic2", ""},
+		QueueArguments:  messaging.QueueArguments{DeadLetterExchange: "dlx", DeadLetterQueue: "dlq"},
+	})
+
+	this.So(stream, should.NotBeNil)
+	this.So(err, should.BeNil)
+	this.So(this.passiveQueueNames, should.Equal, []string{"dlq", "queue"})
+	this.So(this.passiveExchangeNames, should.Equal, []string{"dlx", "topic1", "topic2"})
+	this.So(this.declareQueueNames, should.BeEmpty)
+	this.So(this.declareExchangeNames, should.BeEmpty)
+	this.So(this.bindQueueQueueNames, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenVerifyingTopologyWithMissingObject_CloseChannelAndReturnErrorNamingIt() {
+	this.configPanicOnTopologyFailure = true
+	this.initializeReader()
+	this.passiveErrors["topic2"] = &amqp.Error{Code: 404, Reason: "NOT_FOUND - no exchange 'topic2'"}
+	config := messaging.StreamConfig{VerifyTopology: true, StreamName: "queue", Topics: []string{"topic1", "topic2", "topic3"}}
+
+	stream, err := this.reader.Stream(context.Background(), config)
+
+	var missing *MissingTopologyError
+	this.So(stream, should.BeNil)
+	this.So(errors.As(err, &missing), should.BeTrue)
+	this.So(missing.Kind, should.Equal, "exchange")
+	this.So(missing.Name, should.Equal, "topic2")
+	this.So(err.Error(), should.Equal, "the exchange [topic2] required by stream [queue] does not exist")
+	this.So(errors.Is(err, this.passiveErrors["topic2"]), should.BeTrue)
+	this.So(this.passiveExchangeNames, should.Equal, []string{"topic1", "topic2"})
+	this.So(this.callsToClose, should.Equal, 1)
+}
+func (this *ReaderFixture) TestWhenVerifyingTopologyFailsForAnotherReason_CloseChannelAndReturnError() {
+	this.passiveErrors["queue"] = &amqp.Error{Code: 403}
+	config := messaging.StreamConfig{VerifyTopology: true, StreamName: "queue"}
+
+	stream, err := this.reader.Stream(context.Background(), config)
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.passiveErrors["queue"])
+	this.So(this.callsToClose, should.Equal, 1)
+}
+func (this *ReaderFixture) TestWhenEstablishingTopology_DoNotAlsoVerifyIt() {
+	config := messaging.StreamConfig{EstablishTopology: true, VerifyTopology: true, StreamName: "queue"}
+
+	_, err := this.reader.Stream(context.Background(), config)
+
+	this.So(err, should.BeNil)
+	this.So(this.declareQueueNames, should.Equal, []string{"queue"})
+	this.So(this.passiveQueueNames, should.BeEmpty)
+}
+
 func (this *ReaderFixture) TestWhenTopologyAvailableTopicsDeclared_DeclareAllOfThem() {
 	stream, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
 		EstablishTopology: true,
@@ -165,6 +236,219 @@ func (this *ReaderFixture) TestWhenTopologyAvailableTopicsDeclared_DeclareAllOfT
 	this.So(this.consumeConsumerID, should.Equal, "0")
 	this.So(this.consumeQueue, should.Equal, "queue")
 }
+func (this *ReaderFixture) TestWhenExchangeTypesConfigured_DeclareExchangesOfThoseTypes() {
+	this.initializeReader(
+		Options.ExchangeType("orders", ExchangeTopic),
+		Options.ExchangeType("available", ExchangeHeaders))
+
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		Topics:            []string{"orders", "other"},
+		AvailableTopics:   []string{"available"},
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareExchangeNames, should.Equal, []string{"orders", "other", "available"})
+	this.So(this.declareExchangeKinds, should.Equal, []string{ExchangeTopic, ExchangeFanout, ExchangeHeaders})
+	this.So(this.bindQueueExchangeNames, should.Equal, []string{"orders", "other"})
+	this.So(this.bindQueueKeys, should.Equal, []string{"#", ""})
+}
+func (this *ReaderFixture) TestWhenUsingDelayedMessageExchange_DeclareTopicExchangesAsDelayedMessageExchanges() {
+	this.initializeReader(Options.DelayedMessageExchange(true), Options.ExchangeType("topic1", ExchangeTopic))
+
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		Topics:            []string{"topic1"},
+		AvailableTopics:   []string{"topic2"},
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareExchangeNames, should.Equal, []string{"topic1", "topic2"})
+	this.So(this.declareExchangeKinds, should.Equal, []string{ExchangeDelayedMessage, ExchangeDelayedMessage})
+	this.So(this.declareExchangeArgs, should.Equal, []map[string]any{
+		{"x-delayed-type": ExchangeTopic},
+		{"x-delayed-type": ExchangeFanout},
+	})
+	this.So(this.bindQueueKeys, should.Equal, []string{"#"})
+}
+func (this *ReaderFixture) TestWhenBindingsConfigured_BindQueueWithEachOfThem() {
+	arguments := map[string]any{"x-match": "all", "region": "us"}
+	this.initializeReader(
+		Options.ExchangeType("orders", ExchangeTopic),
+		Options.Binding("queue", "orders", "orders.*.created", nil),
+		Options.Binding("queue", "orders", "orders.*.cancelled", nil),
+		Options.Binding("other-queue", "orders", "ignored", nil),
+		Options.ExchangeType("regional", ExchangeHeaders),
+		Options.Binding("queue", "regional", "", arguments))
+
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		Topics:            []string{"orders", "regional"},
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.bindQueueQueueNames, should.Equal, []string{"queue", "queue", "queue"})
+	this.So(this.bindQueueExchangeNames, should.Equal, []string{"orders", "orders", "regional"})
+	this.So(this.bindQueueKeys, should.Equal, []string{"orders.*.created", "orders.*.cancelled", ""})
+	this.So(this.bindQueueArguments, should.Equal, []map[string]any{nil, nil, arguments})
+}
+func (this *ReaderFixture) TestWhenQueueArgumentsSpecified_DeclareQueueWithArguments() {
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		StreamReplication: true,
+		QueueArguments: messaging.QueueArguments{
+			MessageTTL:           time.Minute,
+			MaxLength:            100,
+			Overflow:             "reject-publish",
+			DeliveryLimit:        5,
+			SingleActiveConsumer: true,
+			MaxPriority:          10,
+		},
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareQueueNames, should.Equal, []string{"queue"})
+	this.So(this.declareQueueReplicated, should.Equal, []bool{true})
+	this.So(this.declareQueueArguments, should.Equal, []map[string]any{{
+		"x-message-ttl":            int64(60000),
+		"x-max-length":             int64(100),
+		"x-overflow":               "reject-publish",
+		"x-delivery-limit":         int64(5),
+		"x-single-active-consumer": true,
+		"x-max-priority":           int64(10),
+	}})
+	this.So(this.declareExchangeNames, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenDeadLetterExchangeSpecified_DeclareDeadLetterExchangeAndQueue() {
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		Topics:            []string{"topic"},
+		QueueArguments: messaging.QueueArguments{
+			DeadLetterExchange:   "dead-letters",
+			DeadLetterRoutingKey: "key",
+		},
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareExchangeNames, should.Equal, []string{"dead-letters", "topic"})
+	this.So(this.declareQueueNames, should.Equal, []string{"dead-letters", "queue"})
+	this.So(this.declareQueueArguments, should.Equal, []map[string]any{nil, {
+		"x-dead-letter-exchange":    "dead-letters",
+		"x-dead-letter-routing-key": "key",
+	}})
+	this.So(this.bindQueueQueueNames, should.Equal, []string{"dead-letters", "queue"})
+	this.So(this.bindQueueExchangeNames, should.Equal, []string{"dead-letters", "topic"})
+}
+func (this *ReaderFixture) TestWhenDeadLetterQueueSpecified_DeclareAndBindQueueOfThatName() {
+	this.initializeReader(Options.ExchangeType("dead-letters", ExchangeTopic))
+
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		QueueArguments: messaging.QueueArguments{
+			DeadLetterExchange: "dead-letters",
+			DeadLetterQueue:    "poison",
+		},
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareExchangeKinds, should.Equal, []string{ExchangeTopic})
+	this.So(this.declareQueueNames, should.Equal, []string{"poison", "queue"})
+	this.So(this.bindQueueQueueNames, should.Equal, []string{"poison"})
+	this.So(this.bindQueueKeys, should.Equal, []string{"#"})
+}
+func (this *ReaderFixture) TestWhenDeclaringDeadLetterExchangeFails_CloseChannelAndReturnError() {
+	this.declareExchangeError = errors.New("")
+
+	stream, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		QueueArguments:    messaging.QueueArguments{DeadLetterExchange: "dead-letters"},
+	})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.declareExchangeError)
+	this.So(this.declareQueueNames, should.BeEmpty)
+	this.So(this.callsToClose, should.Equal, 1)
+}
+func (this *ReaderFixture) TestWhenStreamQueue_DeclareStreamQueueAndConsumeFromNextOffset() {
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		StreamQueue:       true,
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareQueueArguments, should.Equal, []map[string]any{{"x-queue-type": "stream"}})
+	this.So(this.consumeArguments, should.Equal, map[string]any{"x-stream-offset": "next"})
+}
+func (this *ReaderFixture) TestWhenStreamQueueWithQueueArguments_ReturnErrorWithoutEstablishingTopology() {
+	for _, arguments := range []messaging.QueueArguments{
+		{DeadLetterExchange: "dead-letters"},
+		{MessageTTL: time.Minute},
+		{MaxLength: 1},
+		{MaxPriority: 10},
+	} {
+		stream, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+			EstablishTopology: true,
+			StreamName:        "queue",
+			StreamQueue:       true,
+			QueueArguments:    arguments,
+		})
+
+		this.So(stream, should.BeNil)
+		this.So(err, should.Equal, ErrStreamQueueArguments)
+	}
+
+	this.So(this.declareQueueNames, should.BeEmpty)
+	this.So(this.consumeQueue, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenStreamQueueWithSequence_ConsumeFromSequenceOffset() {
+	_, err := this.reader.Stream(context.Background(), messaging.StreamConfig{
+		StreamName:   "queue",
+		StreamQueue:  true,
+		Sequence:     42,
+		StreamOffset: "first", // ignored
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declareQueueNames, should.BeEmpty)
+	this.So(this.consumeArguments, should.Equal, map[string]any{"x-stream-offset": int64(42)})
+}
+func (this *ReaderFixture) TestWhenStreamQueueWithExplicitZeroSequence_ConsumeFromFirstOffset() {
+	_, _ = this.reader.Stream(context.Background(), messaging.StreamConfig{
+		StreamQueue:  true,
+		Sequence:     0,
+		StreamOffset: StreamOffsetSequence,
+	})
+
+	this.So(this.consumeArguments, should.Equal, map[string]any{"x-stream-offset": int64(0)})
+}
+func (this *ReaderFixture) TestWhenStreamQueueWithNamedOffset_ConsumeFromNamedOffset() {
+	_, _ = this.reader.Stream(context.Background(), messaging.StreamConfig{StreamQueue: true, StreamOffset: "first"})
+
+	this.So(this.consumeArguments, should.Equal, map[string]any{"x-stream-offset": "first"})
+}
+func (this *ReaderFixture) TestWhenStreamQueueWithTimestampOffset_ConsumeFromTimestamp() {
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	_, _ = this.reader.Stream(context.Background(), messaging.StreamConfig{
+		StreamQueue:  true,
+		StreamOffset: timestamp.Format(time.RFC3339),
+	})
+
+	this.So(this.consumeArguments, should.Equal, map[string]any{"x-stream-offset": timestamp})
+}
+func (this *ReaderFixture) TestWhenNotStreamQueue_ConsumeWithoutArguments() {
+	_, _ = this.reader.Stream(context.Background(), messaging.StreamConfig{Sequence: 42, StreamOffset: "first"})
+
+	this.So(this.consumeArguments, should.BeNil)
+}
 
 func (this *ReaderFixture) TestWhenSettingBufferCapacityFails_CloseChannelAndReturnError() {
 	this.bufferCapacityError = errors.New("")
@@ -196,29 +480,48 @@ func (this *ReaderFixture) TestWhenClosing_ShutDownAllStreamsAndUnderlyingChanne
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func (this *ReaderFixture) DeclareQueue(name string, replicated bool) error {
+func (this *ReaderFixture) DeclareQueue(name string, replicated bool, arguments map[string]any) error {
 	this.declareQueueName = name
+	this.declareQueueNames = append(this.declareQueueNames, name)
+	this.declareQueueReplicated = append(this.declareQueueReplicated, replicated)
+	this.declareQueueArguments = append(this.declareQueueArguments, arguments)
 	return this.declareQueueError
 }
-func (this *ReaderFixture) DeclareExchange(name string) error {
+func (this *ReaderFixture) DeclareExchange(name, kind string, arguments map[string]any) error {
+	this.declareExchangeArgs = append(this.declareExchangeArgs, arguments)
 	this.declareExchangeNames = append(this.declareExchangeNames, name)
+	this.declareExchangeKinds = append(this.declareExchangeKinds, kind)
 	return this.declareExchangeError
 }
-func (this *ReaderFixture) BindQueue(queue, exchange string) error {
+func (this *ReaderFixture) BindQueue(queue, exchange, key string, arguments map[string]any) error {
 	this.bindQueueQueueNames = append(this.bindQueueQueueNames, queue)
 	this.bindQueueExchangeNames = append(this.bindQueueExchangeNames, exchange)
+	this.bindQueueKeys = append(this.bindQueueKeys, key)
+	this.bindQueueArguments = append(this.bindQueueArguments, arguments)
 	return this.bindQueueError
 }
+func (this *ReaderFixture) DeclareQueuePassive(name string) error {
+	this.passiveQueueNames = append(this.passiveQueueNames, name)
+	return this.passiveErrors[name]
+}
+func (this *ReaderFixture) DeclareExchangePassive(name, _ string) error {
+	this.passiveExchangeNames = append(this.passiveExchangeNames, name)
+	return this.passiveErrors[name]
+}
 func (this *ReaderFixture) BufferCapacity(value uint16) error {
 	this.bufferCapacityValue = value
 	return this.bufferCapacityError
 }
-func (this *ReaderFixture) Consume(consumerID, queue string) (<-chan amqp.Delivery, error) {
+func (this *ReaderFixture) Consume(consumerID, queue string, arguments map[string]any) (<-chan amqp.Delivery, error) {
+	this.consumeArguments = arguments
 	this.consumeConsumerID = consumerID
 	this.consumeQueue = queue
 	return this.consumeChannel, this.consumeError
 }
 func (this *ReaderFixture) Close() error { this.callsToClose++; return nil }
+func (this *ReaderFixture) NotifyClose(closes chan *amqp.Error) chan *amqp.Error {
+	return closes
+}
 
 func (this *ReaderFixture) Ack(deliveryTag uint64, multiple bool) error {
 	panic("nop")
@@ -239,3 +542,18 @@ func (this *ReaderFixture) TxCommit() error {
 func (this *ReaderFixture) TxRollback() error {
 	panic("nop")
 }
+func (this *ReaderFixture) Confirm(noWait bool) error {
+	panic("nop")
+}
+func (this *ReaderFixture) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	panic("nop")
+}
+func (this *ReaderFixture) GetNextPublishSeqNo() uint64 {
+	panic("nop")
+}
+func (this *ReaderFixture) PublishMandatory(_, _ string, _ amqp.Publishing) error    { panic("nop") }
+func (this *ReaderFixture) NotifyReturn(chan amqp.Return) chan amqp.Return           { panic("nop") }
+func (this *ReaderFixture) Nack(uint64, bool, bool) error                            { panic("nop") }
+func (this *ReaderFixture) DeleteQueue(string) error                                 { panic("nop") }
+func (this *ReaderFixture) DeleteExchange(string) error                              { panic("nop") }
+func (this *ReaderFixture) UnbindQueue(string, string, string, map[string]any) error { panic("nop") }
@@ -0,0 +1,112 @@
+package rabbitmq
+
+import (
+	"strconv"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/messaging/v3/rabbitmq/adapter"
+)
+
+// returnListener receives the mandatory dispatches returned by the broker as unroutable on a dedicated goroutine, which
+// provides each to the handler as soon as it is received (regardless of whether the writer is idle) and concludes when
+// the channel is closed. The connection sends returns synchronously, so receiving them promptly keeps a large number of
+// returns from blocking the connection (and thereby the reply to a transaction commit). The broker sends each return
+// before the reply to the transaction commit (or the publisher confirmation) of the returned dispatch, so the returns
+// of the dispatches written have all been received, or are buffered, by the time Commit completes.
+type returnListener struct {
+	returns  chan amqp.Return
+	handler  func(ReturnedDispatch)
+	retain   bool
+	requests chan chan []ReturnedDispatch
+	done     chan struct{}
+	returned []ReturnedDispatch // owned by the listening goroutine until done
+}
+
+func newReturnListener(inner adapter.Channel, retain bool, config configuration) *returnListener {
+	this := &returnListener{
+		returns:  inner.NotifyReturn(make(chan amqp.Return, maxBufferedReturns)),
+		handler:  config.ReturnHandler,
+		retain:   retain,
+		requests: make(chan chan []ReturnedDispatch),
+		done:     make(chan struct{}),
+	}
+	go this.listen()
+	return this
+}
+func (this *returnListener) listen() {
+	defer close(this.done)
+
+	for {
+		select {
+		case item, open := <-this.returns:
+			if !open {
+				return
+			}
+			this.receive(item)
+		case reply := <-this.requests:
+			open := this.drain() // returns sent before the request are buffered, if not already received
+			reply <- this.returned
+			this.returned = nil
+			if !open {
+				return
+			}
+		}
+	}
+}
+func (this *returnListener) drain() bool {
+	for {
+		select {
+		case item, open := <-this.returns:
+			if !open {
+				return false
+			}
+			this.receive(item)
+		default:
+			return true
+		}
+	}
+}
+func (this *returnListener) receive(item amqp.Return) {
+	returned := toReturnedDispatch(item)
+	this.handler(returned)
+	if this.retain {
+		this.returned = append(this.returned, returned)
+	}
+}
+
+// Collect returns an *UnroutableError describing the dispatches returned since the previous Collect or Discard.
+func (this *returnListener) Collect() error {
+	if returned := this.take(); len(returned) > 0 {
+		return &UnroutableError{Returned: returned}
+	}
+
+	return nil
+}
+func (this *returnListener) Discard() {
+	_ = this.take()
+}
+func (this *returnListener) take() []ReturnedDispatch {
+	reply := make(chan []ReturnedDispatch, 1)
+	select {
+	case this.requests <- reply:
+		return <-reply
+	case <-this.done:
+		returned := this.returned
+		this.returned = nil
+		return returned
+	}
+}
+
+func toReturnedDispatch(item amqp.Return) ReturnedDispatch {
+	messageID, _ := strconv.ParseUint(item.MessageId, 10, 64)
+	return ReturnedDispatch{
+		Topic:       item.Exchange,
+		RoutingKey:  item.RoutingKey,
+		MessageID:   messageID,
+		MessageType: item.Type,
+		ReplyCode:   item.ReplyCode,
+		ReplyText:   item.ReplyText,
+	}
+}
+
+const maxBufferedReturns = 1024
@@ -13,6 +13,7 @@ import (
 
 type defaultStream struct {
 	channel    adapter.Channel
+	closes     *closeListener
 	deliveries <-chan amqp.Delivery
 	streamID   string
 	streamName string
@@ -25,6 +26,7 @@ type defaultStream struct {
 func newStream(channel adapter.Channel, deliveries <-chan amqp.Delivery, id, name string, exclusive bool, config configuration) messaging.Stream {
 	return &defaultStream{
 		channel:    channel,
+		closes:     newCloseListener(channel),
 		deliveries: deliveries,
 		streamID:   id,
 		streamName: name,
@@ -35,16 +37,22 @@ func newStream(channel adapter.Channel, deliveries <-chan amqp.Delivery, id, nam
 }
 
 func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	if err := this.closes.Err(); err != nil {
+		return err
+	}
+
 	select {
 	case source, open := <-this.deliveries:
 		return this.processDelivery(source, target, open)
+	case notification, open := <-this.closes.Closed():
+		return coalesceError(this.closes.Receive(notification, open), io.EOF)
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 func (this *defaultStream) processDelivery(source amqp.Delivery, target *messaging.Delivery, deliveryChannelOpen bool) error {
 	if !deliveryChannelOpen {
-		return io.EOF
+		return coalesceError(this.closes.Err(), io.EOF) // closed by the broker or the consumer was canceled
 	}
 
 	target.Upstream = source
@@ -55,6 +63,12 @@ func (this *defaultStream) processDelivery(source amqp.Delivery, target *messagi
 	target.Timestamp = source.Timestamp
 	target.Durable = source.DeliveryMode == amqp.Persistent
 	target.Topic = this.streamName
+	target.Sequence = parseStreamOffset(source.Headers)
+	target.Redelivered = source.Redelivered
+	target.Attempt = parseAttempt(source.Redelivered, source.Headers)
+	target.Exchange = source.Exchange
+	target.RoutingKey = source.RoutingKey
+	target.Priority = source.Priority
 	target.MessageType = source.Type
 	target.ContentType = source.ContentType
 	target.ContentEncoding = source.ContentEncoding
@@ -64,6 +78,29 @@ func (this *defaultStream) processDelivery(source amqp.Delivery, target *messagi
 	this.monitor.DeliveryReceived()
 	return nil
 }
+func parseStreamOffset(headers amqp.Table) uint64 {
+	offset, _ := headers["x-stream-offset"].(int64) // set by the broker on each delivery from a stream queue
+	return uint64(offset)
+}
+func parseAttempt(redelivered bool, headers amqp.Table) uint64 {
+	// set by the broker on each redelivery from a quorum queue, the number of previous (unsuccessful) deliveries
+	switch count := headers["x-delivery-count"].(type) {
+	case int64:
+		return uint64(count) + 1
+	case int32:
+		return uint64(count) + 1
+	case int16:
+		return uint64(count) + 1
+	case int8:
+		return uint64(count) + 1
+	}
+
+	if redelivered {
+		return 2 // at least
+	}
+
+	return 1
+}
 func parseUint64(value string) uint64 {
 	parsed, _ := strconv.ParseUint(value, 10, 64)
 	return parsed
@@ -81,6 +118,11 @@ func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messag
 		deliveries = deliveries[length-1:] // only ack the last one
 	}
 
+	if err := this.closes.Err(); err != nil {
+		this.monitor.DeliveryAcknowledged(uint16(length), err)
+		return err // delivery tags are scoped to the channel, so deliveries can't be acknowledged once it's closed
+	}
+
 	for _, delivery := range deliveries {
 		if err := this.channel.Ack(delivery.DeliveryID, this.batchAck); err != nil {
 			this.logger.Printf("[WARN] Unable to acknowledge delivery against underlying channel [%s].", err)
@@ -93,6 +135,42 @@ func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messag
 	return nil
 }
 
+// Reject negatively acknowledges the deliveries such that the broker either requeues them for redelivery or discards
+// them, in which case they are dead-lettered if the queue is so configured.
+func (this *defaultStream) Reject(ctx context.Context, requeue bool, deliveries ...messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	length := len(deliveries)
+	if length > 1 && this.batchAck {
+		deliveries = deliveries[length-1:] // only reject the last one
+	}
+
+	if err := this.closes.Err(); err != nil {
+		this.rejected(length, requeue, err)
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if err := this.channel.Nack(delivery.DeliveryID, this.batchAck, requeue); err != nil {
+			this.logger.Printf("[WARN] Unable to reject delivery against underlying channel [%s].", err)
+			this.rejected(length, requeue, err)
+			return err
+		}
+	}
+
+	this.rejected(length, requeue, nil)
+	return nil
+}
+func (this *defaultStream) rejected(length int, requeue bool, err error) {
+	if extended, ok := this.monitor.(deliveryMonitor); ok {
+		extended.DeliveryRejected(uint16(length), requeue, err)
+	}
+}
+
 func (this *defaultStream) Close() (err error) {
 	this.closer.Do(func() {
 		err = this.channel.CancelConsumer(this.streamID)
@@ -3,6 +3,7 @@ package rabbitmq
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"testing"
 	"time"
@@ -26,11 +27,19 @@ type StreamFixture struct {
 	streamName      string
 	exclusiveStream bool
 	now             time.Time
+	closes          chan *amqp.Error
 
 	cancellations         []string
 	acknowledgedTags      []uint64
 	acknowledgedMultiples []bool
 	acknowledgeError      error
+
+	rejectedTags      []uint64
+	rejectedMultiples []bool
+	rejectedRequeues  []bool
+	rejectError       error
+
+	monitoredRejections []string
 }
 
 func (this *StreamFixture) Setup() {
@@ -42,7 +51,7 @@ func (this *StreamFixture) initializeStream() {
 	this.streamID = "streamID"
 	this.streamName = "streamName"
 	this.stream = newStream(this, this.deliveries, this.streamID, this.streamName, this.exclusiveStream,
-		configuration{Logger: nop{}, Monitor: nop{}})
+		configuration{Logger: nop{}, Monitor: this})
 }
 
 func (this *StreamFixture) TestWhenCloseInvokedMultipleTimes_OnlyCancelConsumerOnce() {
@@ -67,6 +76,9 @@ func (this *StreamFixture) TestWhenReading_ReadFromConsumerBufferChannel() {
 		AppId:           "5",
 		DeliveryTag:     6,
 		Redelivered:     false,
+		Exchange:        "exchange",
+		RoutingKey:      "routing-key",
+		Priority:        9,
 		Body:            []byte("payload"),
 		Headers: map[string]any{
 			"header10": "value10",
@@ -89,6 +101,10 @@ func (this *StreamFixture) TestWhenReading_ReadFromConsumerBufferChannel() {
 		Timestamp:       this.now,
 		Durable:         true,
 		Topic:           "streamName",
+		Attempt:         1,
+		Exchange:        "exchange",
+		RoutingKey:      "routing-key",
+		Priority:        9,
 		MessageType:     "message-type",
 		ContentType:     "content-type",
 		ContentEncoding: "content-encoding",
@@ -100,6 +116,33 @@ func (this *StreamFixture) TestWhenReading_ReadFromConsumerBufferChannel() {
 		},
 	})
 }
+func (this *StreamFixture) TestWhenReadingFromStreamQueue_SequenceIsStreamOffset() {
+	this.deliveries <- amqp.Delivery{Headers: amqp.Table{"x-stream-offset": int64(42)}}
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(context.Background(), &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery.Sequence, should.Equal, 42)
+}
+func (this *StreamFixture) TestWhenReadingRedelivery_AttemptIsAtLeastSecond() {
+	this.deliveries <- amqp.Delivery{Redelivered: true}
+
+	var delivery messaging.Delivery
+	_ = this.stream.Read(context.Background(), &delivery)
+
+	this.So(delivery.Redelivered, should.BeTrue)
+	this.So(delivery.Attempt, should.Equal, 2)
+}
+func (this *StreamFixture) TestWhenReadingRedeliveryFromQuorumQueue_AttemptFollowsDeliveryCount() {
+	this.deliveries <- amqp.Delivery{Redelivered: true, Headers: amqp.Table{"x-delivery-count": int64(4)}}
+
+	var delivery messaging.Delivery
+	_ = this.stream.Read(context.Background(), &delivery)
+
+	this.So(delivery.Redelivered, should.BeTrue)
+	this.So(delivery.Attempt, should.Equal, 5)
+}
 func (this *StreamFixture) TestWhenReadingFromAClosedBufferChannel_ReturnEOF() {
 	close(this.deliveries)
 
@@ -109,6 +152,40 @@ func (this *StreamFixture) TestWhenReadingFromAClosedBufferChannel_ReturnEOF() {
 	this.So(err, should.Equal, io.EOF)
 	this.So(delivery, should.Equal, messaging.Delivery{})
 }
+func (this *StreamFixture) TestWhenChannelClosedByBroker_ReturnClosedError() {
+	this.closes <- &amqp.Error{Code: 404, Reason: "NOT_FOUND"}
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(context.Background(), &delivery)
+
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 404, ReplyText: "NOT_FOUND"})
+	this.So(errors.Is(err, amqp.ErrClosed), should.BeTrue)
+	this.So(this.stream.Read(context.Background(), &delivery), should.Equal, err)
+}
+func (this *StreamFixture) TestWhenBufferChannelClosedAfterChannelClosedByBroker_ReturnClosedError() {
+	this.closes <- &amqp.Error{Code: 320, Reason: "CONNECTION_FORCED"}
+	close(this.deliveries)
+
+	err := this.stream.(*defaultStream).processDelivery(amqp.Delivery{}, &messaging.Delivery{}, false)
+
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 320, ReplyText: "CONNECTION_FORCED"})
+}
+func (this *StreamFixture) TestWhenChannelClosedGracefully_ReturnEOF() {
+	close(this.closes)
+	close(this.deliveries)
+
+	err := this.stream.Read(context.Background(), &messaging.Delivery{})
+
+	this.So(err, should.Equal, io.EOF)
+}
+func (this *StreamFixture) TestWhenAcknowledgingAfterChannelClosedByBroker_ReturnClosedError() {
+	this.closes <- &amqp.Error{Code: 320, Reason: "CONNECTION_FORCED"}
+
+	err := this.stream.Acknowledge(context.Background(), messaging.Delivery{DeliveryID: 1})
+
+	this.So(err, should.HaveSameTypeAs, &ClosedError{})
+	this.So(this.acknowledgedTags, should.BeEmpty)
+}
 func (this *StreamFixture) TestWhenContextIsCancelled_ReturnCancellationError() {
 	dead, shutdown := context.WithCancel(context.Background())
 	shutdown()
@@ -164,6 +241,72 @@ func (this *StreamFixture) TestWhenAcknowledgingFails_ReturnUnderlyingError() {
 	this.So(this.acknowledgedMultiples, should.Equal, []bool{false})
 }
 
+func (this *StreamFixture) TestWhenRejectingWithACancelledContext_ReturnError() {
+	dead, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := this.stream.(messaging.Rejecter).Reject(dead, true, messaging.Delivery{})
+
+	this.So(err, should.Equal, context.Canceled)
+	this.So(this.rejectedTags, should.BeEmpty)
+}
+func (this *StreamFixture) TestWhenRejectingManyDeliveriesOnExclusiveStream_OnlyNackLastOne() {
+	this.exclusiveStream = true
+	this.initializeStream()
+
+	err := this.stream.(messaging.Rejecter).Reject(context.Background(), true,
+		messaging.Delivery{DeliveryID: 1},
+		messaging.Delivery{DeliveryID: 2},
+	)
+
+	this.So(err, should.BeNil)
+	this.So(this.rejectedTags, should.Equal, []uint64{2})
+	this.So(this.rejectedMultiples, should.Equal, []bool{true})
+	this.So(this.rejectedRequeues, should.Equal, []bool{true})
+}
+func (this *StreamFixture) TestWhenRejectingManyDeliveriesOnSharedStream_NackEachDelivery() {
+	err := this.stream.(messaging.Rejecter).Reject(context.Background(), false,
+		messaging.Delivery{DeliveryID: 1},
+		messaging.Delivery{DeliveryID: 2},
+	)
+
+	this.So(err, should.BeNil)
+	this.So(this.rejectedTags, should.Equal, []uint64{1, 2})
+	this.So(this.rejectedMultiples, should.Equal, []bool{false, false})
+	this.So(this.rejectedRequeues, should.Equal, []bool{false, false})
+}
+func (this *StreamFixture) TestWhenRejectingFails_ReturnUnderlyingError() {
+	this.rejectError = errors.New("")
+
+	err := this.stream.(messaging.Rejecter).Reject(context.Background(), true,
+		messaging.Delivery{DeliveryID: 1},
+		messaging.Delivery{DeliveryID: 2},
+	)
+
+	this.So(err, should.Equal, this.rejectError)
+	this.So(this.rejectedTags, should.Equal, []uint64{1})
+}
+func (this *StreamFixture) TestWhenRejecting_InformMonitor() {
+	_ = this.stream.(messaging.Rejecter).Reject(context.Background(), true,
+		messaging.Delivery{DeliveryID: 1},
+		messaging.Delivery{DeliveryID: 2},
+	)
+	this.rejectError = errors.New("failure")
+	_ = this.stream.(messaging.Rejecter).Reject(context.Background(), false, messaging.Delivery{DeliveryID: 3})
+
+	this.So(this.monitoredRejections, should.Equal, []string{"2/true/<nil>", "1/false/failure"})
+}
+func (this *StreamFixture) TestWhenMonitorDoesNotMonitorRejections_RejectNonetheless() {
+	this.stream = newStream(this, this.deliveries, this.streamID, this.streamName, this.exclusiveStream,
+		configuration{Logger: nop{}, Monitor: struct{ monitor }{this}}) // only the methods of the monitor interface
+
+	err := this.stream.(messaging.Rejecter).Reject(context.Background(), true, messaging.Delivery{DeliveryID: 1})
+
+	this.So(err, should.BeNil)
+	this.So(this.rejectedTags, should.Equal, []uint64{1})
+	this.So(this.monitoredRejections, should.BeEmpty)
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (this *StreamFixture) CancelConsumer(consumerID string) error {
@@ -175,14 +318,55 @@ func (this *StreamFixture) Ack(deliveryTag uint64, multiple bool) error {
 	this.acknowledgedMultiples = append(this.acknowledgedMultiples, multiple)
 	return this.acknowledgeError
 }
+func (this *StreamFixture) Nack(deliveryTag uint64, multiple, requeue bool) error {
+	this.rejectedTags = append(this.rejectedTags, deliveryTag)
+	this.rejectedMultiples = append(this.rejectedMultiples, multiple)
+	this.rejectedRequeues = append(this.rejectedRequeues, requeue)
+	return this.rejectError
+}
+
+func (this *StreamFixture) DeclareQueue(name string, replicated bool, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *StreamFixture) DeclareExchange(name, kind string, _ map[string]any) error { panic("nop") }
+func (this *StreamFixture) BindQueue(queue, exchange, key string, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *StreamFixture) BufferCapacity(value uint16) error { panic("nop") }
+func (this *StreamFixture) Consume(_, _ string, _ map[string]any) (<-chan amqp.Delivery, error) {
+	panic("nop")
+}
+func (this *StreamFixture) Publish(_, _ string, _ amqp.Publishing) error { panic("nop") }
+func (this *StreamFixture) Tx() error                                    { panic("nop") }
+func (this *StreamFixture) TxCommit() error                              { panic("nop") }
+func (this *StreamFixture) TxRollback() error                            { panic("nop") }
+func (this *StreamFixture) Close() error                                 { panic("nop") }
+func (this *StreamFixture) NotifyClose(closes chan *amqp.Error) chan *amqp.Error {
+	this.closes = closes
+	return closes
+}
+func (this *StreamFixture) Confirm(noWait bool) error { panic("nop") }
+func (this *StreamFixture) NotifyPublish(chan amqp.Confirmation) chan amqp.Confirmation {
+	panic("nop")
+}
+func (this *StreamFixture) GetNextPublishSeqNo() uint64                              { panic("nop") }
+func (this *StreamFixture) PublishMandatory(_, _ string, _ amqp.Publishing) error    { panic("nop") }
+func (this *StreamFixture) NotifyReturn(chan amqp.Return) chan amqp.Return           { panic("nop") }
+func (this *StreamFixture) DeclareQueuePassive(string) error                         { panic("nop") }
+func (this *StreamFixture) DeclareExchangePassive(string, string) error              { panic("nop") }
+func (this *StreamFixture) DeleteQueue(string) error                                 { panic("nop") }
+func (this *StreamFixture) DeleteExchange(string) error                              { panic("nop") }
+func (this *StreamFixture) UnbindQueue(string, string, string, map[string]any) error { panic("nop") }
 
-func (this *StreamFixture) DeclareQueue(name string, replicated bool) error   { panic("nop") }
-func (this *StreamFixture) DeclareExchange(name string) error                 { panic("nop") }
-func (this *StreamFixture) BindQueue(queue, exchange string) error            { panic("nop") }
-func (this *StreamFixture) BufferCapacity(value uint16) error                 { panic("nop") }
-func (this *StreamFixture) Consume(_, _ string) (<-chan amqp.Delivery, error) { panic("nop") }
-func (this *StreamFixture) Publish(_, _ string, _ amqp.Publishing) error      { panic("nop") }
-func (this *StreamFixture) Tx() error                                         { panic("nop") }
-func (this *StreamFixture) TxCommit() error                                   { panic("nop") }
-func (this *StreamFixture) TxRollback() error                                 { panic("nop") }
-func (this *StreamFixture) Close() error                                      { panic("nop") }
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *StreamFixture) DeliveryRejected(count uint16, requeue bool, err error) {
+	this.monitoredRejections = append(this.monitoredRejections, fmt.Sprintf("%d/%t/%v", count, requeue, err))
+}
+func (this *StreamFixture) ConnectionOpened(error)             {}
+func (this *StreamFixture) ConnectionClosed()                  {}
+func (this *StreamFixture) DispatchPublished()                 {}
+func (this *StreamFixture) DeliveryReceived()                  {}
+func (this *StreamFixture) DeliveryAcknowledged(uint16, error) {}
+func (this *StreamFixture) TransactionCommitted(error)         {}
+func (this *StreamFixture) TransactionRolledBack(error)        {}
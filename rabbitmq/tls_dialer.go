@@ -19,12 +19,12 @@ type (
 
 type tlsDialer struct {
 	netDialer
-	endpoint brokerEndpoint
-	client   tlsClientFunc
+	endpoints []brokerEndpoint
+	client    tlsClientFunc
 }
 
 func newTLSDialer(dialer netDialer, config configuration) netDialer {
-	return tlsDialer{netDialer: dialer, endpoint: config.Endpoint, client: config.TLSClient}
+	return tlsDialer{netDialer: dialer, endpoints: config.Endpoints, client: config.TLSClient}
 }
 
 func (this tlsDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
@@ -33,15 +33,18 @@ func (this tlsDialer) DialContext(ctx context.Context, network, address string)
 		return nil, err
 	}
 
-	if this.endpoint.TLSConfig == nil || this.endpoint.Address.Scheme != "amqps" {
+	endpoint := this.endpoint(address)
+	if endpoint.TLSConfig == nil || endpoint.Address.Scheme != "amqps" {
 		return conn, nil
 	}
 
-	if len(this.endpoint.TLSConfig.ServerName) == 0 {
-		this.endpoint.TLSConfig.ServerName, _, _ = net.SplitHostPort(this.endpoint.Address.Host)
+	config := endpoint.TLSConfig
+	if len(config.ServerName) == 0 {
+		config = config.Clone() // the configuration is shared by the endpoint of each node
+		config.ServerName, _, _ = net.SplitHostPort(endpoint.Address.Host)
 	}
 
-	tlsConnection := this.client(conn, this.endpoint.TLSConfig)
+	tlsConnection := this.client(conn, config)
 	if err = tlsConnection.Handshake(); err != nil {
 		_ = conn.Close()
 		return nil, err
@@ -49,3 +52,12 @@ func (this tlsDialer) DialContext(ctx context.Context, network, address string)
 
 	return tlsConnection, nil
 }
+func (this tlsDialer) endpoint(address string) brokerEndpoint {
+	for _, endpoint := range this.endpoints {
+		if endpoint.Address.Host == address {
+			return endpoint
+		}
+	}
+
+	return this.endpoints[0]
+}
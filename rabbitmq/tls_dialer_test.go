@@ -93,10 +93,29 @@ func (this *DialerFixture) TestWhenAddressSchemaDoesNotSpecifyAMQPS_DoNotEstabli
 func (this *DialerFixture) TestWhenNoServerNameSpecified_UseServerNameFromBrokerAddress() {
 	_, _ = this.dialer.DialContext(this.backgroundContext, "network", "address")
 
-	this.So(this.tlsConfig.ServerName, should.Equal, "localhost")
+	this.So(this.dialedTLSConfig.ServerName, should.Equal, "localhost")
+	this.So(this.tlsConfig.ServerName, should.BeEmpty)
+}
+func (this *DialerFixture) TestWhenMultipleBrokersSpecified_UseSchemeAndServerNameOfDialedBroker() {
+	config := configuration{}
+	Options.apply(
+		Options.Addresses("amqp://node-1:5672/", "amqps://node-2:5671/"),
+		Options.TLSConfig(this.tlsConfig),
+		Options.TLSClient(this.tlsClient),
+	)(&config)
+	this.dialer = newTLSDialer(this, config)
+
+	_, _ = this.dialer.DialContext(this.backgroundContext, "tcp", "node-1:5672")
+	this.So(this.callsToHandshake, should.Equal, 0)
+
+	_, _ = this.dialer.DialContext(this.backgroundContext, "tcp", "node-2:5671")
+	this.So(this.callsToHandshake, should.Equal, 1)
+	this.So(this.dialedTLSConfig.ServerName, should.Equal, "node-2")
 }
 
 func (this *DialerFixture) TestWhenEstablishingTLSConnection_ReturnTLSConnection() {
+	this.tlsConfig.ServerName = "server-name"
+
 	conn, err := this.dialer.DialContext(this.backgroundContext, "network", "address")
 
 	this.So(conn, should.Equal, this)
@@ -0,0 +1,52 @@
+package topology
+
+import "github.com/smarty/messaging/v3/rabbitmq/adapter"
+
+// New returns a Manager which opens channels on the connection specified, e.g. one established using adapter.New().
+func New(connection adapter.Connection, options ...option) Manager {
+	var config configuration
+	Options.apply(options...)(&config)
+	return newManager(connection, config)
+}
+
+type configuration struct {
+	VerifyEquivalence bool
+	Logger            logger
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// VerifyEquivalence indicates that Verify, having found that an exchange or queue exists, also determines whether it
+// was declared with the expected properties and arguments by declaring it (non-passively) once again, which has no
+// effect on an equivalent exchange or queue and otherwise fails. Unlike the passive declarations with which Verify
+// otherwise consults the broker, this requires the user to have permission to configure the exchanges and queues.
+func (singleton) VerifyEquivalence(value bool) option {
+	return func(this *configuration) { this.VerifyEquivalence = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.Logger(defaultLogger),
+	}, options...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
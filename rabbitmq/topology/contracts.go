@@ -0,0 +1,108 @@
+// Package topology declares, verifies, and tears down a declarative description of the exchanges, queues, and bindings
+// of a RabbitMQ broker, e.g. from a deploy pipeline prior to rolling out the consumers which depend on that topology.
+//
+// A topology is described by Go values or loaded from JSON or YAML:
+//
+//	exchanges:
+//	  - name: orders
+//	    type: topic
+//	queues:
+//	  - name: order-projections
+//	    type: quorum
+//	    arguments:
+//	      x-dead-letter-exchange: orders-dead-letter
+//	bindings:
+//	  - queue: order-projections
+//	    exchange: orders
+//	    routing_key: "orders.*.created"
+package topology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Topology describes the exchanges, queues, and the bindings between them, which should exist on the broker.
+type Topology struct {
+	Exchanges []Exchange `json:"exchanges,omitempty" yaml:"exchanges,omitempty"`
+	Queues    []Queue    `json:"queues,omitempty" yaml:"queues,omitempty"`
+	Bindings  []Binding  `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+}
+
+// Exchange is a durable exchange of the type specified, which is one of "fanout" (the default), "topic", "direct", or
+// "headers".
+type Exchange struct {
+	Name      string         `json:"name" yaml:"name"`
+	Type      string         `json:"type,omitempty" yaml:"type,omitempty"`
+	Arguments map[string]any `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+// Queue is a durable queue of the type specified, which is one of QueueClassic (the default), QueueQuorum, or
+// QueueStream. The arguments are those with which the queue is declared, e.g. "x-dead-letter-exchange".
+type Queue struct {
+	Name      string         `json:"name" yaml:"name"`
+	Type      string         `json:"type,omitempty" yaml:"type,omitempty"`
+	Arguments map[string]any `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+// Binding binds the queue to the exchange with the routing key (or pattern) and arguments specified.
+type Binding struct {
+	Queue     string         `json:"queue" yaml:"queue"`
+	Exchange  string         `json:"exchange" yaml:"exchange"`
+	Key       string         `json:"routing_key,omitempty" yaml:"routing_key,omitempty"`
+	Arguments map[string]any `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+// The types of queue which may be declared.
+const (
+	QueueClassic = "classic"
+	QueueQuorum  = "quorum"
+	QueueStream  = "stream"
+)
+
+type Manager interface {
+	// Declare declares each of the exchanges, queues, and bindings of the topology, failing if any of them already
+	// exists with different properties or arguments.
+	Declare(ctx context.Context, topology Topology) error
+
+	// Verify reports each exchange and queue of the topology which is missing from the broker using only passive
+	// declarations, which neither modify the broker nor require permission to configure it. Whether each exchange and
+	// queue which exists also has the expected properties and arguments is reported only when opted into using
+	// Options.VerifyEquivalence, which requires permission to configure them. The bindings cannot be inspected using
+	// AMQP (only using the management API, which this package does not consult) and are therefore not verified beyond
+	// the existence of their exchange and queue. An error is returned only when the broker cannot be consulted.
+	Verify(ctx context.Context, topology Topology) ([]Drift, error)
+
+	// Teardown deletes each of the queues and exchanges of the topology, along with their bindings, and removes each
+	// of the bindings to or from any exchange or queue not itself part of the topology.
+	Teardown(ctx context.Context, topology Topology) error
+}
+
+// Drift describes an exchange or queue of the topology which is either missing from the broker or, when verifying
+// equivalence, was declared on the broker with different properties or arguments.
+type Drift struct {
+	Kind    string // "exchange" or "queue"
+	Name    string
+	Missing bool
+	Reason  string // the reason given by the broker
+}
+
+func (this Drift) String() string {
+	if this.Missing {
+		return fmt.Sprintf("%s [%s] does not exist", this.Kind, this.Name)
+	}
+
+	return fmt.Sprintf("%s [%s] differs: %s", this.Kind, this.Name, this.Reason)
+}
+
+var ErrUnknownFormat = errors.New("the topology file is neither JSON nor YAML")
+
+const (
+	kindExchange = "exchange"
+	kindQueue    = "queue"
+)
+
+type logger interface {
+	Printf(format string, args ...any)
+}
@@ -0,0 +1,27 @@
+package topology
+
+import (
+	"time"
+
+	"github.com/smarty/messaging/v3/rabbitmq"
+)
+
+// DelayTiers describes the delay tiers of each of the topics specified, as used by the rabbitmq writer to delay the
+// delivery of dispatches when configured with the same tiers using rabbitmq.Options.DelayTiers. Each tier is a fanout
+// exchange bound to a queue whose messages expire after the delay of the tier, at which point they are dead-lettered
+// to the exchange of the topic, which must also be declared, with their original routing key.
+func DelayTiers(tiers []time.Duration, topics ...string) (topology Topology) {
+	for _, topic := range topics {
+		for _, tier := range tiers {
+			name := rabbitmq.DelayTier(topic, tier)
+			topology.Exchanges = append(topology.Exchanges, Exchange{Name: name, Type: rabbitmq.ExchangeFanout})
+			topology.Queues = append(topology.Queues, Queue{Name: name, Type: QueueClassic, Arguments: map[string]any{
+				"x-message-ttl":          tier.Milliseconds(),
+				"x-dead-letter-exchange": topic,
+			}})
+			topology.Bindings = append(topology.Bindings, Binding{Queue: name, Exchange: name})
+		}
+	}
+
+	return topology
+}
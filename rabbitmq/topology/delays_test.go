@@ -0,0 +1,32 @@
+package topology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+)
+
+func TestDelayTiersFixture(t *testing.T) {
+	gunit.Run(new(DelayTiersFixture), t)
+}
+
+type DelayTiersFixture struct {
+	*gunit.Fixture
+}
+
+func (this *DelayTiersFixture) TestWhenDescribingDelayTiers_DeadLetterEachTierToItsTopic() {
+	topology := DelayTiers([]time.Duration{time.Second * 10, time.Minute}, "orders", "invoices")
+
+	this.So(topology.Exchanges, should.HaveLength, 4)
+	this.So(topology.Exchanges[1], should.Equal, Exchange{Name: "orders.delay.1m0s", Type: "fanout"})
+	this.So(topology.Queues[2], should.Equal, Queue{Name: "invoices.delay.10s", Type: QueueClassic, Arguments: map[string]any{
+		"x-message-ttl":          int64(10_000),
+		"x-dead-letter-exchange": "invoices",
+	}})
+	this.So(topology.Bindings[3], should.Equal, Binding{Queue: "invoices.delay.1m0s", Exchange: "invoices.delay.1m0s"})
+}
+func (this *DelayTiersFixture) TestWhenNoTopics_EmptyTopology() {
+	this.So(DelayTiers([]time.Duration{time.Minute}), should.Equal, Topology{})
+}
@@ -0,0 +1,66 @@
+package topology
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the topology from the JSON (".json") or YAML (".yaml" or ".yml") file specified.
+func Load(path string) (Topology, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Topology{}, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseJSON(data)
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return Topology{}, fmt.Errorf("%w: [%s]", ErrUnknownFormat, path)
+	}
+}
+
+func ParseJSON(data []byte) (topology Topology, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // integer arguments (e.g. "x-max-length") must not become floating point numbers
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&topology)
+	return topology, err
+}
+func ParseYAML(data []byte) (topology Topology, err error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&topology)
+	return topology, err
+}
+
+func normalizeValue(value any) any {
+	switch typed := value.(type) {
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer
+		} else if float, err := typed.Float64(); err == nil {
+			return float
+		}
+		return typed.String()
+	case map[string]any:
+		return amqp.Table(normalize(typed))
+	case []any:
+		values := make([]any, 0, len(typed))
+		for _, item := range typed {
+			values = append(values, normalizeValue(item))
+		}
+		return values
+	default:
+		return value
+	}
+}
@@ -0,0 +1,116 @@
+package topology
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+)
+
+func TestLoadFixture(t *testing.T) {
+	gunit.Run(new(LoadFixture), t)
+}
+
+type LoadFixture struct {
+	*gunit.Fixture
+
+	directory string
+	expected  Topology
+}
+
+func (this *LoadFixture) Setup() {
+	this.directory, _ = os.MkdirTemp("", "topology")
+	this.expected = Topology{
+		Exchanges: []Exchange{{Name: "orders", Type: "topic"}},
+		Queues: []Queue{{Name: "projections", Type: QueueQuorum, Arguments: map[string]any{
+			"x-max-length":           int64(10),
+			"x-dead-letter-exchange": "orders-dead-letter",
+		}}},
+		Bindings: []Binding{{Queue: "projections", Exchange: "orders", Key: "orders.*"}},
+	}
+}
+
+func (this *LoadFixture) Teardown() {
+	_ = os.RemoveAll(this.directory)
+}
+
+func (this *LoadFixture) TestWhenParsingJSON_IntegerArgumentsRemainIntegers() {
+	topology, err := ParseJSON([]byte(`{
+		"exchanges": [{"name": "orders", "type": "topic"}],
+		"queues": [{"name": "projections", "type": "quorum", "arguments": {"x-max-length": 10, "x-dead-letter-exchange": "orders-dead-letter"}}],
+		"bindings": [{"queue": "projections", "exchange": "orders", "routing_key": "orders.*"}]
+	}`))
+
+	this.So(err, should.BeNil)
+	this.So(normalize(topology.Queues[0].Arguments), should.Equal, this.expected.Queues[0].Arguments)
+}
+func (this *LoadFixture) TestWhenParsingYAML_DecodeTopology() {
+	topology, err := ParseYAML([]byte(`
+exchanges:
+  - name: orders
+    type: topic
+queues:
+  - name: projections
+    type: quorum
+    arguments:
+      x-max-length: 10
+      x-dead-letter-exchange: orders-dead-letter
+bindings:
+  - queue: projections
+    exchange: orders
+    routing_key: "orders.*"
+`))
+
+	this.So(err, should.BeNil)
+	this.So(topology.Exchanges, should.Equal, this.expected.Exchanges)
+	this.So(topology.Bindings, should.Equal, this.expected.Bindings)
+	this.So(normalize(topology.Queues[0].Arguments), should.Equal, map[string]any{
+		"x-max-length":           10,
+		"x-dead-letter-exchange": "orders-dead-letter",
+	})
+}
+func (this *LoadFixture) TestWhenParsingUnknownFields_ReturnError() {
+	_, jsonErr := ParseJSON([]byte(`{"exchange": []}`))
+	_, yamlErr := ParseYAML([]byte(`exchange: []`))
+
+	this.So(jsonErr, should.NotBeNil)
+	this.So(yamlErr, should.NotBeNil)
+}
+func (this *LoadFixture) TestWhenNormalizingNestedArguments_ProduceAMQPTables() {
+	arguments := normalize(map[string]any{"nested": map[string]any{"value": "1.5"}, "list": []any{map[string]any{}}})
+
+	this.So(arguments["nested"], should.Equal, amqp.Table{"value": "1.5"})
+	this.So(amqp.Table(arguments).Validate(), should.BeNil)
+}
+
+func (this *LoadFixture) TestWhenLoadingFiles_ParseByExtension() {
+	jsonPath := filepath.Join(this.directory, "topology.json")
+	yamlPath := filepath.Join(this.directory, "topology.yml")
+	_ = os.WriteFile(jsonPath, []byte(`{"exchanges": [{"name": "orders", "type": "topic"}]}`), 0o600)
+	_ = os.WriteFile(yamlPath, []byte("exchanges:\n  - name: orders\n    type: topic\n"), 0o600)
+
+	fromJSON, jsonErr := Load(jsonPath)
+	fromYAML, yamlErr := Load(yamlPath)
+
+	this.So(jsonErr, should.BeNil)
+	this.So(yamlErr, should.BeNil)
+	this.So(fromJSON.Exchanges, should.Equal, this.expected.Exchanges)
+	this.So(fromYAML.Exchanges, should.Equal, this.expected.Exchanges)
+}
+func (this *LoadFixture) TestWhenLoadingUnknownFormat_ReturnError() {
+	path := filepath.Join(this.directory, "topology.txt")
+	_ = os.WriteFile(path, []byte(""), 0o600)
+
+	_, err := Load(path)
+
+	this.So(errors.Is(err, ErrUnknownFormat), should.BeTrue)
+}
+func (this *LoadFixture) TestWhenLoadingMissingFile_ReturnError() {
+	_, err := Load(filepath.Join(this.directory, "missing.json"))
+
+	this.So(errors.Is(err, os.ErrNotExist), should.BeTrue)
+}
@@ -0,0 +1,277 @@
+package topology
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/messaging/v3/rabbitmq"
+	"github.com/smarty/messaging/v3/rabbitmq/adapter"
+)
+
+type defaultManager struct {
+	connection  adapter.Connection
+	equivalence bool
+	logger      logger
+}
+
+func newManager(connection adapter.Connection, config configuration) Manager {
+	return &defaultManager{connection: connection, equivalence: config.VerifyEquivalence, logger: config.Logger}
+}
+
+func (this *defaultManager) Declare(ctx context.Context, topology Topology) error {
+	session := newSession(this.connection)
+	defer session.Close()
+
+	for _, exchange := range topology.Exchanges {
+		if err := session.Attempt(ctx, func(channel adapter.Channel) error {
+			return channel.DeclareExchange(exchange.Name, exchangeType(exchange), normalize(exchange.Arguments))
+		}); err != nil {
+			this.logger.Printf("[WARN] Unable to declare exchange [%s] [%s].", exchange.Name, err)
+			return err
+		}
+	}
+
+	for _, queue := range topology.Queues {
+		if err := session.Attempt(ctx, func(channel adapter.Channel) error {
+			return channel.DeclareQueue(queue.Name, queue.Type == QueueQuorum, queueArguments(queue))
+		}); err != nil {
+			this.logger.Printf("[WARN] Unable to declare queue [%s] [%s].", queue.Name, err)
+			return err
+		}
+	}
+
+	for _, binding := range topology.Bindings {
+		if err := session.Attempt(ctx, func(channel adapter.Channel) error {
+			return channel.BindQueue(binding.Queue, binding.Exchange, binding.Key, normalize(binding.Arguments))
+		}); err != nil {
+			this.logger.Printf("[WARN] Unable to bind queue [%s] to exchange [%s] [%s].", binding.Queue, binding.Exchange, err)
+			return err
+		}
+	}
+
+	this.logger.Printf("[INFO] Declared topology of [%d] exchange(s), [%d] queue(s), and [%d] binding(s).",
+		len(topology.Exchanges), len(topology.Queues), len(topology.Bindings))
+	return nil
+}
+
+// Verify passively declares each exchange and queue, which fails with reply code 404 if it doesn't exist. Only when
+// configured to verify equivalence does it then declare the exchange or queue with its expected properties and
+// arguments; this has no effect on the existing exchange or queue when equivalent and otherwise fails with reply code
+// 406, indicating that it has drifted.
+func (this *defaultManager) Verify(ctx context.Context, topology Topology) (drifts []Drift, err error) {
+	session := newSession(this.connection)
+	defer session.Close()
+
+	var drift *Drift
+	exchanges, queues := make(map[string]struct{}), make(map[string]struct{})
+	for _, exchange := range topology.Exchanges {
+		exchanges[exchange.Name] = struct{}{}
+		if drift, err = this.verify(ctx, session, kindExchange, exchange.Name, func(channel adapter.Channel) error {
+			return channel.DeclareExchangePassive(exchange.Name, exchangeType(exchange))
+		}, func(channel adapter.Channel) error {
+			return channel.DeclareExchange(exchange.Name, exchangeType(exchange), normalize(exchange.Arguments))
+		}); err != nil {
+			return drifts, err
+		} else if drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+
+	for _, queue := range topology.Queues {
+		queues[queue.Name] = struct{}{}
+		if drift, err = this.verify(ctx, session, kindQueue, queue.Name, func(channel adapter.Channel) error {
+			return channel.DeclareQueuePassive(queue.Name)
+		}, func(channel adapter.Channel) error {
+			return channel.DeclareQueue(queue.Name, queue.Type == QueueQuorum, queueArguments(queue))
+		}); err != nil {
+			return drifts, err
+		} else if drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+
+	for _, binding := range topology.Bindings {
+		if _, contains := exchanges[binding.Exchange]; !contains {
+			exchanges[binding.Exchange] = struct{}{}
+			if drift, err = this.verify(ctx, session, kindExchange, binding.Exchange, func(channel adapter.Channel) error {
+				return channel.DeclareExchangePassive(binding.Exchange, "")
+			}, nil); err != nil {
+				return drifts, err
+			} else if drift != nil {
+				drifts = append(drifts, *drift)
+			}
+		}
+
+		if _, contains := queues[binding.Queue]; !contains {
+			queues[binding.Queue] = struct{}{}
+			if drift, err = this.verify(ctx, session, kindQueue, binding.Queue, func(channel adapter.Channel) error {
+				return channel.DeclareQueuePassive(binding.Queue)
+			}, nil); err != nil {
+				return drifts, err
+			} else if drift != nil {
+				drifts = append(drifts, *drift)
+			}
+		}
+	}
+
+	for _, item := range drifts {
+		this.logger.Printf("[WARN] Topology drift detected: %s.", item)
+	}
+
+	return drifts, nil
+}
+func (this *defaultManager) verify(ctx context.Context, session *session, kind, name string, exists, equivalent func(adapter.Channel) error) (*Drift, error) {
+	if err := session.Attempt(ctx, exists); isReplyCode(err, amqp.NotFound) {
+		return &Drift{Kind: kind, Name: name, Missing: true, Reason: replyText(err)}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if equivalent == nil || !this.equivalence {
+		return nil, nil
+	}
+
+	if err := session.Attempt(ctx, equivalent); isReplyCode(err, amqp.PreconditionFailed) {
+		return &Drift{Kind: kind, Name: name, Reason: replyText(err)}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (this *defaultManager) Teardown(ctx context.Context, topology Topology) error {
+	session := newSession(this.connection)
+	defer session.Close()
+
+	exchanges, queues := make(map[string]struct{}), make(map[string]struct{})
+	for _, exchange := range topology.Exchanges {
+		exchanges[exchange.Name] = struct{}{}
+	}
+	for _, queue := range topology.Queues {
+		queues[queue.Name] = struct{}{}
+	}
+
+	for _, binding := range topology.Bindings {
+		_, exchangeDeleted := exchanges[binding.Exchange]
+		_, queueDeleted := queues[binding.Queue]
+		if exchangeDeleted || queueDeleted {
+			continue // deleting an exchange or queue deletes its bindings
+		}
+
+		if err := session.Attempt(ctx, func(channel adapter.Channel) error {
+			return channel.UnbindQueue(binding.Queue, binding.Exchange, binding.Key, normalize(binding.Arguments))
+		}); err != nil && !isReplyCode(err, amqp.NotFound) {
+			this.logger.Printf("[WARN] Unable to unbind queue [%s] from exchange [%s] [%s].", binding.Queue, binding.Exchange, err)
+			return err
+		}
+	}
+
+	for _, queue := range topology.Queues {
+		if err := session.Attempt(ctx, func(channel adapter.Channel) error {
+			return channel.DeleteQueue(queue.Name)
+		}); err != nil && !isReplyCode(err, amqp.NotFound) {
+			this.logger.Printf("[WARN] Unable to delete queue [%s] [%s].", queue.Name, err)
+			return err
+		}
+	}
+
+	for _, exchange := range topology.Exchanges {
+		if err := session.Attempt(ctx, func(channel adapter.Channel) error {
+			return channel.DeleteExchange(exchange.Name)
+		}); err != nil && !isReplyCode(err, amqp.NotFound) {
+			this.logger.Printf("[WARN] Unable to delete exchange [%s] [%s].", exchange.Name, err)
+			return err
+		}
+	}
+
+	this.logger.Printf("[INFO] Removed topology of [%d] exchange(s), [%d] queue(s), and [%d] binding(s).",
+		len(topology.Exchanges), len(topology.Queues), len(topology.Bindings))
+	return nil
+}
+
+func exchangeType(exchange Exchange) string {
+	if len(exchange.Type) == 0 {
+		return rabbitmq.ExchangeFanout
+	}
+
+	return exchange.Type
+}
+func queueArguments(queue Queue) map[string]any {
+	arguments := normalize(queue.Arguments)
+	if arguments == nil {
+		arguments = make(map[string]any, 1)
+	}
+
+	arguments["x-queue-type"] = QueueClassic
+	if len(queue.Type) > 0 {
+		arguments["x-queue-type"] = queue.Type
+	}
+
+	return arguments
+}
+func isReplyCode(err error, code int) bool {
+	var brokerError *amqp.Error
+	return errors.As(err, &brokerError) && brokerError.Code == code
+}
+func replyText(err error) string {
+	var brokerError *amqp.Error
+	if errors.As(err, &brokerError) {
+		return brokerError.Reason
+	}
+
+	return err.Error()
+}
+
+// normalize converts the values decoded from JSON or YAML into values which may be encoded as AMQP field values.
+func normalize(arguments map[string]any) map[string]any {
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	normalized := maps.Clone(arguments)
+	for key, value := range normalized {
+		normalized[key] = normalizeValue(value)
+	}
+
+	return normalized
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// session attempts operations against a channel which is reopened after each failure, as the broker closes a channel
+// whenever an operation on it fails.
+type session struct {
+	connection adapter.Connection
+	channel    adapter.Channel
+}
+
+func newSession(connection adapter.Connection) *session {
+	return &session{connection: connection}
+}
+
+func (this *session) Attempt(ctx context.Context, operation func(adapter.Channel) error) (err error) {
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if this.channel == nil {
+		if this.channel, err = this.connection.Channel(); err != nil {
+			return err
+		}
+	}
+
+	if err = operation(this.channel); err != nil {
+		this.Close()
+	}
+
+	return err
+}
+func (this *session) Close() {
+	if this.channel != nil {
+		_ = this.channel.Close()
+		this.channel = nil
+	}
+}
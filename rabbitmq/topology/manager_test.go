@@ -0,0 +1,264 @@
+package topology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3/rabbitmq/adapter"
+)
+
+func TestManagerFixture(t *testing.T) {
+	gunit.Run(new(ManagerFixture), t)
+}
+
+type ManagerFixture struct {
+	*gunit.Fixture
+
+	manager  Manager
+	topology Topology
+
+	operations     []string
+	failures       map[string]error
+	arguments      map[string]map[string]any
+	channelError   error
+	channelsOpened int
+	channelsClosed int
+}
+
+func (this *ManagerFixture) Setup() {
+	this.failures = make(map[string]error)
+	this.arguments = make(map[string]map[string]any)
+	this.manager = New(this)
+	this.topology = Topology{
+		Exchanges: []Exchange{{Name: "orders", Type: "topic"}, {Name: "audit"}},
+		Queues: []Queue{
+			{Name: "projections", Type: QueueQuorum, Arguments: map[string]any{"x-max-length": 10}},
+			{Name: "history", Type: QueueStream},
+		},
+		Bindings: []Binding{
+			{Queue: "projections", Exchange: "orders", Key: "orders.*"},
+			{Queue: "history", Exchange: "audit"},
+		},
+	}
+}
+
+func (this *ManagerFixture) TestWhenDeclaring_DeclareExchangesThenQueuesThenBindings() {
+	err := this.manager.Declare(context.Background(), this.topology)
+
+	this.So(err, should.BeNil)
+	this.So(this.operations, should.Equal, []string{
+		"declare-exchange orders topic",
+		"declare-exchange audit fanout",
+		"declare-queue projections true",
+		"declare-queue history false",
+		"bind projections orders orders.*",
+		"bind history audit ",
+	})
+	this.So(this.arguments["projections"], should.Equal, map[string]any{"x-max-length": 10, "x-queue-type": QueueQuorum})
+	this.So(this.arguments["history"], should.Equal, map[string]any{"x-queue-type": QueueStream})
+	this.So(this.channelsOpened, should.Equal, 1)
+	this.So(this.channelsClosed, should.Equal, 1)
+}
+func (this *ManagerFixture) TestWhenDeclarationFails_ReturnErrorWithoutDeclaringRemainder() {
+	this.failures["declare-queue projections true"] = errors.New("declare failure")
+
+	err := this.manager.Declare(context.Background(), this.topology)
+
+	this.So(err, should.Equal, this.failures["declare-queue projections true"])
+	this.So(this.operations, should.HaveLength, 3)
+	this.So(this.channelsClosed, should.Equal, this.channelsOpened)
+}
+func (this *ManagerFixture) TestWhenOpeningChannelFails_ReturnError() {
+	this.channelError = errors.New("channel failure")
+
+	err := this.manager.Declare(context.Background(), this.topology)
+
+	this.So(err, should.Equal, this.channelError)
+	this.So(this.operations, should.BeEmpty)
+}
+func (this *ManagerFixture) TestWhenContextCancelled_StopDeclaring() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := this.manager.Declare(ctx, this.topology)
+
+	this.So(err, should.Equal, context.Canceled)
+	this.So(this.operations, should.BeEmpty)
+}
+
+func (this *ManagerFixture) TestWhenVerifying_OnlyDeclarePassively() {
+	this.failures["declare-queue projections true"] = &amqp.Error{Code: amqp.PreconditionFailed}
+
+	drifts, err := this.manager.Verify(context.Background(), this.topology)
+
+	this.So(err, should.BeNil)
+	this.So(drifts, should.BeEmpty)
+	this.So(this.operations, should.Equal, []string{
+		"declare-exchange-passive orders topic",
+		"declare-exchange-passive audit fanout",
+		"declare-queue-passive projections",
+		"declare-queue-passive history",
+	})
+}
+func (this *ManagerFixture) TestWhenVerifyingEquivalenceOfMatchingTopology_NoDrift() {
+	this.manager = New(this, Options.VerifyEquivalence(true))
+
+	drifts, err := this.manager.Verify(context.Background(), this.topology)
+
+	this.So(err, should.BeNil)
+	this.So(drifts, should.BeEmpty)
+	this.So(this.operations, should.Equal, []string{
+		"declare-exchange-passive orders topic",
+		"declare-exchange orders topic",
+		"declare-exchange-passive audit fanout",
+		"declare-exchange audit fanout",
+		"declare-queue-passive projections",
+		"declare-queue projections true",
+		"declare-queue-passive history",
+		"declare-queue history false",
+	})
+}
+func (this *ManagerFixture) TestWhenVerifyingEquivalenceOfMissingAndDifferentObjects_ReportDriftAndReopenClosedChannels() {
+	this.manager = New(this, Options.VerifyEquivalence(true))
+	this.failures["declare-exchange-passive orders topic"] = &amqp.Error{Code: amqp.NotFound, Reason: "NOT_FOUND - no exchange 'orders'"}
+	this.failures["declare-queue projections true"] = &amqp.Error{Code: amqp.PreconditionFailed, Reason: "PRECONDITION_FAILED - inequivalent arg 'x-max-length'"}
+
+	drifts, err := this.manager.Verify(context.Background(), this.topology)
+
+	this.So(err, should.BeNil)
+	this.So(drifts, should.Equal, []Drift{
+		{Kind: "exchange", Name: "orders", Missing: true, Reason: "NOT_FOUND - no exchange 'orders'"},
+		{Kind: "queue", Name: "projections", Reason: "PRECONDITION_FAILED - inequivalent arg 'x-max-length'"},
+	})
+	this.So(drifts[0].String(), should.Equal, "exchange [orders] does not exist")
+	this.So(drifts[1].String(), should.ContainSubstring, "queue [projections] differs")
+	this.So(this.channelsOpened, should.Equal, 3)
+	this.So(this.channelsClosed, should.Equal, 3)
+}
+func (this *ManagerFixture) TestWhenVerifyingBindingsToExternalObjects_VerifyTheirExistence() {
+	this.topology = Topology{Bindings: []Binding{{Queue: "external-queue", Exchange: "external-exchange"}}}
+	this.failures["declare-queue-passive external-queue"] = &amqp.Error{Code: amqp.NotFound, Reason: "NOT_FOUND"}
+
+	drifts, err := this.manager.Verify(context.Background(), this.topology)
+
+	this.So(err, should.BeNil)
+	this.So(drifts, should.Equal, []Drift{{Kind: "queue", Name: "external-queue", Missing: true, Reason: "NOT_FOUND"}})
+	this.So(this.operations, should.Equal, []string{
+		"declare-exchange-passive external-exchange ",
+		"declare-queue-passive external-queue",
+	})
+}
+func (this *ManagerFixture) TestWhenVerifyingFailsForAnotherReason_ReturnError() {
+	this.failures["declare-exchange-passive orders topic"] = &amqp.Error{Code: amqp.AccessRefused, Reason: "ACCESS_REFUSED"}
+
+	drifts, err := this.manager.Verify(context.Background(), this.topology)
+
+	this.So(drifts, should.BeEmpty)
+	this.So(err, should.Equal, this.failures["declare-exchange-passive orders topic"])
+}
+
+func (this *ManagerFixture) TestWhenTearingDown_UnbindExternalBindingsThenDeleteQueuesThenExchanges() {
+	this.topology.Bindings = append(this.topology.Bindings, Binding{Queue: "external-queue", Exchange: "external-exchange", Key: "key"})
+
+	err := this.manager.Teardown(context.Background(), this.topology)
+
+	this.So(err, should.BeNil)
+	this.So(this.operations, should.Equal, []string{
+		"unbind external-queue external-exchange key",
+		"delete-queue projections",
+		"delete-queue history",
+		"delete-exchange orders",
+		"delete-exchange audit",
+	})
+}
+func (this *ManagerFixture) TestWhenTearingDownMissingObjects_Continue() {
+	this.failures["delete-queue projections"] = &amqp.Error{Code: amqp.NotFound}
+
+	err := this.manager.Teardown(context.Background(), this.topology)
+
+	this.So(err, should.BeNil)
+	this.So(this.operations, should.HaveLength, 4)
+	this.So(this.channelsOpened, should.Equal, 2)
+}
+func (this *ManagerFixture) TestWhenTearingDownFails_ReturnError() {
+	this.failures["delete-exchange orders"] = &amqp.Error{Code: amqp.AccessRefused}
+
+	err := this.manager.Teardown(context.Background(), this.topology)
+
+	this.So(err, should.Equal, this.failures["delete-exchange orders"])
+	this.So(this.operations, should.HaveLength, 3)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ManagerFixture) record(format string, args ...any) error {
+	operation := fmt.Sprintf(format, args...)
+	this.operations = append(this.operations, operation)
+	return this.failures[operation]
+}
+
+func (this *ManagerFixture) Channel() (adapter.Channel, error) {
+	if this.channelError != nil {
+		return nil, this.channelError
+	}
+
+	this.channelsOpened++
+	return this, nil
+}
+func (this *ManagerFixture) Close() error {
+	this.channelsClosed++
+	return nil
+}
+
+func (this *ManagerFixture) DeclareExchange(name, kind string, _ map[string]any) error {
+	return this.record("declare-exchange %s %s", name, kind)
+}
+func (this *ManagerFixture) DeclareQueue(name string, replicated bool, arguments map[string]any) error {
+	this.arguments[name] = arguments
+	return this.record("declare-queue %s %t", name, replicated)
+}
+func (this *ManagerFixture) BindQueue(queue, exchange, key string, _ map[string]any) error {
+	return this.record("bind %s %s %s", queue, exchange, key)
+}
+func (this *ManagerFixture) DeclareExchangePassive(name, kind string) error {
+	return this.record("declare-exchange-passive %s %s", name, kind)
+}
+func (this *ManagerFixture) DeclareQueuePassive(name string) error {
+	return this.record("declare-queue-passive %s", name)
+}
+func (this *ManagerFixture) UnbindQueue(queue, exchange, key string, _ map[string]any) error {
+	return this.record("unbind %s %s %s", queue, exchange, key)
+}
+func (this *ManagerFixture) DeleteQueue(name string) error {
+	return this.record("delete-queue %s", name)
+}
+func (this *ManagerFixture) DeleteExchange(name string) error {
+	return this.record("delete-exchange %s", name)
+}
+
+func (this *ManagerFixture) UpdateSecret(string, string) error                   { panic("nop") }
+func (this *ManagerFixture) NotifyClose(chan *amqp.Error) chan *amqp.Error       { panic("nop") }
+func (this *ManagerFixture) NotifyBlocked(chan amqp.Blocking) chan amqp.Blocking { panic("nop") }
+func (this *ManagerFixture) BufferCapacity(uint16) error                         { panic("nop") }
+func (this *ManagerFixture) Consume(string, string, map[string]any) (<-chan amqp.Delivery, error) {
+	panic("nop")
+}
+func (this *ManagerFixture) Ack(uint64, bool) error                                 { panic("nop") }
+func (this *ManagerFixture) Nack(uint64, bool, bool) error                          { panic("nop") }
+func (this *ManagerFixture) CancelConsumer(string) error                            { panic("nop") }
+func (this *ManagerFixture) Publish(string, string, amqp.Publishing) error          { panic("nop") }
+func (this *ManagerFixture) PublishMandatory(string, string, amqp.Publishing) error { panic("nop") }
+func (this *ManagerFixture) NotifyReturn(chan amqp.Return) chan amqp.Return         { panic("nop") }
+func (this *ManagerFixture) Tx() error                                              { panic("nop") }
+func (this *ManagerFixture) TxCommit() error                                        { panic("nop") }
+func (this *ManagerFixture) TxRollback() error                                      { panic("nop") }
+func (this *ManagerFixture) Confirm(bool) error                                     { panic("nop") }
+func (this *ManagerFixture) NotifyPublish(chan amqp.Confirmation) chan amqp.Confirmation {
+	panic("nop")
+}
+func (this *ManagerFixture) GetNextPublishSeqNo() uint64 { panic("nop") }
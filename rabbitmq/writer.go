@@ -2,6 +2,8 @@ package rabbitmq
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,16 +15,29 @@ import (
 
 type defaultWriter struct {
 	inner         adapter.Channel
+	closes        *closeListener
+	flow          *flowControl
+	returns       *returnListener
+	delays        delayRouter
 	topologyPanic bool
 	now           func() time.Time
 	logger        logger
 	monitor       monitor
 }
 
-func newWriter(inner adapter.Channel, config configuration) messaging.CommitWriter {
+func newWriter(inner adapter.Channel, flow *flowControl, transactional bool, config configuration) messaging.CommitWriter {
 	config.Logger.Printf("[INFO] Writer channel established on AMQP connection.")
+	var returns *returnListener
+	if config.Mandatory {
+		returns = newReturnListener(inner, transactional, config)
+	}
+
 	return defaultWriter{
 		inner:         inner,
+		closes:        newCloseListener(inner),
+		flow:          flow,
+		returns:       returns,
+		delays:        newDelayRouter(config),
 		topologyPanic: config.TopologyFailurePanic,
 		now:           config.Now,
 		logger:        config.Logger,
@@ -30,7 +45,11 @@ func newWriter(inner adapter.Channel, config configuration) messaging.CommitWrit
 	}
 }
 
-func (this defaultWriter) Write(_ context.Context, messages ...messaging.Dispatch) (count int, err error) {
+func (this defaultWriter) Write(ctx context.Context, messages ...messaging.Dispatch) (count int, err error) {
+	if err = this.awaitWritable(ctx); err != nil {
+		return 0, err
+	}
+
 	now := this.now().UTC()
 
 	for _, message := range messages {
@@ -38,11 +57,15 @@ func (this defaultWriter) Write(_ context.Context, messages ...messaging.Dispatc
 			return count, messaging.ErrEmptyDispatchTopic
 		}
 
-		count++
+		var exchange string
 		converted := toAMQPDispatch(message, now)
+		if exchange, err = this.delays.Route(message, &converted, now); err != nil {
+			return count, err
+		}
 
-		partition := formatPartition(message.Partition)
-		if err = this.inner.Publish(message.Topic, partition, converted); err != nil {
+		count++
+		if err = this.publish(exchange, routingKey(message), converted); err != nil {
+			err = coalesceError(this.closes.Err(), err)
 			this.logger.Printf("[WARN] Unable to write dispatch to underlying channel [%s].", err)
 			return count - 1, err // writes are async, only channel unavailability causes errors here
 		}
@@ -52,6 +75,64 @@ func (this defaultWriter) Write(_ context.Context, messages ...messaging.Dispatc
 
 	return count, nil
 }
+
+// awaitWritable fails if the broker has closed the channel and otherwise waits while the broker blocks the connection.
+func (this defaultWriter) awaitWritable(ctx context.Context) error {
+	if err := this.closes.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case <-this.flow.Unblocked():
+		return nil
+	default:
+	}
+
+	_, reason := this.flow.Blocked()
+	this.logger.Printf("[INFO] Awaiting the broker to unblock the AMQP connection [%s] before writing...", reason)
+
+	select {
+	case <-this.flow.Unblocked():
+		return nil
+	case notification, open := <-this.closes.Closed():
+		return coalesceError(this.closes.Receive(notification, open), amqp.ErrClosed)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+func coalesceError(values ...error) error {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+
+	return nil
+}
+func (this defaultWriter) publish(exchange, key string, envelope amqp.Publishing) error {
+	if this.returns == nil {
+		return this.inner.Publish(exchange, key, envelope)
+	}
+
+	return this.inner.PublishMandatory(exchange, key, envelope)
+}
+func routingKey(dispatch messaging.Dispatch) string {
+	if key, contains := dispatch.Headers[HeaderRoutingKey]; contains {
+		return formatHeader(key)
+	}
+
+	return formatPartition(dispatch.Partition)
+}
+func formatHeader(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
 func formatPartition(value uint64) string {
 	if value == 0 {
 		return ""
@@ -74,10 +155,20 @@ func toAMQPDispatch(dispatch messaging.Dispatch, now time.Time) amqp.Publishing
 		Timestamp:       dispatch.Timestamp,
 		Expiration:      computeExpiration(dispatch.Expiration),
 		DeliveryMode:    computePersistence(dispatch.Durable),
-		Headers:         dispatch.Headers,
+		Priority:        dispatch.Priority,
+		Headers:         publishedHeaders(dispatch.Headers),
 		Body:            dispatch.Payload,
 	}
 }
+func publishedHeaders(headers map[string]any) map[string]any {
+	if _, contains := headers[HeaderRoutingKey]; !contains {
+		return headers
+	}
+
+	published := maps.Clone(headers)
+	delete(published, HeaderRoutingKey)
+	return published
+}
 func computeExpiration(expiration time.Duration) string {
 	if expiration == 0 {
 		return ""
@@ -98,14 +189,28 @@ func computePersistence(durable bool) uint8 {
 func (this defaultWriter) Commit() error {
 	if err := this.inner.TxCommit(); err == nil {
 		this.monitor.TransactionCommitted(nil)
-		return nil
+		return this.collectReturns()
 	} else {
 		this.logger.Printf("[WARN] Unable to commit channel transaction [%s].", err)
 		this.monitor.TransactionCommitted(err)
 		return this.tryPanic(err)
 	}
 }
+func (this defaultWriter) collectReturns() error {
+	if this.returns == nil {
+		return nil
+	}
+
+	err := this.returns.Collect()
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to route dispatches committed [%s].", err)
+	}
+
+	return err
+}
 func (this defaultWriter) Rollback() error {
+	this.discardReturns()
+
 	if err := this.inner.TxRollback(); err == nil {
 		this.monitor.TransactionRolledBack(nil)
 		return nil
@@ -115,6 +220,11 @@ func (this defaultWriter) Rollback() error {
 		return this.tryPanic(err)
 	}
 }
+func (this defaultWriter) discardReturns() {
+	if this.returns != nil {
+		this.returns.Discard()
+	}
+}
 func (this defaultWriter) tryPanic(err error) error {
 	if !this.topologyPanic {
 		return err
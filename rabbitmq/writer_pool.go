@@ -0,0 +1,149 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/messaging/v3"
+)
+
+// writerPool multiplexes concurrent writes over at most the specified number of channels, each of which is written to
+// by one caller at a time. Channels are opened as needed; when all of them are in use, writes await a channel becoming
+// available. A channel which fails is closed and replaced by a new channel opened by a subsequent write. When confirming,
+// the channels are in confirm mode and each write is committed such that it completes once confirmed by the broker.
+type writerPool struct {
+	open     func() (messaging.CommitWriter, error)
+	confirm  bool
+	idle     chan messaging.CommitWriter // capacity of the maximum number of channels, so releasing never blocks
+	channels chan struct{}               // one token per open channel
+	closed   chan struct{}
+	mutex    sync.Mutex
+	logger   logger
+}
+
+func newWriterPool(open func() (messaging.CommitWriter, error), capacity uint16, config configuration) *writerPool {
+	return &writerPool{
+		open:     open,
+		confirm:  config.PublisherConfirms,
+		idle:     make(chan messaging.CommitWriter, capacity),
+		channels: make(chan struct{}, capacity),
+		closed:   make(chan struct{}),
+		logger:   config.Logger,
+	}
+}
+
+func (this *writerPool) Write(ctx context.Context, messages ...messaging.Dispatch) (int, error) {
+	writer, err := this.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := writer.Write(ctx, messages...)
+	if this.confirm {
+		err = this.commit(writer, err)
+	}
+
+	this.release(writer, healthy(ctx, err))
+	return count, err
+}
+func (this *writerPool) acquire(ctx context.Context) (messaging.CommitWriter, error) {
+	select {
+	case <-this.closed:
+		return nil, ErrWriterPoolClosed
+	case writer := <-this.idle: // prefer an open channel over opening another
+		return writer, nil
+	default:
+	}
+
+	select {
+	case <-this.closed:
+		return nil, ErrWriterPoolClosed
+	case writer := <-this.idle:
+		return writer, nil
+	case this.channels <- struct{}{}:
+		writer, err := this.open()
+		if err != nil {
+			<-this.channels
+			return nil, err
+		}
+		return writer, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (this *writerPool) commit(writer messaging.CommitWriter, err error) error {
+	if err != nil {
+		_ = writer.Rollback() // discards any outstanding confirmations of the messages written prior to the failure
+		return err
+	}
+
+	return writer.Commit()
+}
+
+// healthy indicates whether the channel of a writer remains usable after the write, which failed only if the broker
+// (rather than the dispatch or context) was responsible for the error. A closed channel is never usable, even if the
+// context was also cancelled.
+func healthy(ctx context.Context, err error) bool {
+	if errors.Is(err, amqp.ErrClosed) {
+		return false
+	}
+
+	var unroutable *UnroutableError
+	return err == nil || ctx.Err() != nil ||
+		errors.Is(err, messaging.ErrEmptyDispatchTopic) || errors.Is(err, ErrDelayNotSupported) ||
+		errors.Is(err, ErrPublishNotAcked) || errors.As(err, &unroutable)
+}
+
+// release returns the writer to the pool unless the pool has been closed or the writer's channel is to be recycled,
+// e.g. because the broker closed it, in which case it's closed to make room for another.
+func (this *writerPool) release(writer messaging.CommitWriter, healthy bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	select {
+	case <-this.closed:
+	default:
+		if healthy {
+			this.idle <- writer
+			return
+		}
+
+		this.logger.Printf("[INFO] Recycling pooled write channel after failure.")
+	}
+
+	_ = writer.Close()
+	<-this.channels
+}
+
+func (this *writerPool) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	select {
+	case <-this.closed:
+		return nil
+	default:
+		close(this.closed)
+	}
+
+	for {
+		select {
+		case writer := <-this.idle:
+			_ = writer.Close()
+			<-this.channels
+		default:
+			return nil // writers in use are closed when released
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// pooledWriter is the Writer provided to each caller, all of which share the same pool which is closed only when the
+// connection is closed.
+type pooledWriter struct{ *writerPool }
+
+func (pooledWriter) Close() error { return nil }
@@ -0,0 +1,251 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestWriterPoolFixture(t *testing.T) {
+	gunit.Run(new(WriterPoolFixture), t)
+}
+
+type WriterPoolFixture struct {
+	*gunit.Fixture
+
+	pool *writerPool
+
+	mutex     sync.Mutex
+	opened    []*fakePooledWriter
+	openError error
+	writeErr  error
+	commitErr error
+	writing   chan struct{} // when specified, receives as each write starts
+	resume    chan struct{} // when specified, writes await it before completing
+}
+
+func (this *WriterPoolFixture) Setup() {
+	this.pool = newWriterPool(this.open, 2, configuration{Logger: nop{}})
+}
+
+func (this *WriterPoolFixture) TestWhenWritingSequentially_ReuseTheSameChannel() {
+	for range 3 {
+		count, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+		this.So(count, should.Equal, 1)
+		this.So(err, should.BeNil)
+	}
+
+	this.So(this.opened, should.HaveLength, 1)
+	this.So(this.opened[0].writes, should.Equal, 3)
+}
+func (this *WriterPoolFixture) TestWhenWritingConcurrently_OpenNoMoreThanTheMaximumChannels() {
+	this.writing = make(chan struct{}, 4)
+	this.resume = make(chan struct{})
+	var waiter sync.WaitGroup
+	for range 4 {
+		waiter.Add(1)
+		go func() {
+			defer waiter.Done()
+			_, _ = this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+		}()
+	}
+
+	<-this.writing
+	<-this.writing
+	time.Sleep(time.Millisecond * 10) // the remaining writes await an available channel
+	this.So(this.openedCount(), should.Equal, 2)
+	this.So(len(this.writing), should.Equal, 0)
+
+	close(this.resume)
+	waiter.Wait()
+	this.So(this.openedCount(), should.Equal, 2)
+	this.So(this.opened[0].writes+this.opened[1].writes, should.Equal, 4)
+}
+func (this *WriterPoolFixture) TestWhenAllChannelsInUse_WriteAwaitsUntilContextCancelled() {
+	this.writing = make(chan struct{}, 4)
+	this.resume = make(chan struct{})
+	defer close(this.resume)
+	go func() { _, _ = this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"}) }()
+	go func() { _, _ = this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"}) }()
+	<-this.writing
+	<-this.writing
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	count, err := this.pool.Write(ctx, messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, context.DeadlineExceeded)
+}
+func (this *WriterPoolFixture) TestWhenWriteFails_CloseAndReplaceChannel() {
+	this.writeErr = errors.New("channel failure")
+	_, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+	this.writeErr = nil
+	_, _ = this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(err, should.Equal, errors.New("channel failure"))
+	this.So(this.opened, should.HaveLength, 2)
+	this.So(this.opened[0].closed, should.Equal, 1)
+	this.So(this.opened[1].closed, should.Equal, 0)
+}
+func (this *WriterPoolFixture) TestWhenDispatchInvalid_KeepChannel() {
+	this.writeErr = messaging.ErrEmptyDispatchTopic
+
+	_, _ = this.pool.Write(context.Background(), messaging.Dispatch{})
+	_, _ = this.pool.Write(context.Background(), messaging.Dispatch{})
+
+	this.So(this.opened, should.HaveLength, 1)
+	this.So(this.opened[0].closed, should.Equal, 0)
+}
+func (this *WriterPoolFixture) TestWhenChannelClosedAndContextCancelled_CloseAndReplaceChannel() {
+	this.writeErr = &ClosedError{ReplyCode: 404, ReplyText: "NOT_FOUND"}
+	this.writing = make(chan struct{}, 1)
+	this.resume = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	written := make(chan error, 1)
+	go func() {
+		_, err := this.pool.Write(ctx, messaging.Dispatch{Topic: "topic"})
+		written <- err
+	}()
+	<-this.writing
+	cancel()
+	close(this.resume)
+
+	this.So(errors.Is(<-written, amqp.ErrClosed), should.BeTrue)
+	this.So(this.opened[0].closed, should.Equal, 1)
+}
+func (this *WriterPoolFixture) TestWhenConfirming_CommitEachWrite() {
+	this.pool = newWriterPool(this.open, 2, configuration{Logger: nop{}, PublisherConfirms: true})
+
+	_, err1 := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+	_, err2 := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(err1, should.BeNil)
+	this.So(err2, should.BeNil)
+	this.So(this.opened, should.HaveLength, 1)
+	this.So(this.opened[0].commits, should.Equal, 2)
+	this.So(this.opened[0].rollbacks, should.Equal, 0)
+}
+func (this *WriterPoolFixture) TestWhenConfirmingAndBrokerRejects_ReturnErrorAndKeepChannel() {
+	this.pool = newWriterPool(this.open, 2, configuration{Logger: nop{}, PublisherConfirms: true})
+	this.commitErr = ErrPublishNotAcked
+
+	_, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(err, should.Equal, ErrPublishNotAcked)
+	this.So(this.opened[0].closed, should.Equal, 0)
+}
+func (this *WriterPoolFixture) TestWhenConfirmingAndWriteFails_RollbackRatherThanCommit() {
+	this.pool = newWriterPool(this.open, 2, configuration{Logger: nop{}, PublisherConfirms: true})
+	this.writeErr = errors.New("channel failure")
+
+	_, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(err, should.Equal, errors.New("channel failure"))
+	this.So(this.opened[0].commits, should.Equal, 0)
+	this.So(this.opened[0].rollbacks, should.Equal, 1)
+	this.So(this.opened[0].closed, should.Equal, 1)
+}
+func (this *WriterPoolFixture) TestWhenOpeningChannelFails_ReturnErrorAndAllowAnotherAttempt() {
+	this.openError = errors.New("open failure")
+	for range 3 {
+		_, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+		this.So(err, should.Equal, this.openError)
+	}
+	this.openError = nil
+
+	_, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(err, should.BeNil)
+	this.So(this.opened, should.HaveLength, 1)
+}
+func (this *WriterPoolFixture) TestWhenClosed_CloseIdleChannelsAndRejectWrites() {
+	_, _ = this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(this.pool.Close(), should.BeNil)
+	this.So(this.pool.Close(), should.BeNil)
+	count, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(this.opened[0].closed, should.Equal, 1)
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, ErrWriterPoolClosed)
+}
+func (this *WriterPoolFixture) TestWhenClosedWhileWriting_CloseChannelOnceWritten() {
+	this.writing = make(chan struct{}, 1)
+	this.resume = make(chan struct{})
+	written := make(chan error, 1)
+	go func() {
+		_, err := this.pool.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+		written <- err
+	}()
+	<-this.writing
+
+	_ = this.pool.Close()
+	this.So(this.opened[0].closed, should.Equal, 0)
+	close(this.resume)
+
+	this.So(<-written, should.BeNil)
+	this.So(this.opened[0].closed, should.Equal, 1)
+}
+func (this *WriterPoolFixture) TestWhenClosingPooledWriter_PoolRemainsOpen() {
+	writer := pooledWriter{writerPool: this.pool}
+
+	this.So(writer.Close(), should.BeNil)
+	_, err := writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(err, should.BeNil)
+}
+
+func (this *WriterPoolFixture) openedCount() int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return len(this.opened)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *WriterPoolFixture) open() (messaging.CommitWriter, error) {
+	if this.openError != nil {
+		return nil, this.openError
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	writer := &fakePooledWriter{fixture: this}
+	this.opened = append(this.opened, writer)
+	return writer, nil
+}
+
+type fakePooledWriter struct {
+	fixture   *WriterPoolFixture
+	writes    int
+	commits   int
+	rollbacks int
+	closed    int
+}
+
+func (this *fakePooledWriter) Write(_ context.Context, messages ...messaging.Dispatch) (int, error) {
+	if this.fixture.writing != nil {
+		this.fixture.writing <- struct{}{}
+	}
+	if this.fixture.resume != nil {
+		<-this.fixture.resume
+	}
+
+	this.writes++
+	if this.fixture.writeErr != nil {
+		return 0, this.fixture.writeErr
+	}
+
+	return len(messages), nil
+}
+func (this *fakePooledWriter) Close() error    { this.closed++; return nil }
+func (this *fakePooledWriter) Commit() error   { this.commits++; return this.fixture.commitErr }
+func (this *fakePooledWriter) Rollback() error { this.rollbacks++; return nil }
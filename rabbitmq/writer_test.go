@@ -22,6 +22,9 @@ type WriterFixture struct {
 	writer                 messaging.CommitWriter
 	now                    time.Time
 	panicOnTopologyFailure bool
+	mandatory              bool
+	transactional          bool
+	delayTiers             []time.Duration
 
 	closeError              error
 	commitError             error
@@ -32,9 +35,21 @@ type WriterFixture struct {
 	publishExchanges []string
 	publishKeys      []string
 	publishMessages  []amqp.Publishing
+	mandatoryCount   int
+
+	returns  chan amqp.Return
+	returned []ReturnedDispatch
+	handled  chan struct{}
+
+	flow           *flowControl
+	closes         chan *amqp.Error
+	closeOnPublish *amqp.Error
 }
 
 func (this *WriterFixture) Setup() {
+	this.transactional = true
+	this.flow = newFlowControl()
+	this.handled = make(chan struct{}, 16)
 	this.initializeWriter()
 }
 func (this *WriterFixture) initializeWriter() {
@@ -42,9 +57,18 @@ func (this *WriterFixture) initializeWriter() {
 	Options.apply(
 		Options.Now(func() time.Time { return this.now }),
 		Options.PanicOnTopologyError(this.panicOnTopologyFailure),
+		Options.Mandatory(this.mandatory),
+		Options.DelayTiers(this.delayTiers...),
+		Options.ReturnHandler(func(item ReturnedDispatch) {
+			this.returned = append(this.returned, item)
+			select {
+			case this.handled <- struct{}{}:
+			default:
+			}
+		}),
 	)(&config)
 
-	this.writer = newWriter(this, config)
+	this.writer = newWriter(this, this.flow, this.transactional, config)
 }
 
 func (this *WriterFixture) TestWhenCloseInvoked_UnderlyingChannelClosed() {
@@ -54,6 +78,56 @@ func (this *WriterFixture) TestWhenCloseInvoked_UnderlyingChannelClosed() {
 
 	this.So(err, should.Equal, this.closeError)
 }
+func (this *WriterFixture) TestWhenChannelClosedByBroker_FailWithoutPublishing() {
+	this.closes <- &amqp.Error{Code: 403, Reason: "ACCESS_REFUSED"}
+
+	count, err := this.writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 403, ReplyText: "ACCESS_REFUSED"})
+	this.So(this.publishMessages, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenPublishFailsBecauseChannelClosedByBroker_ReturnClosedError() {
+	this.publishError = amqp.ErrClosed
+	this.closeOnPublish = &amqp.Error{Code: 404, Reason: "NOT_FOUND"}
+
+	count, err := this.writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 404, ReplyText: "NOT_FOUND"})
+}
+func (this *WriterFixture) TestWhenConnectionBlocked_AwaitUnblockedBeforePublishing() {
+	this.flow.Block("low on memory")
+	go func() { time.Sleep(time.Millisecond * 5); this.flow.Unblock() }()
+
+	count, err := this.writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+}
+func (this *WriterFixture) TestWhenConnectionBlockedAndContextCancelled_ReturnContextError() {
+	this.flow.Block("low on memory")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	count, err := this.writer.Write(ctx, messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, context.DeadlineExceeded)
+	this.So(this.publishMessages, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenConnectionBlockedAndChannelClosedByBroker_ReturnClosedError() {
+	this.flow.Block("low on disk")
+	go func() {
+		time.Sleep(time.Millisecond * 5)
+		this.closes <- &amqp.Error{Code: 320, Reason: "CONNECTION_FORCED"}
+	}()
+
+	count, err := this.writer.Write(context.Background(), messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, &ClosedError{ReplyCode: 320, ReplyText: "CONNECTION_FORCED"})
+}
 func (this *WriterFixture) TestWhenUnderlyingRollbackFails_ReturnError() {
 	this.rollbackError = errors.New("")
 
@@ -117,6 +191,7 @@ func (this *WriterFixture) TestWhenWrite_PublishToUnderlyingChannel() {
 		Durable:         true,
 		Topic:           "topic",
 		Partition:       5,
+		Priority:        7,
 		MessageType:     "message-type",
 		ContentType:     "content-type",
 		ContentEncoding: "content-encoding",
@@ -138,7 +213,7 @@ func (this *WriterFixture) TestWhenWrite_PublishToUnderlyingChannel() {
 			ContentType:     "content-type",
 			ContentEncoding: "content-encoding",
 			DeliveryMode:    amqp.Persistent,
-			Priority:        0,
+			Priority:        7,
 			CorrelationId:   "3",
 			ReplyTo:         "",
 			Expiration:      "60",
@@ -209,9 +284,152 @@ func (this *WriterFixture) TestWhenWriterFailsMidwayThrough_ReturnNumberOfWrites
 	this.So(err, should.Equal, this.publishError)
 }
 
+func (this *WriterFixture) TestWhenWriteWithRoutingKeyHeader_PublishWithRoutingKeyAndWithoutHeader() {
+	headers := map[string]any{HeaderRoutingKey: "orders.us.created", "other": "value"}
+
+	count, err := this.writer.Write(context.Background(), messaging.Dispatch{
+		Topic:     "orders",
+		Partition: 5,
+		Headers:   headers,
+	})
+
+	this.So(err, should.BeNil)
+	this.So(count, should.Equal, 1)
+	this.So(this.publishKeys, should.Equal, []string{"orders.us.created"})
+	this.So(this.publishMessages[0].Headers, should.Equal, amqp.Table{"other": "value"})
+	this.So(headers, should.HaveLength, 2) // the dispatch itself is unmodified
+}
+func (this *WriterFixture) TestWhenWriteDelayedDispatch_PublishToDelayTierWithRoutingKey() {
+	this.delayTiers = []time.Duration{time.Hour, time.Minute}
+	this.initializeWriter()
+
+	count, err := this.writer.Write(context.Background(), messaging.Dispatch{
+		Topic:      "orders",
+		Partition:  5,
+		Expiration: time.Hour * 24,
+		Delay:      time.Second * 30,
+	})
+
+	this.So(err, should.BeNil)
+	this.So(count, should.Equal, 1)
+	this.So(this.publishExchanges, should.Equal, []string{"orders.delay.1m0s"})
+	this.So(this.publishKeys, should.Equal, []string{"5"})
+	this.So(this.publishMessages[0].Expiration, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWriteDelayedDispatchWithoutSupportingTier_ReturnNumberOfWritesThusFarAndError() {
+	this.delayTiers = []time.Duration{time.Minute}
+	this.initializeWriter()
+
+	count, err := this.writer.Write(context.Background(),
+		messaging.Dispatch{Topic: "orders"},
+		messaging.Dispatch{Topic: "orders", Delay: time.Hour},
+		messaging.Dispatch{Topic: "orders"})
+
+	this.So(count, should.Equal, 1)
+	this.So(errors.Is(err, ErrDelayNotSupported), should.BeTrue)
+	this.So(this.publishExchanges, should.Equal, []string{"orders"})
+}
+func (this *WriterFixture) TestWhenNotMandatory_PublishWithoutMandatoryFlag() {
+	_, _ = this.writer.Write(context.Background(), messaging.Dispatch{Topic: "a"})
+
+	this.So(this.publishMessages, should.HaveLength, 1)
+	this.So(this.mandatoryCount, should.Equal, 0)
+	this.So(this.returns, should.BeNil)
+}
+func (this *WriterFixture) TestWhenMandatory_PublishWithMandatoryFlag() {
+	this.mandatory = true
+	this.initializeWriter()
+
+	count, err := this.writer.Write(context.Background(), messaging.Dispatch{Topic: "a"})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.mandatoryCount, should.Equal, 1)
+	this.So(this.publishExchanges, should.Equal, []string{"a"})
+}
+func (this *WriterFixture) TestWhenMandatoryDispatchesReturned_CommitReturnsUnroutableErrorAndHandlerInvoked() {
+	this.mandatory = true
+	this.initializeWriter()
+	_, _ = this.writer.Write(context.Background(), messaging.Dispatch{Topic: "a", MessageID: 1})
+	this.returns <- amqp.Return{Exchange: "a", RoutingKey: "5", MessageId: "1", Type: "type", ReplyCode: 312, ReplyText: "NO_ROUTE"}
+	this.returns <- amqp.Return{Exchange: "b", MessageId: "2", ReplyCode: 312, ReplyText: "NO_ROUTE"}
+
+	err := this.writer.Commit()
+
+	expected := []ReturnedDispatch{
+		{Topic: "a", RoutingKey: "5", MessageID: 1, MessageType: "type", ReplyCode: 312, ReplyText: "NO_ROUTE"},
+		{Topic: "b", MessageID: 2, ReplyCode: 312, ReplyText: "NO_ROUTE"},
+	}
+	this.So(err, should.Equal, &UnroutableError{Returned: expected})
+	this.So(err.Error(), should.ContainSubstring, "[1] to topic [a]")
+	this.So(this.returned, should.Equal, expected)
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *WriterFixture) TestWhenMandatoryDispatchesReturnedBeforeRollback_ReturnsDiscarded() {
+	this.mandatory = true
+	this.initializeWriter()
+	_, _ = this.writer.Write(context.Background(), messaging.Dispatch{Topic: "a"})
+	this.returns <- amqp.Return{Exchange: "a"}
+
+	_ = this.writer.Rollback()
+
+	this.So(this.returned, should.HaveLength, 1)
+	this.So(this.writer.Commit(), should.BeNil)
+}
+func (this *WriterFixture) TestWhenMandatoryDispatchesReturnedToIdleNonTransactionalWriter_HandlerInvoked() {
+	this.mandatory = true
+	this.transactional = false
+	this.initializeWriter()
+	_, _ = this.writer.Write(context.Background(), messaging.Dispatch{Topic: "a"})
+
+	this.returns <- amqp.Return{Exchange: "a"}
+
+	select {
+	case <-this.handled:
+	case <-time.After(time.Millisecond * 250):
+		this.Error("timed out awaiting the return handler")
+	}
+	this.So(this.returned, should.Equal, []ReturnedDispatch{{Topic: "a"}})
+	this.So(this.writer.(defaultWriter).returns.take(), should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenMoreReturnsThanBuffered_ReceiveAllWithoutBlockingConnection() {
+	this.mandatory = true
+	this.initializeWriter()
+	_, _ = this.writer.Write(context.Background(), messaging.Dispatch{Topic: "a"})
+
+	for i := 0; i < maxBufferedReturns*2; i++ {
+		this.returns <- amqp.Return{Exchange: "a"} // the connection sends each return synchronously
+	}
+	err := this.writer.Commit()
+
+	var unroutable *UnroutableError
+	this.So(errors.As(err, &unroutable), should.BeTrue)
+	this.So(unroutable.Returned, should.HaveLength, maxBufferedReturns*2)
+	this.So(this.returned, should.HaveLength, maxBufferedReturns*2)
+}
+func (this *WriterFixture) TestWhenReturnChannelClosed_ListenerConcludes() {
+	this.mandatory = true
+	this.initializeWriter()
+	close(this.returns)
+
+	err := this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	select {
+	case <-this.writer.(defaultWriter).returns.done:
+	case <-time.After(time.Millisecond * 250):
+		this.Error("timed out awaiting the return listener to conclude")
+	}
+	this.So(this.writer.Commit(), should.BeNil)
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func (this *WriterFixture) Close() error      { return this.closeError }
+func (this *WriterFixture) Close() error { return this.closeError }
+func (this *WriterFixture) NotifyClose(closes chan *amqp.Error) chan *amqp.Error {
+	this.closes = closes
+	return closes
+}
 func (this *WriterFixture) TxCommit() error   { return this.commitError }
 func (this *WriterFixture) TxRollback() error { return this.rollbackError }
 func (this *WriterFixture) Publish(exchange, key string, envelope amqp.Publishing) error {
@@ -219,6 +437,10 @@ func (this *WriterFixture) Publish(exchange, key string, envelope amqp.Publishin
 	this.publishKeys = append(this.publishKeys, key)
 	this.publishMessages = append(this.publishMessages, envelope)
 
+	if this.closeOnPublish != nil {
+		this.closes <- this.closeOnPublish
+	}
+
 	if len(this.publishMessages) >= this.publishCallsBeforeError {
 		return this.publishError
 	}
@@ -226,11 +448,36 @@ func (this *WriterFixture) Publish(exchange, key string, envelope amqp.Publishin
 	return nil
 }
 
-func (this *WriterFixture) DeclareQueue(name string, replicated bool) error   { panic("nop") }
-func (this *WriterFixture) DeclareExchange(name string) error                 { panic("nop") }
-func (this *WriterFixture) BindQueue(queue, exchange string) error            { panic("nop") }
-func (this *WriterFixture) BufferCapacity(value uint16) error                 { panic("nop") }
-func (this *WriterFixture) Consume(_, _ string) (<-chan amqp.Delivery, error) { panic("nop") }
-func (this *WriterFixture) Ack(deliveryTag uint64, multiple bool) error       { panic("nop") }
-func (this *WriterFixture) CancelConsumer(consumerID string) error            { panic("nop") }
-func (this *WriterFixture) Tx() error                                         { panic("nop") }
+func (this *WriterFixture) DeclareQueue(name string, replicated bool, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *WriterFixture) DeclareExchange(name, kind string, _ map[string]any) error { panic("nop") }
+func (this *WriterFixture) BindQueue(queue, exchange, key string, arguments map[string]any) error {
+	panic("nop")
+}
+func (this *WriterFixture) BufferCapacity(value uint16) error { panic("nop") }
+func (this *WriterFixture) Consume(_, _ string, _ map[string]any) (<-chan amqp.Delivery, error) {
+	panic("nop")
+}
+func (this *WriterFixture) Ack(deliveryTag uint64, multiple bool) error { panic("nop") }
+func (this *WriterFixture) CancelConsumer(consumerID string) error      { panic("nop") }
+func (this *WriterFixture) Tx() error                                   { panic("nop") }
+func (this *WriterFixture) Confirm(noWait bool) error                   { panic("nop") }
+func (this *WriterFixture) NotifyPublish(chan amqp.Confirmation) chan amqp.Confirmation {
+	panic("nop")
+}
+func (this *WriterFixture) GetNextPublishSeqNo() uint64 { panic("nop") }
+func (this *WriterFixture) PublishMandatory(exchange, key string, envelope amqp.Publishing) error {
+	this.mandatoryCount++
+	return this.Publish(exchange, key, envelope)
+}
+func (this *WriterFixture) NotifyReturn(returns chan amqp.Return) chan amqp.Return {
+	this.returns = returns
+	return returns
+}
+func (this *WriterFixture) Nack(uint64, bool, bool) error                            { panic("nop") }
+func (this *WriterFixture) DeclareQueuePassive(string) error                         { panic("nop") }
+func (this *WriterFixture) DeclareExchangePassive(string, string) error              { panic("nop") }
+func (this *WriterFixture) DeleteQueue(string) error                                 { panic("nop") }
+func (this *WriterFixture) DeleteExchange(string) error                              { panic("nop") }
+func (this *WriterFixture) UnbindQueue(string, string, string, map[string]any) error { panic("nop") }
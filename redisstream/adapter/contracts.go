@@ -0,0 +1,58 @@
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"time"
+)
+
+func New() Connector { return redisConnector{} }
+
+type Config struct {
+	Address   string
+	Username  string
+	Password  string
+	Database  int
+	TLSConfig *tls.Config
+}
+
+// Record is an entry to be appended to a stream. When MaxLength is positive, the stream is trimmed (approximately) to
+// that many entries as the record is appended.
+type Record struct {
+	Stream    string
+	MaxLength int64
+	Values    map[string]any
+}
+
+// Entry is an entry read from a stream along with the ID assigned by the server.
+type Entry struct {
+	Stream string
+	ID     string
+	Values map[string]any
+}
+
+type Connector interface {
+	Connect(ctx context.Context, config Config) (Client, error)
+}
+
+type Client interface {
+	// Append adds each of the records to its stream within a single MULTI/EXEC transaction.
+	Append(ctx context.Context, records []Record) error
+
+	// DeclareGroup creates the consumer group on the stream (creating the stream if necessary) with its position at the
+	// ID provided; if the group already exists, it is left unchanged.
+	DeclareGroup(ctx context.Context, stream, group, start string) error
+
+	// ReadGroup reads new entries (XREADGROUP with ">") from the streams on behalf of the consumer, waiting up to the
+	// block duration for entries to arrive. No entries and no error are returned if the wait elapses.
+	ReadGroup(ctx context.Context, group, consumer string, streams []string, count int64, block time.Duration) ([]Entry, error)
+
+	// AutoClaim transfers to the consumer those pending entries of the group which have been idle for at least the
+	// duration provided (XAUTOCLAIM), returning the entries claimed and the cursor from which to continue.
+	AutoClaim(ctx context.Context, stream, group, consumer string, idle time.Duration, start string, count int64) ([]Entry, string, error)
+
+	Acknowledge(ctx context.Context, stream, group string, ids ...string) error
+
+	io.Closer
+}
@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisConnector struct{}
+
+func (this redisConnector) Connect(ctx context.Context, config Config) (Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:      config.Address,
+		Username:  config.Username,
+		Password:  config.Password,
+		DB:        config.Database,
+		TLSConfig: config.TLSConfig,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return redisClient{Client: client}, nil
+}
+
+type redisClient struct{ *redis.Client }
+
+func (this redisClient) Append(ctx context.Context, records []Record) error {
+	_, err := this.TxPipelined(ctx, func(pipeline redis.Pipeliner) error {
+		for _, record := range records {
+			pipeline.XAdd(ctx, &redis.XAddArgs{
+				Stream: record.Stream,
+				MaxLen: record.MaxLength,
+				Approx: record.MaxLength > 0,
+				Values: record.Values,
+			})
+		}
+		return nil
+	})
+	return err
+}
+
+func (this redisClient) DeclareGroup(ctx context.Context, stream, group, start string) error {
+	err := this.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil // the group already exists
+	}
+
+	return err
+}
+
+func (this redisClient) ReadGroup(ctx context.Context, group, consumer string, streams []string, count int64, block time.Duration) ([]Entry, error) {
+	ids := make([]string, 0, len(streams)*2)
+	ids = append(ids, streams...)
+	for range streams {
+		ids = append(ids, ">")
+	}
+
+	results, err := this.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  ids,
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, result := range results {
+		entries = appendEntries(entries, result.Stream, result.Messages)
+	}
+
+	return entries, nil
+}
+
+func (this redisClient) AutoClaim(ctx context.Context, stream, group, consumer string, idle time.Duration, start string, count int64) ([]Entry, string, error) {
+	messages, next, err := this.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  idle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return appendEntries(nil, stream, messages), next, nil
+}
+
+func (this redisClient) Acknowledge(ctx context.Context, stream, group string, ids ...string) error {
+	return this.XAck(ctx, stream, group, ids...).Err()
+}
+
+func appendEntries(entries []Entry, stream string, messages []redis.XMessage) []Entry {
+	for _, message := range messages {
+		entries = append(entries, Entry{Stream: stream, ID: message.ID, Values: message.Values})
+	}
+
+	return entries
+}
@@ -0,0 +1,130 @@
+package redisstream
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/redisstream/adapter"
+)
+
+func New(options ...option) messaging.Connector {
+	var config configuration
+	Options.apply(options...)(&config)
+	return newConnector(config)
+}
+
+type configuration struct {
+	Address      string
+	Username     string
+	Password     string
+	Database     int
+	TLSConfig    *tls.Config
+	ConsumerName string
+	MaxLength    int64
+	ClaimIdle    time.Duration
+	BlockTimeout time.Duration
+	Connector    adapter.Connector
+	Logger       logger
+	Now          func() time.Time
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// Address is the host:port of the Redis server.
+func (singleton) Address(value string) option {
+	return func(this *configuration) { this.Address = value }
+}
+func (singleton) Credentials(username, password string) option {
+	return func(this *configuration) { this.Username = username; this.Password = password }
+}
+func (singleton) Database(value int) option {
+	return func(this *configuration) { this.Database = value }
+}
+func (singleton) TLSConfig(value *tls.Config) option {
+	return func(this *configuration) { this.TLSConfig = value }
+}
+
+// ConsumerName identifies this process within each consumer group it joins. It must be unique among the members of
+// a group and should be stable across restarts such that entries pending to this consumer are resumed. By default,
+// it is the host name and process ID.
+func (singleton) ConsumerName(value string) option {
+	return func(this *configuration) { this.ConsumerName = value }
+}
+
+// MaxLength, when positive, trims each stream to approximately that many entries as entries are appended to it.
+func (singleton) MaxLength(value int64) option {
+	return func(this *configuration) { this.MaxLength = value }
+}
+
+// ClaimIdle is the duration an entry may remain pending (delivered but unacknowledged) to a consumer before it is
+// considered abandoned and claimed by another consumer of the same group. It is also the interval at which each stream
+// looks for abandoned entries to claim. A non-positive duration, which would claim the entries being processed by every
+// other live consumer, is replaced by the default.
+func (singleton) ClaimIdle(value time.Duration) option {
+	return func(this *configuration) {
+		if value <= 0 {
+			value = defaultClaimIdle
+		}
+
+		this.ClaimIdle = value
+	}
+}
+
+// BlockTimeout is the longest duration each request waits for new entries to arrive before the stream checks for
+// abandoned entries and begins waiting again.
+func (singleton) BlockTimeout(value time.Duration) option {
+	return func(this *configuration) { this.BlockTimeout = value }
+}
+func (singleton) Connector(value adapter.Connector) option {
+	return func(this *configuration) { this.Connector = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+func (singleton) Now(value func() time.Time) option {
+	return func(this *configuration) { this.Now = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultNow = time.Now
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.Address(defaultAddress),
+		Options.ConsumerName(defaultConsumerName()),
+		Options.ClaimIdle(defaultClaimIdle),
+		Options.BlockTimeout(time.Second * 5),
+		Options.Connector(adapter.New()),
+		Options.Logger(defaultLogger),
+		Options.Now(defaultNow),
+	}, options...)
+}
+
+const (
+	defaultAddress   = "127.0.0.1:6379"
+	defaultClaimIdle = time.Minute
+)
+
+func defaultConsumerName() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
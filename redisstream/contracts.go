@@ -0,0 +1,35 @@
+package redisstream
+
+import "errors"
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+var (
+	ErrMissingGroupName = errors.New("the group name (or stream name) is required to join a consumer group")
+	ErrMissingTopics    = errors.New("at least one topic (stream key) is required")
+	ErrUnknownDelivery  = errors.New("the delivery is not outstanding on the stream and cannot be acknowledged")
+	ErrClosed           = errors.New("the resource has already been closed")
+)
+
+// Entry IDs, being of the form <unix-milliseconds>-<sequence>, are packed into the Sequence of each Delivery (and of
+// each StreamConfig) as the milliseconds shifted left by entrySequenceBits combined with the sequence.
+const (
+	entrySequenceBits = 20
+	entrySequenceMask = 1<<entrySequenceBits - 1
+)
+
+// The fields of each stream entry. Application-specified headers are stored as fields having the FieldHeaderPrefix
+// followed by the header name.
+const (
+	FieldPayload         = "payload"
+	FieldSourceID        = "source-id"
+	FieldMessageID       = "message-id"
+	FieldCorrelationID   = "correlation-id"
+	FieldMessageType     = "message-type"
+	FieldContentType     = "content-type"
+	FieldContentEncoding = "content-encoding"
+	FieldTimestamp       = "timestamp"
+	FieldHeaderPrefix    = "header:"
+)
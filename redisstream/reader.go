@@ -0,0 +1,91 @@
+package redisstream
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/redisstream/adapter"
+)
+
+type defaultReader struct {
+	inner   adapter.Client
+	config  configuration
+	streams []io.Closer
+	closed  bool
+	mutex   sync.Mutex
+	logger  logger
+}
+
+func newReader(inner adapter.Client, config configuration) messaging.Reader {
+	return &defaultReader{inner: inner, config: config, logger: config.Logger}
+}
+
+// Stream joins the consumer group named by GroupName (or, when empty, by StreamName) on each of the stream keys named
+// by Topics. When EstablishTopology is specified, the group is created on each stream key, creating the stream itself
+// if necessary, such that the group reads the entries following the entry ID indicated by Sequence, which is packed
+// as is the Sequence of each Delivery; a zero Sequence includes all entries of the stream.
+func (this *defaultReader) Stream(ctx context.Context, settings messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	group := settings.GroupName
+	if len(group) == 0 {
+		group = settings.StreamName
+	}
+	if len(group) == 0 {
+		return nil, ErrMissingGroupName
+	}
+	if len(settings.Topics) == 0 {
+		return nil, ErrMissingTopics
+	}
+
+	if err := this.establishTopology(ctx, group, settings); err != nil {
+		this.logger.Printf("[WARN] Unable to establish consumer group [%s]: %s", group, err)
+		return nil, err
+	}
+
+	this.logger.Printf("[INFO] Consumer [%s] joined group [%s] on streams %v, awaiting messages...", this.config.ConsumerName, group, settings.Topics)
+	stream := newStream(this.inner, group, settings, this.config)
+	this.streams = append(this.streams, stream)
+	return stream, nil
+}
+func (this *defaultReader) establishTopology(ctx context.Context, group string, settings messaging.StreamConfig) error {
+	if !settings.EstablishTopology {
+		return nil
+	}
+
+	start := formatEntryID(settings.Sequence)
+	for _, topic := range settings.Topics {
+		if err := this.inner.DeclareGroup(ctx, topic, group, start); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatEntryID unpacks the sequence produced by parseEntrySequence into the entry ID it represents.
+func formatEntryID(sequence uint64) string {
+	return strconv.FormatUint(sequence>>entrySequenceBits, 10) + "-" + strconv.FormatUint(sequence&entrySequenceMask, 10)
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
@@ -0,0 +1,109 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/redisstream/adapter"
+)
+
+func TestReaderFixture(t *testing.T) {
+	gunit.Run(new(ReaderFixture), t)
+}
+
+type ReaderFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	reader messaging.Reader
+
+	declared     []string
+	declareError error
+}
+
+func (this *ReaderFixture) Setup() {
+	this.ctx = context.Background()
+	config := configuration{}
+	Options.apply(Options.Connector(nil), Options.ConsumerName("consumer"))(&config)
+	this.reader = newReader(this, config)
+}
+
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutGroupOrStreamName_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{Topics: []string{"a"}})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingGroupName)
+}
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutTopics_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{GroupName: "group"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingTopics)
+}
+func (this *ReaderFixture) TestWhenOpeningStream_JoinGroupWithConfiguredConsumerName() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{GroupName: "group", Topics: []string{"a", "b"}})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).group, should.Equal, "group")
+	this.So(stream.(*defaultStream).consumer, should.Equal, "consumer")
+	this.So(stream.(*defaultStream).topics, should.Equal, []string{"a", "b"})
+	this.So(this.declared, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutGroupName_UseStreamNameAsGroup() {
+	stream, _ := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue", Topics: []string{"a"}})
+
+	this.So(stream.(*defaultStream).group, should.Equal, "queue")
+}
+func (this *ReaderFixture) TestWhenEstablishingTopology_DeclareGroupOnEachStreamKey() {
+	_, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		EstablishTopology: true,
+		GroupName:         "group",
+		Topics:            []string{"a", "b"},
+		Sequence:          42<<20 | 7,
+	})
+
+	this.So(err, should.BeNil)
+	this.So(this.declared, should.Equal, []string{"a/group/42-7", "b/group/42-7"})
+}
+func (this *ReaderFixture) TestWhenDeclaringGroupFails_ReturnError() {
+	this.declareError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{EstablishTopology: true, GroupName: "group", Topics: []string{"a"}})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.declareError)
+}
+func (this *ReaderFixture) TestWhenClosed_StreamsClosedAndNoFurtherStreamsOpened() {
+	stream, _ := this.reader.Stream(this.ctx, messaging.StreamConfig{GroupName: "group", Topics: []string{"a"}})
+
+	err := this.reader.Close()
+	_, streamErr := this.reader.Stream(this.ctx, messaging.StreamConfig{GroupName: "group", Topics: []string{"a"}})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).closed.Err(), should.NotBeNil)
+	this.So(streamErr, should.Equal, ErrClosed)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ReaderFixture) DeclareGroup(_ context.Context, stream, group, start string) error {
+	this.declared = append(this.declared, stream+"/"+group+"/"+start)
+	return this.declareError
+}
+
+func (this *ReaderFixture) Append(_ context.Context, _ []adapter.Record) error { panic("nop") }
+func (this *ReaderFixture) ReadGroup(_ context.Context, _, _ string, _ []string, _ int64, _ time.Duration) ([]adapter.Entry, error) {
+	panic("nop")
+}
+func (this *ReaderFixture) AutoClaim(_ context.Context, _, _, _ string, _ time.Duration, _ string, _ int64) ([]adapter.Entry, string, error) {
+	panic("nop")
+}
+func (this *ReaderFixture) Acknowledge(_ context.Context, _, _ string, _ ...string) error {
+	panic("nop")
+}
+func (this *ReaderFixture) Close() error { panic("nop") }
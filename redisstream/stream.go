@@ -0,0 +1,227 @@
+package redisstream
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/redisstream/adapter"
+)
+
+// defaultStream reads entries on behalf of a single consumer of a consumer group. Each time the buffer of entries is
+// exhausted it first claims any entries which have been pending to other (presumably failed) consumers for longer than
+// the configured idle duration and otherwise waits for new entries. Entries remain pending until acknowledged; those
+// not acknowledged when the stream is closed are claimed by another consumer once they have become idle.
+type defaultStream struct {
+	inner     adapter.Client
+	group     string
+	consumer  string
+	topics    []string
+	capacity  int64
+	claimIdle time.Duration
+	block     time.Duration
+	now       func() time.Time
+	logger    logger
+
+	closed   context.Context
+	shutdown context.CancelFunc
+
+	buffer    []adapter.Entry
+	cursors   map[string]string
+	lastClaim time.Time
+
+	mutex    sync.Mutex
+	sequence uint64
+	inflight map[uint64]adapter.Entry
+}
+
+func newStream(inner adapter.Client, group string, settings messaging.StreamConfig, config configuration) messaging.Stream {
+	capacity := int64(settings.BufferCapacity)
+	if capacity <= 0 {
+		capacity = defaultStreamBufferCapacity
+	}
+
+	closed, shutdown := context.WithCancel(context.Background())
+	return &defaultStream{
+		inner:     inner,
+		group:     group,
+		consumer:  config.ConsumerName,
+		topics:    settings.Topics,
+		capacity:  capacity,
+		claimIdle: config.ClaimIdle,
+		block:     config.BlockTimeout,
+		now:       config.Now,
+		logger:    config.Logger,
+		closed:    closed,
+		shutdown:  shutdown,
+		cursors:   make(map[string]string, len(settings.Topics)),
+		inflight:  make(map[uint64]adapter.Entry),
+	}
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	for len(this.buffer) == 0 {
+		if err := this.fill(ctx); err != nil {
+			return err
+		}
+	}
+
+	source := this.buffer[0]
+	this.buffer[0] = adapter.Entry{}
+	this.buffer = this.buffer[1:]
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.sequence++
+	this.inflight[this.sequence] = source
+	processDelivery(source, this.sequence, target)
+	return nil
+}
+func (this *defaultStream) fill(ctx context.Context) (err error) {
+	if this.closed.Err() != nil {
+		return io.EOF
+	} else if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(this.closed, cancel)()
+
+	if now := this.now(); now.Sub(this.lastClaim) >= this.claimIdle {
+		this.lastClaim = now
+		this.buffer, err = this.claim(ctx)
+	} else {
+		this.buffer, err = this.inner.ReadGroup(ctx, this.group, this.consumer, this.topics, this.capacity, this.block)
+	}
+
+	if this.closed.Err() != nil {
+		return io.EOF
+	} else if err != nil && ctx.Err() == nil {
+		this.logger.Printf("[WARN] Unable to read from consumer group [%s]: %s", this.group, err)
+	}
+
+	return err
+}
+func (this *defaultStream) claim(ctx context.Context) (entries []adapter.Entry, err error) {
+	for _, topic := range this.topics {
+		start := this.cursors[topic]
+		if len(start) == 0 {
+			start = initialClaimCursor
+		}
+
+		claimed, next, err := this.inner.AutoClaim(ctx, topic, this.group, this.consumer, this.claimIdle, start, this.capacity)
+		if err != nil {
+			return entries, err
+		}
+
+		this.cursors[topic] = next
+		entries = append(entries, claimed...)
+	}
+
+	if len(entries) > 0 {
+		this.logger.Printf("[INFO] Claimed [%d] abandoned entries for consumer group [%s].", len(entries), this.group)
+	}
+
+	return entries, nil
+}
+
+func processDelivery(source adapter.Entry, deliveryID uint64, target *messaging.Delivery) {
+	target.Upstream = source
+	target.DeliveryID = deliveryID
+	target.Durable = true
+	target.Topic = source.Stream
+	target.Timestamp = parseEntryTimestamp(source.ID)
+	target.Sequence = parseEntrySequence(source.ID)
+	target.Payload = nil
+	target.Headers = nil
+
+	for key, raw := range source.Values {
+		value := formatField(raw)
+		switch key {
+		case FieldPayload:
+			target.Payload = []byte(value)
+		case FieldSourceID:
+			target.SourceID = parseUint64(value)
+		case FieldMessageID:
+			target.MessageID = parseUint64(value)
+		case FieldCorrelationID:
+			target.CorrelationID = parseUint64(value)
+		case FieldMessageType:
+			target.MessageType = value
+		case FieldContentType:
+			target.ContentType = value
+		case FieldContentEncoding:
+			target.ContentEncoding = value
+		case FieldTimestamp:
+			if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				target.Timestamp = parsed
+			}
+		default:
+			if !strings.HasPrefix(key, FieldHeaderPrefix) {
+				continue
+			}
+			if target.Headers == nil {
+				target.Headers = make(map[string]any, len(source.Values))
+			}
+			target.Headers[strings.TrimPrefix(key, FieldHeaderPrefix)] = value
+		}
+	}
+}
+func parseEntryTimestamp(id string) time.Time {
+	milliseconds, _, _ := strings.Cut(id, "-") // IDs are of the form <unix-milliseconds>-<sequence>
+	return time.UnixMilli(int64(parseUint64(milliseconds))).UTC()
+}
+
+// parseEntrySequence packs the entry ID into a single value, the milliseconds component in the upper bits and the
+// sequence within that millisecond in the lower entrySequenceBits bits, such that the values are ordered as the
+// entries themselves. See formatEntryID for the reverse.
+func parseEntrySequence(id string) uint64 {
+	milliseconds, sequence, _ := strings.Cut(id, "-")
+	return parseUint64(milliseconds)<<entrySequenceBits | parseUint64(sequence)&entrySequenceMask
+}
+func parseUint64(value string) uint64 {
+	parsed, _ := strconv.ParseUint(value, 10, 64)
+	return parsed
+}
+
+func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messaging.Delivery) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	ids := make(map[string][]string, len(this.topics))
+	for _, delivery := range deliveries {
+		entry, contains := this.inflight[delivery.DeliveryID]
+		if !contains {
+			return ErrUnknownDelivery
+		}
+		ids[entry.Stream] = append(ids[entry.Stream], entry.ID)
+	}
+
+	for stream, values := range ids {
+		if err := this.inner.Acknowledge(ctx, stream, this.group, values...); err != nil {
+			this.logger.Printf("[WARN] Unable to acknowledge entries of stream [%s]: %s", stream, err)
+			return err
+		}
+	}
+
+	for _, delivery := range deliveries {
+		delete(this.inflight, delivery.DeliveryID)
+	}
+
+	return nil
+}
+
+func (this *defaultStream) Close() error {
+	this.shutdown()
+	return nil
+}
+
+const (
+	defaultStreamBufferCapacity = 64
+	initialClaimCursor          = "0-0"
+)
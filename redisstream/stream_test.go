@@ -0,0 +1,265 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/redisstream/adapter"
+)
+
+func TestStreamFixture(t *testing.T) {
+	gunit.Run(new(StreamFixture), t)
+}
+
+type StreamFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	now    time.Time
+	config configuration
+	stream messaging.Stream
+
+	claimCalls   []string
+	claimResults [][]adapter.Entry
+	claimError   error
+
+	readCalls    int
+	readStreams  []string
+	readCount    int64
+	readBlock    time.Duration
+	readResults  [][]adapter.Entry
+	readError    error
+	readCallback func()
+
+	acknowledged     []string
+	acknowledgeError error
+}
+
+func (this *StreamFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	Options.apply(
+		Options.Connector(nil),
+		Options.ConsumerName("consumer"),
+		Options.ClaimIdle(time.Minute),
+		Options.BlockTimeout(time.Second),
+		Options.Now(func() time.Time { return this.now }),
+	)(&this.config)
+	this.stream = newStream(this, "group", messaging.StreamConfig{Topics: []string{"a", "b"}, BufferCapacity: 8}, this.config)
+}
+
+func (this *StreamFixture) TestWhenFirstReading_ClaimAbandonedEntriesOfEachStreamFirst() {
+	this.claimResults = [][]adapter.Entry{{{Stream: "a", ID: "1-0"}}, nil}
+
+	delivery := this.read()
+
+	this.So(this.claimCalls, should.Equal, []string{"a/group/consumer/1m0s/0-0/8", "b/group/consumer/1m0s/0-0/8"})
+	this.So(this.readCalls, should.Equal, 0)
+	this.So(delivery.Topic, should.Equal, "a")
+	this.So(delivery.Upstream, should.Equal, adapter.Entry{Stream: "a", ID: "1-0"})
+}
+func (this *StreamFixture) TestWhenClaimIdleNotPositive_ClaimOnlyEntriesIdleForDefaultDuration() {
+	for _, idle := range []time.Duration{0, -time.Second} {
+		this.claimCalls = nil
+		Options.ClaimIdle(idle)(&this.config)
+		this.stream = newStream(this, "group", messaging.StreamConfig{Topics: []string{"a"}, BufferCapacity: 8}, this.config)
+		this.readResults = [][]adapter.Entry{{{Stream: "a", ID: "1-0"}}}
+
+		_ = this.read()
+
+		this.So(this.claimCalls, should.Equal, []string{"a/group/consumer/1m0s/0-0/8"})
+	}
+}
+func (this *StreamFixture) TestWhenNothingToClaim_ReadNewEntriesFromGroup() {
+	this.readResults = [][]adapter.Entry{{{Stream: "b", ID: "2-0"}}}
+
+	delivery := this.read()
+
+	this.So(len(this.claimCalls), should.Equal, 2)
+	this.So(this.readCalls, should.Equal, 1)
+	this.So(this.readStreams, should.Equal, []string{"a", "b"})
+	this.So(this.readCount, should.Equal, 8)
+	this.So(this.readBlock, should.Equal, time.Second)
+	this.So(delivery.Topic, should.Equal, "b")
+}
+func (this *StreamFixture) TestWhenClaimIdleElapses_ClaimAgainContinuingFromCursor() {
+	this.readResults = [][]adapter.Entry{nil, {{Stream: "a", ID: "3-0"}}}
+	this.readCallback = func() { this.now = this.now.Add(time.Minute) }
+
+	_ = this.read()
+
+	this.So(this.claimCalls, should.Equal, []string{
+		"a/group/consumer/1m0s/0-0/8", "b/group/consumer/1m0s/0-0/8",
+		"a/group/consumer/1m0s/next/8", "b/group/consumer/1m0s/next/8",
+	})
+}
+func (this *StreamFixture) TestWhenReading_MapEntryToDelivery() {
+	this.readResults = [][]adapter.Entry{{{Stream: "a", ID: "1577934245000-3", Values: map[string]any{
+		FieldPayload:              "payload",
+		FieldSourceID:             "1",
+		FieldMessageID:            "2",
+		FieldCorrelationID:        "3",
+		FieldMessageType:          "message-type",
+		FieldContentType:          "content-type",
+		FieldContentEncoding:      "content-encoding",
+		FieldTimestamp:            "2021-01-01T00:00:00.000000001Z",
+		FieldHeaderPrefix + "key": "value",
+		"unknown":                 "ignored",
+	}}}}
+
+	delivery := this.read()
+
+	this.So(delivery.DeliveryID, should.Equal, 1)
+	this.So(delivery.SourceID, should.Equal, 1)
+	this.So(delivery.MessageID, should.Equal, 2)
+	this.So(delivery.CorrelationID, should.Equal, 3)
+	this.So(delivery.MessageType, should.Equal, "message-type")
+	this.So(delivery.ContentType, should.Equal, "content-type")
+	this.So(delivery.ContentEncoding, should.Equal, "content-encoding")
+	this.So(delivery.Timestamp, should.Equal, time.Date(2021, 1, 1, 0, 0, 0, 1, time.UTC))
+	this.So(delivery.Durable, should.BeTrue)
+	this.So(delivery.Topic, should.Equal, "a")
+	this.So(delivery.Sequence, should.Equal, uint64(1577934245000<<20|3))
+	this.So(delivery.Headers, should.Equal, map[string]any{"key": "value"})
+	this.So(delivery.Payload, should.Equal, []byte("payload"))
+}
+func (this *StreamFixture) TestWhenEntryHasNoTimestampField_UseEntryIDTimestamp() {
+	this.readResults = [][]adapter.Entry{{{Stream: "a", ID: "1577934245000-7"}}}
+
+	delivery := this.read()
+
+	this.So(delivery.Timestamp, should.Equal, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+}
+func (this *StreamFixture) TestWhenMultipleEntriesRead_ServeFromBufferWithIncrementingDeliveryIDs() {
+	this.readResults = [][]adapter.Entry{{{Stream: "a", ID: "1-0"}, {Stream: "a", ID: "2-0"}}}
+
+	first, second := this.read(), this.read()
+
+	this.So(first.DeliveryID, should.Equal, 1)
+	this.So(second.DeliveryID, should.Equal, 2)
+	this.So(this.readCalls, should.Equal, 1)
+}
+func (this *StreamFixture) TestWhenClaimFails_ReturnError() {
+	this.claimError = errors.New("")
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, this.claimError)
+}
+func (this *StreamFixture) TestWhenReadFails_ReturnError() {
+	this.readError = errors.New("")
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, this.readError)
+}
+func (this *StreamFixture) TestWhenContextCancelled_ReturnError() {
+	ctx, cancel := context.WithCancel(this.ctx)
+	cancel()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(ctx, &delivery)
+
+	this.So(err, should.Equal, context.Canceled)
+}
+func (this *StreamFixture) TestWhenClosedWhileWaiting_ReturnEOF() {
+	this.readCallback = func() { _ = this.stream.Close() }
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+}
+func (this *StreamFixture) TestWhenAcknowledging_AcknowledgeEntryIDsGroupedByStream() {
+	this.readResults = [][]adapter.Entry{{{Stream: "a", ID: "1-0"}, {Stream: "b", ID: "2-0"}, {Stream: "a", ID: "3-0"}}}
+	deliveries := []messaging.Delivery{this.read(), this.read(), this.read()}
+
+	err := this.stream.Acknowledge(this.ctx, deliveries...)
+
+	this.So(err, should.BeNil)
+	this.So(len(this.acknowledged), should.Equal, 2)
+	this.So(this.acknowledged, should.Contain, "a/group/[1-0 3-0]")
+	this.So(this.acknowledged, should.Contain, "b/group/[2-0]")
+}
+func (this *StreamFixture) TestWhenAcknowledgingUnknownDelivery_ReturnError() {
+	err := this.stream.Acknowledge(this.ctx, messaging.Delivery{DeliveryID: 1})
+
+	this.So(err, should.Equal, ErrUnknownDelivery)
+	this.So(this.acknowledged, should.BeEmpty)
+}
+func (this *StreamFixture) TestWhenAcknowledgementFails_ReturnErrorAndKeepDeliveryOutstanding() {
+	this.readResults = [][]adapter.Entry{{{Stream: "a", ID: "1-0"}}}
+	delivery := this.read()
+	this.acknowledgeError = errors.New("")
+
+	err := this.stream.Acknowledge(this.ctx, delivery)
+	this.acknowledgeError = nil
+	retryErr := this.stream.Acknowledge(this.ctx, delivery)
+
+	this.So(err, should.NotBeNil)
+	this.So(retryErr, should.BeNil)
+}
+
+func (this *StreamFixture) read() (delivery messaging.Delivery) {
+	this.So(this.stream.Read(this.ctx, &delivery), should.BeNil)
+	return delivery
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *StreamFixture) AutoClaim(_ context.Context, stream, group, consumer string, idle time.Duration, start string, count int64) ([]adapter.Entry, string, error) {
+	this.claimCalls = append(this.claimCalls, fmt.Sprintf("%s/%s/%s/%s/%s/%d", stream, group, consumer, idle, start, count))
+	if this.claimError != nil {
+		return nil, "", this.claimError
+	}
+
+	if len(this.claimResults) == 0 {
+		return nil, "next", nil
+	}
+
+	result := this.claimResults[0]
+	this.claimResults = this.claimResults[1:]
+	return result, "next", nil
+}
+func (this *StreamFixture) ReadGroup(_ context.Context, _, _ string, streams []string, count int64, block time.Duration) ([]adapter.Entry, error) {
+	this.readCalls++
+	this.readStreams = streams
+	this.readCount = count
+	this.readBlock = block
+	if this.readCallback != nil {
+		this.readCallback()
+	}
+	if this.readError != nil {
+		return nil, this.readError
+	}
+
+	if len(this.readResults) == 0 {
+		return nil, nil
+	}
+
+	result := this.readResults[0]
+	this.readResults = this.readResults[1:]
+	return result, nil
+}
+func (this *StreamFixture) Acknowledge(_ context.Context, stream, group string, ids ...string) error {
+	if this.acknowledgeError != nil {
+		return this.acknowledgeError
+	}
+	this.acknowledged = append(this.acknowledged, fmt.Sprintf("%s/%s/%v", stream, group, ids))
+	return nil
+}
+
+func (this *StreamFixture) Append(_ context.Context, _ []adapter.Record) error { panic("nop") }
+func (this *StreamFixture) DeclareGroup(_ context.Context, _, _, _ string) error {
+	panic("nop")
+}
+func (this *StreamFixture) Close() error { panic("nop") }
@@ -0,0 +1,144 @@
+package redisstream
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/redisstream/adapter"
+)
+
+// defaultWriter appends each dispatch to the stream keyed by its topic. All records of a single call to Write (or, for
+// a transactional writer, all records written prior to Commit) are appended within a single MULTI/EXEC transaction.
+type defaultWriter struct {
+	inner         adapter.Client
+	transactional bool
+	maxLength     int64
+	now           func() time.Time
+	logger        logger
+
+	mutex  sync.Mutex
+	buffer []adapter.Record
+}
+
+func newWriter(inner adapter.Client, transactional bool, config configuration) messaging.CommitWriter {
+	return &defaultWriter{
+		inner:         inner,
+		transactional: transactional,
+		maxLength:     config.MaxLength,
+		now:           config.Now,
+		logger:        config.Logger,
+	}
+}
+
+func (this *defaultWriter) Write(ctx context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	if len(dispatches) == 0 {
+		return 0, nil
+	}
+
+	now := this.now().UTC()
+	records := make([]adapter.Record, 0, len(dispatches))
+	for _, dispatch := range dispatches {
+		if len(dispatch.Topic) == 0 {
+			return 0, messaging.ErrEmptyDispatchTopic
+		}
+
+		records = append(records, this.toRecord(dispatch, now))
+	}
+
+	if this.transactional {
+		this.mutex.Lock()
+		defer this.mutex.Unlock()
+		this.buffer = append(this.buffer, records...)
+		return len(records), nil
+	}
+
+	if err := this.inner.Append(ctx, records); err != nil {
+		this.logger.Printf("[WARN] Unable to append entries to Redis stream [%s].", err)
+		return 0, err
+	}
+
+	return len(records), nil
+}
+func (this *defaultWriter) toRecord(dispatch messaging.Dispatch, now time.Time) adapter.Record {
+	if dispatch.Timestamp.IsZero() {
+		dispatch.Timestamp = now
+	}
+
+	values := make(map[string]any, 8+len(dispatch.Headers))
+	for key, value := range dispatch.Headers {
+		values[FieldHeaderPrefix+key] = formatField(value)
+	}
+
+	values[FieldPayload] = dispatch.Payload
+	values[FieldTimestamp] = dispatch.Timestamp.UTC().Format(time.RFC3339Nano)
+	setField(values, FieldSourceID, formatUint64(dispatch.SourceID))
+	setField(values, FieldMessageID, formatUint64(dispatch.MessageID))
+	setField(values, FieldCorrelationID, formatUint64(dispatch.CorrelationID))
+	setField(values, FieldMessageType, dispatch.MessageType)
+	setField(values, FieldContentType, dispatch.ContentType)
+	setField(values, FieldContentEncoding, dispatch.ContentEncoding)
+
+	return adapter.Record{Stream: dispatch.Topic, MaxLength: this.maxLength, Values: values}
+}
+func setField(values map[string]any, key, value string) {
+	if len(value) > 0 {
+		values[key] = value
+	}
+}
+func formatUint64(value uint64) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(value, 10)
+}
+func formatField(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func (this *defaultWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if len(this.buffer) == 0 {
+		return nil
+	}
+
+	err := this.inner.Append(context.Background(), this.buffer)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to append entries to Redis stream [%s].", err)
+	}
+
+	this.clearBuffer()
+	return err
+}
+func (this *defaultWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.clearBuffer()
+	return nil
+}
+func (this *defaultWriter) clearBuffer() {
+	for i := range this.buffer {
+		this.buffer[i] = adapter.Record{} // clear it out to avoid a memory leak
+	}
+
+	this.buffer = this.buffer[0:0]
+}
+
+// Close releases any buffered records; the underlying Redis client is shared and is closed by the connection.
+func (this *defaultWriter) Close() error {
+	return this.Rollback()
+}
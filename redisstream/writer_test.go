@@ -0,0 +1,146 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/redisstream/adapter"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	now    time.Time
+	config configuration
+	writer messaging.CommitWriter
+
+	appendCalls int
+	appended    []adapter.Record
+	appendError error
+}
+
+func (this *WriterFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	Options.apply(
+		Options.Connector(nil),
+		Options.MaxLength(1000),
+		Options.Now(func() time.Time { return this.now }),
+	)(&this.config)
+	this.writer = newWriter(this, false, this.config)
+}
+
+func (this *WriterFixture) TestWhenWritingNothing_Nop() {
+	count, err := this.writer.Write(this.ctx)
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.BeNil)
+	this.So(this.appendCalls, should.Equal, 0)
+}
+func (this *WriterFixture) TestWhenWritingDispatchWithoutTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+	this.So(this.appendCalls, should.Equal, 0)
+}
+func (this *WriterFixture) TestWhenWriting_AppendRecordsWithFieldsAndTrimming() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Topic:           "topic",
+		Headers:         map[string]any{"key": "value", "number": 42},
+		Payload:         []byte("payload"),
+	})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.appended, should.Equal, []adapter.Record{{
+		Stream:    "topic",
+		MaxLength: 1000,
+		Values: map[string]any{
+			FieldPayload:                 []byte("payload"),
+			FieldSourceID:                "1",
+			FieldMessageID:               "2",
+			FieldCorrelationID:           "3",
+			FieldMessageType:             "message-type",
+			FieldContentType:             "content-type",
+			FieldContentEncoding:         "content-encoding",
+			FieldTimestamp:               "2020-01-02T03:04:05.000000006Z",
+			FieldHeaderPrefix + "key":    "value",
+			FieldHeaderPrefix + "number": "42",
+		},
+	}})
+}
+func (this *WriterFixture) TestWhenWritingMultipleDispatches_AppendTogether() {
+	count, _ := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+
+	this.So(count, should.Equal, 2)
+	this.So(this.appendCalls, should.Equal, 1)
+	this.So(len(this.appended), should.Equal, 2)
+}
+func (this *WriterFixture) TestWhenAppendFails_ReturnError() {
+	this.appendError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.appendError)
+}
+func (this *WriterFixture) TestWhenWritingTransactionally_BufferUntilCommit() {
+	this.writer = newWriter(this, true, this.config)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "b"})
+
+	this.So(this.appendCalls, should.Equal, 0)
+
+	err := this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.appendCalls, should.Equal, 1)
+	this.So(len(this.appended), should.Equal, 2)
+}
+func (this *WriterFixture) TestWhenRollingBack_DiscardBufferedRecords() {
+	this.writer = newWriter(this, true, this.config)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+
+	err := this.writer.Rollback()
+	_ = this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.appendCalls, should.Equal, 0)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *WriterFixture) Append(_ context.Context, records []adapter.Record) error {
+	this.appendCalls++
+	this.appended = append(this.appended, records...)
+	return this.appendError
+}
+
+func (this *WriterFixture) DeclareGroup(_ context.Context, _, _, _ string) error { panic("nop") }
+func (this *WriterFixture) ReadGroup(_ context.Context, _, _ string, _ []string, _ int64, _ time.Duration) ([]adapter.Entry, error) {
+	panic("nop")
+}
+func (this *WriterFixture) AutoClaim(_ context.Context, _, _, _ string, _ time.Duration, _ string, _ int64) ([]adapter.Entry, string, error) {
+	panic("nop")
+}
+func (this *WriterFixture) Acknowledge(_ context.Context, _, _ string, _ ...string) error {
+	panic("nop")
+}
+func (this *WriterFixture) Close() error { panic("nop") }
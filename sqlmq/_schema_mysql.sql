@@ -6,3 +6,11 @@ CREATE TABLE Messages (
     PRIMARY KEY (id)
 );
 CREATE UNIQUE INDEX ix_messages_dispatched ON Messages (dispatched, id);
+
+CREATE TABLE Queue (
+    id      bigint unsigned AUTO_INCREMENT NOT NULL,
+    created datetime(3)                    NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    type    varchar(256)                   NOT NULL,
+    payload mediumblob                     NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -21,6 +21,10 @@ type configuration struct {
 	AutoincrementStride uint64
 	Now                 func() time.Time
 	Sleep               time.Duration
+	PollInterval        time.Duration
+	Notifier            *notifier
+	QueueTable          string
+	Broker              bool
 	Logger              logger
 	Monitor             monitor
 
@@ -28,6 +32,8 @@ type configuration struct {
 	Sender       messaging.Writer
 }
 
+// New returns a connector whose writers store dispatches in the Messages outbox table, along with the processor which
+// relays them to the transport specified. Streams read from the queue table, never the outbox.
 func New(transport messaging.Connector, options ...option) (messaging.Connector, messaging.ListenCloser) {
 	var config configuration
 	options = append(options, Options.TransportConnector(transport))
@@ -35,6 +41,16 @@ func New(transport messaging.Connector, options ...option) (messaging.Connector,
 	return newConnector(config), newDispatchProcessor(config)
 }
 
+// NewBroker returns a connector which uses the database as its only broker: writers store dispatches in the queue table
+// (rather than the Messages outbox table) from which streams read them, so there is neither a transport nor a dispatch
+// processor.
+func NewBroker(options ...option) messaging.Connector {
+	var config configuration
+	options = append(options, func(this *configuration) { this.Broker = true })
+	Options.apply(options...)(&config)
+	return newConnector(config)
+}
+
 var Options singleton
 
 type singleton struct{}
@@ -79,6 +95,23 @@ func (singleton) Now(value func() time.Time) option {
 func (singleton) RetryTimeout(value time.Duration) option {
 	return func(this *configuration) { this.Sleep = value }
 }
+func (singleton) PollInterval(value time.Duration) option {
+	return func(this *configuration) { this.PollInterval = value }
+}
+
+// QueueTable is the table read by streams without a StreamName and, with NewBroker, the table to which dispatches are
+// written. Unlike the Messages outbox table, rows are deleted from the queue table once acknowledged.
+//
+// Each stream selects up to BufferCapacity rows at a time (256 by default) within a transaction which holds the locks
+// on those rows until every row of the batch has been acknowledged, i.e. for as long as the entire batch takes to be
+// handled. BufferCapacity should therefore be small enough that a batch is handled well within the database's lock and
+// transaction timeouts (such as innodb_lock_wait_timeout and wait_timeout with MySQL).
+//
+// When the table read by a stream has a created column, its value is the Timestamp of each delivery, which requires the
+// driver to scan it as a time (with MySQL, by specifying parseTime=true); otherwise, the Timestamp is left unspecified.
+func (singleton) QueueTable(value string) option {
+	return func(this *configuration) { this.QueueTable = value }
+}
 func (singleton) MessageStore(value messageStore) option {
 	return func(this *configuration) { this.MessageStore = value }
 }
@@ -102,8 +135,22 @@ func (singleton) apply(options ...option) option {
 			this.StorageHandle = adapter.Open(this.DriverName, this.DataSource)
 		}
 
+		if !tableNamePattern.MatchString(this.QueueTable) {
+			panic(ErrInvalidStreamName)
+		}
+
+		table := outboxTableName
+		if this.Broker {
+			table = this.QueueTable
+			this.Channel = nil // nothing relays the dispatches written
+		}
+
+		if this.Notifier == nil {
+			this.Notifier = newNotifier()
+		}
+
 		if this.MessageStore == nil {
-			this.MessageStore = newMessageStore(this.StorageHandle, this.AutoincrementStride, this.Now)
+			this.MessageStore = newMessageStore(this.StorageHandle, table, this.AutoincrementStride, this.Now)
 		}
 
 		if this.Sender == nil {
@@ -119,6 +166,8 @@ func (singleton) defaults(options ...option) []option {
 	const defaultIsolationLevel = sql.LevelReadCommitted
 	const defaultRetryTimeout = time.Second * 5
 	const defaultAutoincrementStride = 1
+	const defaultPollInterval = time.Second
+	const defaultQueueTable = "Queue"
 
 	return append([]option{
 		Options.Context(defaultContext),
@@ -127,6 +176,8 @@ func (singleton) defaults(options ...option) []option {
 		Options.AutoincrementStride(defaultAutoincrementStride),
 		Options.Now(time.Now),
 		Options.RetryTimeout(defaultRetryTimeout),
+		Options.PollInterval(defaultPollInterval),
+		Options.QueueTable(defaultQueueTable),
 		Options.Logger(defaultLogger),
 		Options.Monitor(defaultMonitor),
 	}, options...)
@@ -1,10 +1,17 @@
 package sqlmq
 
 import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/smarty/gunit"
 	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqlmq/adapter"
 )
 
 func TestConfigFixture(t *testing.T) {
@@ -13,6 +20,18 @@ func TestConfigFixture(t *testing.T) {
 
 type ConfigFixture struct {
 	*gunit.Fixture
+
+	ctx        context.Context
+	mutex      sync.Mutex
+	statements []string
+	queued     []messaging.Dispatch // the rows of the queue table
+	affected   int64
+	sent       chan messaging.Dispatch
+}
+
+func (this *ConfigFixture) Setup() {
+	this.ctx = context.Background()
+	this.sent = make(chan messaging.Dispatch, 16)
 }
 
 func (this *ConfigFixture) TestPanicOnInvalidDriver() {
@@ -21,3 +40,120 @@ func (this *ConfigFixture) TestPanicOnInvalidDriver() {
 		Options.apply(Options.DataSource("", ""))(&config)
 	}, should.Panic)
 }
+func (this *ConfigFixture) TestPanicOnInvalidQueueTable() {
+	config := configuration{}
+	this.So(func() {
+		Options.apply(Options.StorageAdapter(this), Options.QueueTable("Queue; DROP TABLE Queue"))(&config)
+	}, should.Panic)
+}
+
+func (this *ConfigFixture) TestWhenRelayingOutboxAlongsideStream_StreamNeverReadsOrModifiesOutbox() {
+	connector, processor := New(nil, Options.StorageAdapter(this), Options.MessageSender(this), Options.PollInterval(time.Millisecond))
+	go processor.Listen()
+	defer func() { _ = processor.Close() }()
+	connection, _ := connector.Connect(this.ctx)
+	writer, _ := connection.Writer(this.ctx)
+	reader, _ := connection.Reader(this.ctx)
+	stream, streamErr := reader.Stream(this.ctx, messaging.StreamConfig{})
+	outbox, outboxErr := reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "Messages"})
+	this.queued = []messaging.Dispatch{{MessageID: 7, MessageType: "queued"}}
+
+	_, writeErr := writer.Write(this.ctx, messaging.Dispatch{MessageType: "outbox", Payload: []byte("payload")})
+	relayed := <-this.sent
+	var delivery messaging.Delivery
+	readErr := stream.Read(this.ctx, &delivery)
+	ackErr := stream.Acknowledge(this.ctx, delivery)
+
+	this.So(writeErr, should.BeNil)
+	this.So(streamErr, should.BeNil)
+	this.So(outbox, should.BeNil)
+	this.So(outboxErr, should.Equal, ErrOutboxStream)
+	this.So(relayed.MessageType, should.Equal, "outbox")
+	this.So(readErr, should.BeNil)
+	this.So(ackErr, should.BeNil)
+	this.So(delivery.MessageType, should.Equal, "queued")
+	for _, statement := range this.recorded() {
+		this.So(strings.Contains(statement, "Messages") && strings.Contains(statement, "Queue"), should.BeFalse)
+	}
+	this.So(this.recorded(), should.Contain, "INSERT INTO Messages (type, payload) VALUES (?,?);")
+	this.So(this.recorded(), should.Contain, "DELETE FROM Queue WHERE id IN (7);")
+}
+func (this *ConfigFixture) TestWhenUsingDatabaseAsBroker_WriteToQueueTableWithoutRelaying() {
+	connector := NewBroker(Options.StorageAdapter(this), Options.QueueTable("Jobs"), Options.ChannelBufferCapacity(1))
+	connection, _ := connector.Connect(this.ctx)
+	writer, _ := connection.Writer(this.ctx)
+	reader, _ := connection.Reader(this.ctx)
+
+	for range 3 { // more than the capacity of the channel, none of which are relayed
+		_, err := writer.Write(this.ctx, messaging.Dispatch{MessageType: "job"})
+		this.So(err, should.BeNil)
+	}
+	stream, err := reader.Stream(this.ctx, messaging.StreamConfig{})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).tableName, should.Equal, "Jobs")
+	this.So(this.recorded(), should.Equal, []string{
+		"INSERT INTO Jobs (type, payload) VALUES (?,?);",
+		"INSERT INTO Jobs (type, payload) VALUES (?,?);",
+		"INSERT INTO Jobs (type, payload) VALUES (?,?);",
+		"SELECT created FROM Jobs WHERE 1 = 0;",
+	})
+}
+
+func (this *ConfigFixture) recorded() []string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]string(nil), this.statements...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ConfigFixture) record(statement string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.statements = append(this.statements, statement)
+}
+
+func (this *ConfigFixture) BeginTx(context.Context, *sql.TxOptions) (adapter.Transaction, error) {
+	return this, nil
+}
+func (this *ConfigFixture) QueryContext(_ context.Context, statement string, _ ...any) (adapter.QueryResult, error) {
+	this.record(statement)
+	if !strings.Contains(statement, "FROM Queue") {
+		return &storageQueryResult{}, nil
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	result := &storageQueryResult{items: this.queued}
+	this.queued = nil
+	return result, nil
+}
+func (this *ConfigFixture) ExecContext(_ context.Context, statement string, _ ...any) (sql.Result, error) {
+	this.record(statement)
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.affected = int64(max(strings.Count(statement, "(?,?)"), 1))
+	return this, nil
+}
+func (this *ConfigFixture) LastInsertId() (int64, error) { return 1, nil }
+func (this *ConfigFixture) RowsAffected() (int64, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.affected, nil
+}
+func (this *ConfigFixture) Write(_ context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	for _, dispatch := range dispatches {
+		this.sent <- dispatch
+	}
+	return len(dispatches), nil
+}
+func (this *ConfigFixture) Commit() error   { return nil }
+func (this *ConfigFixture) Rollback() error { return nil }
+func (this *ConfigFixture) Close() error    { return nil }
+
+func (this *ConfigFixture) QueryRowContext(context.Context, string, ...any) adapter.RowScanner {
+	panic("nop")
+}
+func (this *ConfigFixture) DBHandle() *sql.DB { panic("nop") }
+func (this *ConfigFixture) TxHandle() *sql.Tx { panic("nop") }
@@ -21,10 +21,10 @@ func (this defaultConnector) Close() error {
 type defaultConnection struct{ config configuration }
 
 func (this defaultConnection) Reader(_ context.Context) (messaging.Reader, error) {
-	panic("not supported")
+	return newReader(this.config), nil
 }
 func (this defaultConnection) Writer(_ context.Context) (messaging.Writer, error) {
-	panic("not supported")
+	return newWriter(this.config), nil
 }
 func (this defaultConnection) CommitWriter(ctx context.Context) (messaging.CommitWriter, error) {
 	tx, err := this.config.StorageHandle.BeginTx(ctx, &this.config.SQLTxOptions)
@@ -61,19 +61,21 @@ func (this *ConnectorFixture) TestWhenClosingCreatedConnection_Nop() {
 	this.So(err, should.BeNil)
 }
 
-func (this *ConnectorFixture) TestWhenOpeningAReader_ItShouldPanic() {
-	connection, err := this.connector.Connect(this.ctx)
+func (this *ConnectorFixture) TestWhenOpeningAReader_ItShouldReturnNewReader() {
+	connection, _ := this.connector.Connect(this.ctx)
+
+	reader, err := connection.Reader(this.ctx)
 
-	this.So(connection, should.NotBeNil)
+	this.So(reader, should.HaveSameTypeAs, &defaultReader{})
 	this.So(err, should.BeNil)
-	this.So(func() { _, _ = connection.Reader(this.ctx) }, should.Panic)
 }
-func (this *ConnectorFixture) TestWhenOpeningARegularWriter_ItShouldPanic() {
-	connection, err := this.connector.Connect(this.ctx)
+func (this *ConnectorFixture) TestWhenOpeningARegularWriter_ItShouldReturnNewWriter() {
+	connection, _ := this.connector.Connect(this.ctx)
+
+	writer, err := connection.Writer(this.ctx)
 
-	this.So(connection, should.NotBeNil)
+	this.So(writer, should.HaveSameTypeAs, defaultWriter{})
 	this.So(err, should.BeNil)
-	this.So(func() { _, _ = connection.Writer(this.ctx) }, should.Panic)
 }
 
 func (this *ConnectorFixture) TestWhenOpeningCommitWriterAndNewTxFails_ItShouldReturnError() {
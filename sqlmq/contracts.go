@@ -3,6 +3,7 @@ package sqlmq
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/smarty/messaging/v3"
 	"github.com/smarty/messaging/v3/sqlmq/adapter"
@@ -29,3 +30,9 @@ type monitor interface {
 type logger interface {
 	Printf(format string, args ...any)
 }
+
+var (
+	ErrInvalidStreamName = errors.New("the stream name must be a valid table identifier")
+	ErrUnknownDelivery   = errors.New("the delivery is not outstanding on the stream and cannot be acknowledged")
+	ErrOutboxStream      = errors.New("the Messages outbox table is relayed by the dispatch processor and cannot be streamed")
+)
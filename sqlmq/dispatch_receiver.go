@@ -13,6 +13,7 @@ type dispatchReceiver struct {
 	tx      adapter.Transaction
 	output  chan messaging.Dispatch
 	store   messageStore
+	notify  *notifier
 	logger  logger
 	monitor monitor
 
@@ -25,6 +26,7 @@ func newDispatchReceiver(ctx context.Context, tx adapter.Transaction, config con
 		tx:      tx,
 		output:  config.Channel,
 		store:   config.MessageStore,
+		notify:  config.Notifier,
 		logger:  config.Logger,
 		monitor: config.Monitor,
 	}
@@ -38,6 +40,15 @@ func (this *dispatchReceiver) Write(_ context.Context, dispatches ...messaging.D
 }
 
 func (this *dispatchReceiver) Commit() error {
+	if err := this.persist(); err != nil {
+		return err
+	}
+
+	return this.relay()
+}
+
+// persist stores and commits the dispatches written such that, once it returns successfully, they are durable.
+func (this *dispatchReceiver) persist() error {
 	if err := this.store.Store(this.ctx, this.tx, this.buffer); err != nil {
 		this.logger.Printf("[WARN] Unable to persist messages to durable storage [%s].", err)
 		return err
@@ -52,6 +63,17 @@ func (this *dispatchReceiver) Commit() error {
 	}
 
 	this.monitor.MessageStored(len(this.buffer))
+	this.notify.Notify()
+	return nil
+}
+
+// relay passes the committed dispatches to the dispatch processor, if any, unless the context is cancelled first, in
+// which case the dispatches not yet relayed remain pending in durable storage.
+func (this *dispatchReceiver) relay() error {
+	if this.output == nil {
+		return nil // the dispatches are consumed from the queue table rather than relayed by the dispatch processor
+	}
+
 	for _, dispatch := range this.buffer {
 		select {
 		case this.output <- dispatch:
@@ -14,13 +14,14 @@ import (
 
 type dispatchStore struct {
 	db               adapter.ReadWriter
+	table            string
 	stride           uint64
 	now              func() time.Time
 	confirmStatement *strings.Builder
 }
 
-func newMessageStore(db adapter.ReadWriter, stride uint64, now func() time.Time) messageStore {
-	return dispatchStore{db: db, stride: stride, now: now, confirmStatement: &strings.Builder{}}
+func newMessageStore(db adapter.ReadWriter, table string, stride uint64, now func() time.Time) messageStore {
+	return dispatchStore{db: db, table: table, stride: stride, now: now, confirmStatement: &strings.Builder{}}
 }
 
 func (this dispatchStore) Store(ctx context.Context, writer adapter.Writer, dispatches []messaging.Dispatch) error {
@@ -55,7 +56,7 @@ func (this dispatchStore) buildExecArgs(dispatches []messaging.Dispatch) (string
 	builder := &strings.Builder{}
 	args := make([]any, 0, len(dispatches)*2)
 
-	_, _ = builder.WriteString("INSERT INTO Messages (type, payload) VALUES ")
+	_, _ = fmt.Fprintf(builder, "INSERT INTO %s (type, payload) VALUES ", this.table)
 	for i, dispatch := range dispatches {
 		args = append(args, dispatch.MessageType, dispatch.Payload)
 		if i == len(dispatches)-1 {
@@ -69,7 +70,7 @@ func (this dispatchStore) buildExecArgs(dispatches []messaging.Dispatch) (string
 }
 
 func (this dispatchStore) Load(ctx context.Context, id uint64) (results []messaging.Dispatch, err error) {
-	statement := fmt.Sprintf("SELECT id, type, payload FROM Messages WHERE dispatched IS NULL AND id > %d;", id)
+	statement := fmt.Sprintf("SELECT id, type, payload FROM %s WHERE dispatched IS NULL AND id > %d;", this.table, id)
 	rows, err := this.db.QueryContext(ctx, statement)
 	if err != nil {
 		return nil, err
@@ -107,8 +108,8 @@ func (this dispatchStore) Confirm(ctx context.Context, dispatches []messaging.Di
 	}
 
 	now := this.now().UTC().Format("2006-01-02 15:04:05.000000")
-	const statementFormat = "UPDATE Messages SET dispatched = '%s' WHERE dispatched IS NULL AND id IN (%s);"
-	statement := fmt.Sprintf(statementFormat, now, this.confirmStatement.String())
+	const statementFormat = "UPDATE %s SET dispatched = '%s' WHERE dispatched IS NULL AND id IN (%s);"
+	statement := fmt.Sprintf(statementFormat, this.table, now, this.confirmStatement.String())
 	_, err := this.db.ExecContext(ctx, statement)
 	return err
 }
@@ -43,7 +43,7 @@ type DispatchStoreFixture struct {
 func (this *DispatchStoreFixture) Setup() {
 	this.now = time.Now().UTC()
 	this.ctx = context.Background()
-	this.store = newMessageStore(this, 7, func() time.Time { return this.now })
+	this.store = newMessageStore(this, outboxTableName, 7, func() time.Time { return this.now })
 }
 
 func (this *DispatchStoreFixture) TestWhenNoDispatchesToWrite_DoNotPerformWriteOperation() {
@@ -221,6 +221,8 @@ func (this *storageQueryResult) Scan(fields ...any) error {
 			*(fields[i].(*string)) = item.MessageType
 		case 2:
 			*(fields[i].(*[]byte)) = item.Payload
+		case 3:
+			*(fields[i].(*sql.NullTime)) = sql.NullTime{Time: item.Timestamp, Valid: !item.Timestamp.IsZero()}
 		default:
 			panic("bad scan")
 		}
@@ -0,0 +1,89 @@
+package sqlmq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+)
+
+type defaultReader struct {
+	config  configuration
+	streams []io.Closer
+	mutex   sync.Mutex
+	logger  logger
+}
+
+func newReader(config configuration) messaging.Reader {
+	return &defaultReader{config: config, logger: config.Logger}
+}
+
+func (this *defaultReader) Stream(ctx context.Context, settings messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	tableName := settings.StreamName
+	if len(tableName) == 0 {
+		tableName = this.config.QueueTable
+	}
+
+	if !tableNamePattern.MatchString(tableName) {
+		this.logger.Printf("[WARN] Unable to open stream, the table name [%s] is not a valid identifier.", tableName)
+		return nil, ErrInvalidStreamName
+	}
+
+	if isOutboxTable(tableName) {
+		this.logger.Printf("[WARN] Unable to open stream, the table [%s] is the outbox relayed by the dispatch processor.", tableName)
+		return nil, ErrOutboxStream
+	}
+
+	created := this.hasCreatedColumn(ctx, tableName)
+	this.logger.Printf("[INFO] Stream opened for table [%s], polling for messages...", tableName)
+	stream := newStream(tableName, created, settings, this.config)
+	this.streams = append(this.streams, stream)
+	return stream, nil
+}
+
+// hasCreatedColumn indicates whether the table has a created column containing the time at which each row was written,
+// which is otherwise unknown, in which case the Timestamp of each delivery is left unspecified.
+func (this *defaultReader) hasCreatedColumn(ctx context.Context, tableName string) bool {
+	statement := fmt.Sprintf("SELECT %s FROM %s WHERE 1 = 0;", createdColumn, tableName)
+	rows, err := this.config.StorageHandle.QueryContext(ctx, statement)
+	if err != nil {
+		this.logger.Printf("[INFO] Unable to select column [%s] of table [%s], delivery timestamps are unspecified: %s", createdColumn, tableName, err)
+		return false
+	}
+
+	closeResource(rows)
+	return true
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
+
+// isOutboxTable indicates whether the table is the Messages outbox table, possibly qualified by its schema.
+func isOutboxTable(tableName string) bool {
+	return strings.EqualFold(tableName[strings.LastIndex(tableName, ".")+1:], outboxTableName)
+}
+
+const (
+	outboxTableName             = "Messages"
+	createdColumn               = "created"
+	defaultStreamBufferCapacity = 256
+)
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
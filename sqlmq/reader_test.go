@@ -0,0 +1,80 @@
+package sqlmq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+)
+
+func TestReaderFixture(t *testing.T) {
+	gunit.Run(new(ReaderFixture), t)
+}
+
+type ReaderFixture struct {
+	*gunit.Fixture
+
+	ctx     context.Context
+	storage *StreamFixture
+	reader  messaging.Reader
+}
+
+func (this *ReaderFixture) Setup() {
+	this.ctx = context.Background()
+	config := configuration{}
+	this.storage = &StreamFixture{}
+	Options.apply(Options.StorageAdapter(this.storage))(&config)
+	this.reader = newReader(config)
+}
+
+func (this *ReaderFixture) TestWhenOpeningStream_UseStreamNameAsTableName() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "schema.Queue"})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).tableName, should.Equal, "schema.Queue")
+}
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutName_UseQueueTable() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).tableName, should.Equal, "Queue")
+}
+func (this *ReaderFixture) TestWhenOpeningStream_SelectCreatedColumnToDetermineWhetherTimestampsAreStored() {
+	stream, _ := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "schema.Queue"})
+
+	this.So(stream.(*defaultStream).created, should.BeTrue)
+	this.So(this.storage.queryStatements, should.Equal, []string{"SELECT created FROM schema.Queue WHERE 1 = 0;"})
+}
+func (this *ReaderFixture) TestWhenTableHasNoCreatedColumn_OpenStreamWithoutTimestamps() {
+	this.storage.queryError = errors.New("unknown column")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{})
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).created, should.BeFalse)
+}
+func (this *ReaderFixture) TestWhenOpeningStreamOfOutboxTable_ReturnError() {
+	for _, name := range []string{"Messages", "schema.messages"} {
+		stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: name})
+
+		this.So(stream, should.BeNil)
+		this.So(err, should.Equal, ErrOutboxStream)
+	}
+}
+func (this *ReaderFixture) TestWhenStreamNameIsNotAValidIdentifier_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "Messages; DROP TABLE Messages"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrInvalidStreamName)
+}
+func (this *ReaderFixture) TestWhenClosed_StreamsAreClosed() {
+	stream, _ := this.reader.Stream(this.ctx, messaging.StreamConfig{})
+
+	err := this.reader.Close()
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).closed.Err(), should.NotBeNil)
+}
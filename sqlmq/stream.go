@@ -0,0 +1,294 @@
+package sqlmq
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqlmq/adapter"
+)
+
+// defaultStream consumes the rows of a queue table. Each batch of rows is selected and locked inside of its own
+// transaction (FOR UPDATE SKIP LOCKED) such that concurrent consumers never observe the same row. Rows are deleted
+// when acknowledged and the transaction is committed once every row in the batch is acknowledged, so the locks on the
+// rows of a batch are held for as long as the batch takes to be handled (see QueueTable). Any rows which remain
+// unacknowledged when the stream is closed are unlocked and will be delivered again.
+type defaultStream struct {
+	ctx       context.Context // transactions are not bound to the lifetime of the stream, see Close
+	closed    context.Context
+	shutdown  context.CancelFunc
+	handle    adapter.Handle
+	txOptions sql.TxOptions
+	tableName string
+	selection string
+	topics    []any
+	interval  time.Duration
+	notifier  *notifier
+	created   bool
+	logger    logger
+
+	mutex    sync.Mutex
+	buffer   []messaging.Delivery
+	inflight map[uint64]*deliveryBatch
+}
+type deliveryBatch struct {
+	tx          adapter.Transaction
+	outstanding int
+}
+
+// newStream reads the table specified which, when created is specified, has a created column from which the Timestamp
+// of each delivery is read.
+func newStream(tableName string, created bool, settings messaging.StreamConfig, config configuration) messaging.Stream {
+	closed, shutdown := context.WithCancel(config.Context)
+	topics := make([]any, 0, len(settings.Topics))
+	for _, topic := range settings.Topics {
+		topics = append(topics, topic)
+	}
+
+	return &defaultStream{
+		ctx:       config.Context,
+		closed:    closed,
+		shutdown:  shutdown,
+		handle:    config.StorageHandle,
+		txOptions: config.SQLTxOptions,
+		tableName: tableName,
+		selection: buildSelectStatement(tableName, created, len(topics), settings.BufferCapacity),
+		topics:    topics,
+		interval:  config.PollInterval,
+		notifier:  config.Notifier,
+		created:   created,
+		logger:    config.Logger,
+		inflight:  make(map[uint64]*deliveryBatch),
+	}
+}
+func buildSelectStatement(tableName string, created bool, topics int, capacity uint16) string {
+	builder := &strings.Builder{}
+	_, _ = builder.WriteString("SELECT id, type, payload")
+	if created {
+		_, _ = fmt.Fprintf(builder, ", %s", createdColumn)
+	}
+	_, _ = fmt.Fprintf(builder, " FROM %s", tableName)
+
+	if topics > 0 {
+		_, _ = builder.WriteString(" WHERE type IN (")
+		_, _ = builder.WriteString(strings.TrimSuffix(strings.Repeat("?,", topics), ","))
+		_, _ = builder.WriteString(")")
+	}
+
+	if capacity == 0 {
+		capacity = defaultStreamBufferCapacity
+	}
+
+	_, _ = fmt.Fprintf(builder, " ORDER BY id LIMIT %d FOR UPDATE SKIP LOCKED;", capacity)
+	return builder.String()
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	for {
+		changed := this.notifier.Changed()
+
+		if received, err := this.tryRead(target); err != nil {
+			return err
+		} else if received {
+			return nil
+		}
+
+		if err := this.wait(ctx, changed); err != nil {
+			return err
+		}
+	}
+}
+func (this *defaultStream) tryRead(target *messaging.Delivery) (bool, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed.Err() != nil {
+		return false, io.EOF
+	}
+
+	if len(this.buffer) == 0 {
+		if err := this.fill(); err != nil {
+			this.logger.Printf("[WARN] Unable to read messages from table [%s]: %s", this.tableName, err)
+			return false, err
+		}
+	}
+
+	if len(this.buffer) == 0 {
+		return false, nil
+	}
+
+	*target, this.buffer[0] = this.buffer[0], messaging.Delivery{}
+	this.buffer = this.buffer[1:]
+	return true, nil
+}
+func (this *defaultStream) fill() error {
+	tx, err := this.handle.BeginTx(this.ctx, &this.txOptions)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := this.query(tx)
+	if err != nil || len(deliveries) == 0 {
+		_ = tx.Rollback()
+		return err
+	}
+
+	batch := &deliveryBatch{tx: tx, outstanding: len(deliveries)}
+	for _, delivery := range deliveries {
+		this.inflight[delivery.DeliveryID] = batch
+	}
+
+	this.buffer = append(this.buffer, deliveries...)
+	return nil
+}
+func (this *defaultStream) query(tx adapter.Transaction) (results []messaging.Delivery, err error) {
+	rows, err := tx.QueryContext(this.ctx, this.selection, this.topics...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResource(rows)
+
+	for rows.Next() {
+		var delivery messaging.Delivery
+		var created sql.NullTime
+		fields := []any{&delivery.MessageID, &delivery.MessageType, &delivery.Payload}
+		if this.created {
+			fields = append(fields, &created)
+		}
+
+		if err = rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+
+		if created.Valid {
+			delivery.Timestamp = created.Time.UTC()
+		}
+
+		delivery.DeliveryID = delivery.MessageID
+		delivery.Durable = true
+		delivery.Topic = this.tableName
+		results = append(results, delivery)
+	}
+
+	return results, rows.Err()
+}
+func (this *defaultStream) wait(ctx context.Context, changed <-chan struct{}) error {
+	timer := time.NewTimer(this.interval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-changed:
+		return nil
+	case <-this.closed.Done():
+		return io.EOF
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messaging.Delivery) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	batches := make(map[*deliveryBatch][]uint64)
+	var ordered []*deliveryBatch
+	for _, delivery := range deliveries {
+		batch, contains := this.inflight[delivery.DeliveryID]
+		if !contains {
+			return ErrUnknownDelivery
+		}
+
+		if _, contains = batches[batch]; !contains {
+			ordered = append(ordered, batch)
+		}
+		batches[batch] = append(batches[batch], delivery.DeliveryID)
+	}
+
+	for _, batch := range ordered {
+		if err := this.acknowledge(batch, batches[batch]); err != nil {
+			this.logger.Printf("[WARN] Unable to delete messages acknowledged from table [%s]: %s", this.tableName, err)
+			this.abandon(batch)
+			return err
+		}
+	}
+
+	return nil
+}
+func (this *defaultStream) acknowledge(batch *deliveryBatch, ids []uint64) error {
+	builder := &strings.Builder{}
+	for i, id := range ids {
+		if i > 0 {
+			_, _ = builder.WriteString(", ")
+		}
+		_, _ = fmt.Fprintf(builder, "%d", id)
+	}
+
+	statement := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s);", this.tableName, builder.String())
+	if _, err := batch.tx.ExecContext(this.ctx, statement); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		delete(this.inflight, id)
+	}
+
+	batch.outstanding -= len(ids)
+	if batch.outstanding > 0 {
+		return nil
+	}
+
+	return batch.tx.Commit()
+}
+
+// abandon rolls back the batch such that all of its rows, acknowledged or not, become available for redelivery.
+func (this *defaultStream) abandon(batch *deliveryBatch) {
+	_ = batch.tx.Rollback()
+
+	for id, item := range this.inflight {
+		if item == batch {
+			delete(this.inflight, id)
+		}
+	}
+
+	pending := this.buffer[0:0]
+	for _, delivery := range this.buffer {
+		if _, contains := this.inflight[delivery.DeliveryID]; contains {
+			pending = append(pending, delivery)
+		}
+	}
+	this.buffer = pending
+}
+
+func (this *defaultStream) Close() error {
+	this.shutdown()
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	committed := make(map[*deliveryBatch]struct{}, len(this.inflight))
+	for _, batch := range this.inflight {
+		if _, contains := committed[batch]; contains {
+			continue
+		}
+
+		committed[batch] = struct{}{}
+		_ = batch.tx.Commit() // deletes acknowledged rows and releases the locks on the remainder
+	}
+
+	this.inflight = make(map[uint64]*deliveryBatch)
+	this.buffer = nil
+	return nil
+}
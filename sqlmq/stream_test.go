@@ -0,0 +1,290 @@
+package sqlmq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqlmq/adapter"
+)
+
+func TestStreamFixture(t *testing.T) {
+	gunit.Run(new(StreamFixture), t)
+}
+
+type StreamFixture struct {
+	*gunit.Fixture
+
+	now    time.Time
+	ctx    context.Context
+	config configuration
+	stream messaging.Stream
+
+	beginCount   int
+	beginOptions sql.TxOptions
+	beginError   error
+
+	queryStatements []string
+	queryArgs       [][]any
+	queryResults    []*storageQueryResult
+	queryError      error
+
+	execStatements []string
+	execError      error
+
+	commitCount   int
+	rollbackCount int
+}
+
+func (this *StreamFixture) Setup() {
+	this.now = time.Date(2020, 01, 02, 12, 30, 15, 0, time.UTC)
+	this.ctx = context.Background()
+	Options.apply(
+		Options.StorageAdapter(this),
+		Options.IsolationLevel(sql.LevelReadCommitted),
+		Options.PollInterval(time.Millisecond),
+	)(&this.config)
+	this.initializeStream(messaging.StreamConfig{StreamName: "Queue", BufferCapacity: 16, Topics: []string{"a", "b"}})
+}
+func (this *StreamFixture) initializeStream(settings messaging.StreamConfig) {
+	this.stream = newStream(settings.StreamName, true, settings, this.config)
+}
+
+func (this *StreamFixture) TestWhenReading_SelectAndLockRowsInNewTransaction() {
+	this.queryResults = append(this.queryResults, &storageQueryResult{items: []messaging.Dispatch{
+		{MessageID: 42, MessageType: "a", Payload: []byte("payload"), Timestamp: this.now},
+	}})
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery, should.Equal, messaging.Delivery{
+		DeliveryID:  42,
+		MessageID:   42,
+		Timestamp:   this.now,
+		Durable:     true,
+		Topic:       "Queue",
+		MessageType: "a",
+		Payload:     []byte("payload"),
+	})
+	this.So(this.beginCount, should.Equal, 1)
+	this.So(this.beginOptions, should.Equal, sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	this.So(this.queryStatements, should.Equal, []string{
+		"SELECT id, type, payload, created FROM Queue WHERE type IN (?,?) ORDER BY id LIMIT 16 FOR UPDATE SKIP LOCKED;",
+	})
+	this.So(this.queryArgs, should.Equal, [][]any{{"a", "b"}})
+	this.So(this.commitCount+this.rollbackCount, should.Equal, 0)
+}
+func (this *StreamFixture) TestWhenNoTopicsOrCapacitySpecified_SelectAllTypesUsingDefaultLimit() {
+	this.initializeStream(messaging.StreamConfig{StreamName: "Queue"})
+	this.queryResults = append(this.queryResults, &storageQueryResult{items: []messaging.Dispatch{{MessageID: 1}}})
+
+	var delivery messaging.Delivery
+	_ = this.stream.Read(this.ctx, &delivery)
+
+	this.So(this.queryStatements, should.Equal, []string{
+		"SELECT id, type, payload, created FROM Queue ORDER BY id LIMIT 256 FOR UPDATE SKIP LOCKED;",
+	})
+}
+func (this *StreamFixture) TestWhenTableHasNoCreatedColumn_LeaveTimestampUnspecified() {
+	this.stream = newStream("Queue", false, messaging.StreamConfig{}, this.config)
+	this.queryResults = append(this.queryResults, &storageQueryResult{items: []messaging.Dispatch{{MessageID: 1}}})
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery.MessageID, should.Equal, 1)
+	this.So(delivery.Timestamp, should.BeZeroValue)
+	this.So(this.queryStatements, should.Equal, []string{
+		"SELECT id, type, payload FROM Queue ORDER BY id LIMIT 256 FOR UPDATE SKIP LOCKED;",
+	})
+}
+func (this *StreamFixture) TestWhenNoRowsAvailable_RollbackAndPollAgain() {
+	this.queryResults = append(this.queryResults,
+		&storageQueryResult{},
+		&storageQueryResult{items: []messaging.Dispatch{{MessageID: 7}}},
+	)
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery.MessageID, should.Equal, 7)
+	this.So(this.beginCount, should.Equal, 2)
+	this.So(this.rollbackCount, should.Equal, 1)
+}
+func (this *StreamFixture) TestWhenMultipleRowsSelected_SubsequentReadsServedFromBuffer() {
+	this.queryResults = append(this.queryResults, &storageQueryResult{items: []messaging.Dispatch{{MessageID: 1}, {MessageID: 2}}})
+
+	var first, second messaging.Delivery
+	_ = this.stream.Read(this.ctx, &first)
+	_ = this.stream.Read(this.ctx, &second)
+
+	this.So(first.MessageID, should.Equal, 1)
+	this.So(second.MessageID, should.Equal, 2)
+	this.So(this.beginCount, should.Equal, 1)
+}
+func (this *StreamFixture) TestWhenWaitingForRows_NotifierWakesStream() {
+	this.config.PollInterval = time.Hour
+	this.initializeStream(messaging.StreamConfig{StreamName: "Queue"})
+	this.queryResults = append(this.queryResults,
+		&storageQueryResult{},
+		&storageQueryResult{items: []messaging.Dispatch{{MessageID: 7}}},
+	)
+
+	go func() {
+		time.Sleep(time.Millisecond * 5)
+		this.config.Notifier.Notify()
+	}()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(delivery.MessageID, should.Equal, 7)
+}
+func (this *StreamFixture) TestWhenBeginFails_ReturnError() {
+	this.beginError = errors.New("")
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, this.beginError)
+}
+func (this *StreamFixture) TestWhenQueryFails_RollbackAndReturnError() {
+	this.queryError = errors.New("")
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, this.queryError)
+	this.So(this.rollbackCount, should.Equal, 1)
+}
+func (this *StreamFixture) TestWhenContextCancelledWhileWaiting_ReturnError() {
+	ctx, cancel := context.WithCancel(this.ctx)
+	cancel()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(ctx, &delivery)
+
+	this.So(err, should.Equal, context.Canceled)
+}
+func (this *StreamFixture) TestWhenReadingFromClosedStream_ReturnEOF() {
+	_ = this.stream.Close()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+	this.So(this.beginCount, should.Equal, 0)
+}
+func (this *StreamFixture) TestWhenAcknowledgingPartOfBatch_DeleteRowsWithoutCommitting() {
+	this.queryResults = append(this.queryResults, &storageQueryResult{items: []messaging.Dispatch{{MessageID: 1}, {MessageID: 2}, {MessageID: 3}}})
+	first, second := this.read(), this.read()
+
+	err := this.stream.Acknowledge(this.ctx, first, second)
+
+	this.So(err, should.BeNil)
+	this.So(this.execStatements, should.Equal, []string{
+		"DELETE FROM Queue WHERE id IN (1, 2);",
+	})
+	this.So(this.commitCount, should.Equal, 0)
+}
+func (this *StreamFixture) TestWhenAcknowledgingEntireBatch_CommitTransaction() {
+	this.queryResults = append(this.queryResults, &storageQueryResult{items: []messaging.Dispatch{{MessageID: 1}, {MessageID: 2}}})
+	first, second := this.read(), this.read()
+
+	_ = this.stream.Acknowledge(this.ctx, first)
+	err := this.stream.Acknowledge(this.ctx, second)
+
+	this.So(err, should.BeNil)
+	this.So(len(this.execStatements), should.Equal, 2)
+	this.So(this.commitCount, should.Equal, 1)
+}
+func (this *StreamFixture) TestWhenAcknowledgingUnknownDelivery_ReturnError() {
+	err := this.stream.Acknowledge(this.ctx, messaging.Delivery{DeliveryID: 1})
+
+	this.So(err, should.Equal, ErrUnknownDelivery)
+	this.So(this.execStatements, should.BeEmpty)
+}
+func (this *StreamFixture) TestWhenAcknowledgementFails_RollbackBatchAndDiscardBufferedDeliveries() {
+	this.queryResults = append(this.queryResults,
+		&storageQueryResult{items: []messaging.Dispatch{{MessageID: 1}, {MessageID: 2}}},
+		&storageQueryResult{items: []messaging.Dispatch{{MessageID: 3}}},
+	)
+	first := this.read()
+	this.execError = errors.New("")
+
+	err := this.stream.Acknowledge(this.ctx, first)
+
+	this.So(err, should.Equal, this.execError)
+	this.So(this.rollbackCount, should.Equal, 1)
+	this.So(this.read().MessageID, should.Equal, 3)
+}
+func (this *StreamFixture) TestWhenClosed_CommitOutstandingBatchesToReleaseLocks() {
+	this.queryResults = append(this.queryResults, &storageQueryResult{items: []messaging.Dispatch{{MessageID: 1}, {MessageID: 2}}})
+	_ = this.read()
+
+	err := this.stream.Close()
+
+	this.So(err, should.BeNil)
+	this.So(this.commitCount, should.Equal, 1)
+}
+
+func (this *StreamFixture) read() (delivery messaging.Delivery) {
+	this.So(this.stream.Read(this.ctx, &delivery), should.BeNil)
+	return delivery
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *StreamFixture) BeginTx(_ context.Context, options *sql.TxOptions) (adapter.Transaction, error) {
+	this.beginCount++
+	this.beginOptions = *options
+	if this.beginError != nil {
+		return nil, this.beginError
+	}
+	return this, nil
+}
+func (this *StreamFixture) QueryContext(_ context.Context, statement string, args ...any) (adapter.QueryResult, error) {
+	this.queryStatements = append(this.queryStatements, statement)
+	this.queryArgs = append(this.queryArgs, args)
+	if this.queryError != nil {
+		return nil, this.queryError
+	}
+
+	if len(this.queryResults) == 0 {
+		return &storageQueryResult{}, nil
+	}
+
+	result := this.queryResults[0]
+	this.queryResults = this.queryResults[1:]
+	return result, nil
+}
+func (this *StreamFixture) ExecContext(_ context.Context, statement string, _ ...any) (sql.Result, error) {
+	this.execStatements = append(this.execStatements, statement)
+	return nil, this.execError
+}
+func (this *StreamFixture) Commit() error {
+	this.commitCount++
+	return nil
+}
+func (this *StreamFixture) Rollback() error {
+	this.rollbackCount++
+	return nil
+}
+
+func (this *StreamFixture) QueryRowContext(_ context.Context, _ string, _ ...any) adapter.RowScanner {
+	panic("nop")
+}
+func (this *StreamFixture) Close() error      { panic("nop") }
+func (this *StreamFixture) DBHandle() *sql.DB { panic("nop") }
+func (this *StreamFixture) TxHandle() *sql.Tx { panic("nop") }
@@ -0,0 +1,55 @@
+package sqlmq
+
+import (
+	"context"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqlmq/adapter"
+)
+
+// defaultWriter stores each batch of dispatches in its own short-lived transaction which is committed before Write
+// returns, after which the dispatches are handed to the dispatch processor exactly as with a committed CommitWriter.
+// Once committed, the dispatches are reported as written even if the context is cancelled before all of them have been
+// handed to the dispatch processor, as writing them again would store duplicates. Unlike the dispatchReceiver, it can
+// be reused for any number of calls to Write.
+type defaultWriter struct {
+	handle adapter.Handle
+	config configuration
+	logger logger
+}
+
+func newWriter(config configuration) messaging.Writer {
+	return defaultWriter{handle: config.StorageHandle, config: config, logger: config.Logger}
+}
+
+func (this defaultWriter) Write(ctx context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	if len(dispatches) == 0 {
+		return 0, nil
+	}
+
+	tx, err := this.handle.BeginTx(ctx, &this.config.SQLTxOptions)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to begin new storage transaction [%s].", err)
+		return 0, err
+	}
+
+	receiver := newDispatchReceiver(ctx, tx, this.config).(*dispatchReceiver)
+	if _, err = receiver.Write(ctx, dispatches...); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if err = receiver.persist(); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	// the dispatches are durable once committed, so a failure to relay them must not cause them to be written again
+	if err = receiver.relay(); err != nil {
+		this.logger.Printf("[WARN] Unable to relay messages committed to durable storage [%s]; they remain pending in storage.", err)
+	}
+
+	return len(dispatches), nil
+}
+
+func (this defaultWriter) Close() error { return nil }
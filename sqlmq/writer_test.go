@@ -0,0 +1,168 @@
+package sqlmq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqlmq/adapter"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	ctx     context.Context
+	channel chan messaging.Dispatch
+	writer  messaging.Writer
+
+	beginCount   int
+	beginContext context.Context
+	beginError   error
+
+	storeTx     adapter.Writer
+	storeWrites []messaging.Dispatch
+	storeError  error
+
+	commitCount   int
+	commitError   error
+	rollbackCount int
+}
+
+func (this *WriterFixture) Setup() {
+	this.ctx = context.Background()
+	this.channel = make(chan messaging.Dispatch, 16)
+	config := configuration{}
+	Options.apply(
+		Options.StorageAdapter(this),
+		Options.Channel(this.channel),
+		Options.MessageStore(this),
+	)(&config)
+	this.writer = newWriter(config)
+}
+
+func (this *WriterFixture) TestWhenWritingNothing_NoTransactionStarted() {
+	count, err := this.writer.Write(this.ctx)
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.BeNil)
+	this.So(this.beginCount, should.Equal, 0)
+}
+func (this *WriterFixture) TestWhenWriting_StoreAndCommitInOwnTransactionThenSendToOutputChannel() {
+	writes := []messaging.Dispatch{{MessageType: "1"}, {MessageType: "2"}}
+
+	count, err := this.writer.Write(this.ctx, writes...)
+
+	this.So(count, should.Equal, 2)
+	this.So(err, should.BeNil)
+	this.So(this.beginContext, should.Equal, this.ctx)
+	this.So(this.storeTx, should.Equal, this)
+	this.So(this.storeWrites, should.Equal, writes)
+	this.So(this.commitCount, should.Equal, 1)
+	this.So(this.rollbackCount, should.Equal, 0)
+	this.So(len(this.channel), should.Equal, 2)
+}
+func (this *WriterFixture) TestWhenWritingRepeatedly_EachWriteUsesNewTransaction() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{})
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(this.beginCount, should.Equal, 2)
+	this.So(this.commitCount, should.Equal, 2)
+}
+func (this *WriterFixture) TestWhenBeginFails_ReturnError() {
+	this.beginError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.beginError)
+}
+func (this *WriterFixture) TestWhenStoreFails_RollbackAndReturnError() {
+	this.storeError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.storeError)
+	this.So(this.commitCount, should.Equal, 0)
+	this.So(this.rollbackCount, should.Equal, 1)
+	this.So(len(this.channel), should.Equal, 0)
+}
+func (this *WriterFixture) TestWhenCommitFails_ReturnError() {
+	this.commitError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.commitError)
+	this.So(len(this.channel), should.Equal, 0)
+}
+func (this *WriterFixture) TestWhenContextCancelledWhileRelayingCommittedDispatches_ReportWrittenWithoutRollback() {
+	for len(this.channel) < cap(this.channel) {
+		this.channel <- messaging.Dispatch{} // the channel is full, so the committed dispatch cannot be relayed
+	}
+	ctx, cancel := context.WithCancel(this.ctx)
+	cancel()
+
+	count, err := this.writer.Write(ctx, messaging.Dispatch{MessageType: "1"})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.commitCount, should.Equal, 1)
+	this.So(this.rollbackCount, should.Equal, 0)
+	this.So(len(this.channel), should.Equal, cap(this.channel))
+}
+func (this *WriterFixture) TestWhenClosing_Nop() {
+	this.So(this.writer.Close(), should.BeNil)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *WriterFixture) BeginTx(ctx context.Context, _ *sql.TxOptions) (adapter.Transaction, error) {
+	this.beginCount++
+	this.beginContext = ctx
+	if this.beginError != nil {
+		return nil, this.beginError
+	}
+	return this, nil
+}
+func (this *WriterFixture) Commit() error {
+	this.commitCount++
+	return this.commitError
+}
+func (this *WriterFixture) Rollback() error {
+	this.rollbackCount++
+	return nil
+}
+
+func (this *WriterFixture) Store(_ context.Context, writer adapter.Writer, dispatches []messaging.Dispatch) error {
+	this.storeTx = writer
+	this.storeWrites = dispatches
+	return this.storeError
+}
+func (this *WriterFixture) Load(_ context.Context, _ uint64) ([]messaging.Dispatch, error) {
+	panic("nop")
+}
+func (this *WriterFixture) Confirm(_ context.Context, _ []messaging.Dispatch) error {
+	panic("nop")
+}
+
+func (this *WriterFixture) QueryContext(_ context.Context, _ string, _ ...any) (adapter.QueryResult, error) {
+	panic("nop")
+}
+func (this *WriterFixture) QueryRowContext(_ context.Context, _ string, _ ...any) adapter.RowScanner {
+	panic("nop")
+}
+func (this *WriterFixture) ExecContext(_ context.Context, _ string, _ ...any) (sql.Result, error) {
+	panic("nop")
+}
+func (this *WriterFixture) Close() error      { panic("nop") }
+func (this *WriterFixture) DBHandle() *sql.DB { panic("nop") }
+func (this *WriterFixture) TxHandle() *sql.Tx { panic("nop") }
@@ -0,0 +1,334 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+type awsConnector struct{}
+
+func (this awsConnector) Connect(ctx context.Context, config Config) (Client, error) {
+	var options []func(*awsconfig.LoadOptions) error
+	if len(config.Region) > 0 {
+		options = append(options, awsconfig.WithRegion(config.Region))
+	}
+
+	loaded, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoint *string
+	if len(config.Endpoint) > 0 {
+		endpoint = aws.String(config.Endpoint)
+	}
+
+	return awsClient{
+		sqs: sqs.NewFromConfig(loaded, func(options *sqs.Options) { options.BaseEndpoint = endpoint }),
+		sns: sns.NewFromConfig(loaded, func(options *sns.Options) { options.BaseEndpoint = endpoint }),
+	}, nil
+}
+
+type awsClient struct {
+	sqs *sqs.Client
+	sns *sns.Client
+}
+
+func (this awsClient) CreateQueue(ctx context.Context, name string, fifo bool) (string, string, error) {
+	attributes := map[string]string{}
+	if fifo {
+		attributes[string(sqstypes.QueueAttributeNameFifoQueue)] = "true"
+		attributes[string(sqstypes.QueueAttributeNameContentBasedDeduplication)] = "true"
+	}
+
+	created, err := this.sqs.CreateQueue(ctx, &sqs.CreateQueueInput{QueueName: aws.String(name), Attributes: attributes})
+	if err != nil {
+		return "", "", err
+	}
+
+	queried, err := this.sqs.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       created.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return aws.ToString(created.QueueUrl), queried.Attributes[string(sqstypes.QueueAttributeNameQueueArn)], nil
+}
+func (this awsClient) QueueURL(ctx context.Context, name string) (string, error) {
+	output, err := this.sqs.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.QueueUrl), nil
+}
+
+func (this awsClient) CreateTopic(ctx context.Context, name string, fifo bool) (string, error) {
+	attributes := map[string]string{}
+	if fifo {
+		attributes["FifoTopic"] = "true"
+		attributes["ContentBasedDeduplication"] = "true"
+	}
+
+	output, err := this.sns.CreateTopic(ctx, &sns.CreateTopicInput{Name: aws.String(name), Attributes: attributes})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.TopicArn), nil
+}
+
+func (this awsClient) Subscribe(ctx context.Context, queueURL, queueARN string, topicARNs []string) error {
+	if len(topicARNs) == 0 {
+		return nil
+	}
+
+	queried, err := this.sqs.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNamePolicy},
+	})
+	if err != nil {
+		return err
+	}
+
+	policy, err := mergeQueuePolicy(queried.Attributes[string(sqstypes.QueueAttributeNamePolicy)], queueARN, topicARNs)
+	if err != nil {
+		return err
+	}
+
+	if _, err = this.sqs.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl:   aws.String(queueURL),
+		Attributes: map[string]string{string(sqstypes.QueueAttributeNamePolicy): policy},
+	}); err != nil {
+		return err
+	}
+
+	for _, topicARN := range topicARNs {
+		if _, err = this.sns.Subscribe(ctx, &sns.SubscribeInput{
+			TopicArn:   aws.String(topicARN),
+			Protocol:   aws.String("sqs"),
+			Endpoint:   aws.String(queueARN),
+			Attributes: map[string]string{"RawMessageDelivery": "true"},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeQueuePolicy adds the statement which grants the topics permission to send to the queue to the existing policy
+// of the queue, if any, such that the statements granted by others (or by other readers of the queue) are retained.
+// The topics already granted by a previous statement of this package are retained as well.
+func mergeQueuePolicy(existing, queueARN string, topicARNs []string) (string, error) {
+	policy := map[string]any{"Version": "2012-10-17"}
+	if len(existing) > 0 {
+		if err := json.Unmarshal([]byte(existing), &policy); err != nil {
+			return "", err
+		}
+	}
+
+	var statements []any
+	switch typed := policy["Statement"].(type) {
+	case []any:
+		statements = typed
+	case map[string]any:
+		statements = []any{typed}
+	}
+
+	granted := make([]string, 0, len(topicARNs))
+	retained := make([]any, 0, len(statements)+1)
+	for _, statement := range statements {
+		if typed, ok := statement.(map[string]any); ok && typed["Sid"] == policyStatementID {
+			granted = append(granted, sourceARNs(typed)...)
+		} else {
+			retained = append(retained, statement)
+		}
+	}
+	for _, topicARN := range topicARNs {
+		if !slices.Contains(granted, topicARN) {
+			granted = append(granted, topicARN)
+		}
+	}
+
+	policy["Statement"] = append(retained, map[string]any{
+		"Sid":       policyStatementID,
+		"Effect":    "Allow",
+		"Principal": map[string]any{"Service": "sns.amazonaws.com"},
+		"Action":    "sqs:SendMessage",
+		"Resource":  queueARN,
+		"Condition": map[string]any{"ArnEquals": map[string]any{"aws:SourceArn": granted}},
+	})
+
+	raw, err := json.Marshal(policy)
+	return string(raw), err
+}
+func sourceARNs(statement map[string]any) (values []string) {
+	condition, _ := statement["Condition"].(map[string]any)
+	equals, _ := condition["ArnEquals"].(map[string]any)
+	switch typed := equals["aws:SourceArn"].(type) {
+	case string:
+		values = append(values, typed)
+	case []any:
+		for _, item := range typed {
+			if value, ok := item.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func (this awsClient) Send(ctx context.Context, queueURL string, messages []Message) error {
+	for start := 0; start < len(messages); start += maxBatchSize {
+		batch := messages[start:min(start+maxBatchSize, len(messages))]
+		entries := make([]sqstypes.SendMessageBatchRequestEntry, 0, len(batch))
+		for i, message := range batch {
+			entries = append(entries, sqstypes.SendMessageBatchRequestEntry{
+				Id:                     aws.String(strconv.Itoa(i)),
+				MessageBody:            aws.String(message.Body),
+				MessageAttributes:      sqsAttributes(message.Attributes),
+				MessageGroupId:         optional(message.GroupID),
+				MessageDeduplicationId: optional(message.DeduplicationID),
+			})
+		}
+
+		output, err := this.sqs.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{QueueUrl: aws.String(queueURL), Entries: entries})
+		if err != nil {
+			return err
+		} else if len(output.Failed) > 0 {
+			return batchError(output.Failed[0].Code, output.Failed[0].Message, len(output.Failed))
+		}
+	}
+
+	return nil
+}
+func (this awsClient) Publish(ctx context.Context, topicARN string, messages []Message) error {
+	for start := 0; start < len(messages); start += maxBatchSize {
+		batch := messages[start:min(start+maxBatchSize, len(messages))]
+		entries := make([]snstypes.PublishBatchRequestEntry, 0, len(batch))
+		for i, message := range batch {
+			entries = append(entries, snstypes.PublishBatchRequestEntry{
+				Id:                     aws.String(strconv.Itoa(i)),
+				Message:                aws.String(message.Body),
+				MessageAttributes:      snsAttributes(message.Attributes),
+				MessageGroupId:         optional(message.GroupID),
+				MessageDeduplicationId: optional(message.DeduplicationID),
+			})
+		}
+
+		output, err := this.sns.PublishBatch(ctx, &sns.PublishBatchInput{TopicArn: aws.String(topicARN), PublishBatchRequestEntries: entries})
+		if err != nil {
+			return err
+		} else if len(output.Failed) > 0 {
+			return batchError(output.Failed[0].Code, output.Failed[0].Message, len(output.Failed))
+		}
+	}
+
+	return nil
+}
+
+func (this awsClient) Receive(ctx context.Context, queueURL string, max int, wait time.Duration) ([]Received, error) {
+	output, err := this.sqs.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(queueURL),
+		MaxNumberOfMessages:         int32(min(max, maxBatchSize)),
+		WaitTimeSeconds:             int32(wait / time.Second),
+		MessageAttributeNames:       []string{"All"},
+		MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{sqstypes.MessageSystemAttributeNameSentTimestamp},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	received := make([]Received, 0, len(output.Messages))
+	for _, message := range output.Messages {
+		item := Received{
+			MessageID:     aws.ToString(message.MessageId),
+			ReceiptHandle: aws.ToString(message.ReceiptHandle),
+			Body:          aws.ToString(message.Body),
+			Attributes:    make(map[string]string, len(message.MessageAttributes)),
+		}
+		for key, value := range message.MessageAttributes {
+			item.Attributes[key] = aws.ToString(value.StringValue)
+		}
+		if sent, err := strconv.ParseInt(message.Attributes[string(sqstypes.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+			item.SentTimestamp = time.UnixMilli(sent).UTC()
+		}
+		received = append(received, item)
+	}
+
+	return received, nil
+}
+
+func (this awsClient) Delete(ctx context.Context, queueURL string, receiptHandles []string) ([]string, error) {
+	for start := 0; start < len(receiptHandles); start += maxBatchSize {
+		batch := receiptHandles[start:min(start+maxBatchSize, len(receiptHandles))]
+		entries := make([]sqstypes.DeleteMessageBatchRequestEntry, 0, len(batch))
+		for i, handle := range batch {
+			entries = append(entries, sqstypes.DeleteMessageBatchRequestEntry{Id: aws.String(strconv.Itoa(i)), ReceiptHandle: aws.String(handle)})
+		}
+
+		output, err := this.sqs.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{QueueUrl: aws.String(queueURL), Entries: entries})
+		if err != nil {
+			return receiptHandles[start:], err
+		} else if len(output.Failed) == 0 {
+			continue
+		}
+
+		failed := make([]string, 0, len(output.Failed)+len(receiptHandles)-start-len(batch))
+		for _, entry := range output.Failed {
+			if index, err := strconv.Atoi(aws.ToString(entry.Id)); err == nil && index < len(batch) {
+				failed = append(failed, batch[index])
+			}
+		}
+		failed = append(failed, receiptHandles[start+len(batch):]...)
+		return failed, batchError(output.Failed[0].Code, output.Failed[0].Message, len(output.Failed))
+	}
+
+	return nil, nil
+}
+
+func sqsAttributes(values map[string]string) map[string]sqstypes.MessageAttributeValue {
+	attributes := make(map[string]sqstypes.MessageAttributeValue, len(values))
+	for key, value := range values {
+		attributes[key] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	return attributes
+}
+func snsAttributes(values map[string]string) map[string]snstypes.MessageAttributeValue {
+	attributes := make(map[string]snstypes.MessageAttributeValue, len(values))
+	for key, value := range values {
+		attributes[key] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	return attributes
+}
+func optional(value string) *string {
+	if len(value) == 0 {
+		return nil
+	}
+	return aws.String(value)
+}
+func batchError(code, message *string, count int) error {
+	return fmt.Errorf("%w: %d entries failed, the first with [%s] %s", ErrBatchFailed, count, aws.ToString(code), aws.ToString(message))
+}
+
+var ErrBatchFailed = errors.New("one or more entries of the batch failed")
+
+const (
+	maxBatchSize      = 10
+	policyStatementID = "smarty-messaging-sns-subscriptions"
+)
@@ -0,0 +1,63 @@
+package adapter
+
+import (
+	"context"
+	"time"
+)
+
+func New() Connector { return awsConnector{} }
+
+type Config struct {
+	Region   string
+	Endpoint string // e.g. an ElasticMQ or LocalStack address; empty for AWS
+}
+
+// Message is a message to be sent to a queue or published to a topic. Its attributes are sent as string message
+// attributes. The group and deduplication IDs apply only to FIFO queues and topics.
+type Message struct {
+	Body            string
+	Attributes      map[string]string
+	GroupID         string
+	DeduplicationID string
+}
+
+// Received is a message received from a queue.
+type Received struct {
+	MessageID     string
+	ReceiptHandle string
+	Body          string
+	Attributes    map[string]string
+	SentTimestamp time.Time
+}
+
+type Connector interface {
+	Connect(ctx context.Context, config Config) (Client, error)
+}
+
+type Client interface {
+	// CreateQueue creates the queue, if necessary, and returns its URL and ARN. FIFO queues are created with
+	// content-based deduplication such that messages without a deduplication ID are accepted.
+	CreateQueue(ctx context.Context, name string, fifo bool) (url, arn string, err error)
+	QueueURL(ctx context.Context, name string) (string, error)
+
+	// CreateTopic creates the SNS topic, if necessary, and returns its ARN.
+	CreateTopic(ctx context.Context, name string, fifo bool) (arn string, err error)
+
+	// Subscribe subscribes the queue to each of the topics with raw message delivery (such that message attributes are
+	// preserved) and grants the topics permission to send to the queue by merging a statement into the existing policy
+	// of the queue.
+	Subscribe(ctx context.Context, queueURL, queueARN string, topicARNs []string) error
+
+	// Send sends the messages to the queue in batches of up to ten messages.
+	Send(ctx context.Context, queueURL string, messages []Message) error
+
+	// Publish publishes the messages to the topic in batches of up to ten messages.
+	Publish(ctx context.Context, topicARN string, messages []Message) error
+
+	// Receive long-polls the queue for up to the maximum number of messages (no more than ten).
+	Receive(ctx context.Context, queueURL string, max int, wait time.Duration) ([]Received, error)
+
+	// Delete deletes the messages identified by the receipt handles in batches of up to ten messages. When any message
+	// is not deleted, the receipt handles of all messages which may not have been deleted are returned with the error.
+	Delete(ctx context.Context, queueURL string, receiptHandles []string) (failed []string, err error)
+}
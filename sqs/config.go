@@ -0,0 +1,85 @@
+package sqs
+
+import (
+	"time"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqs/adapter"
+)
+
+func New(options ...option) messaging.Connector {
+	var config configuration
+	Options.apply(options...)(&config)
+	return newConnector(config)
+}
+
+type configuration struct {
+	Region       string
+	Endpoint     string
+	DirectQueues bool
+	WaitTime     time.Duration
+	Connector    adapter.Connector
+	Logger       logger
+	Now          func() time.Time
+}
+
+var Options singleton
+
+type singleton struct{}
+type option func(*configuration)
+
+// Region is the AWS region of the queues and topics. By default, the region is resolved from the environment in the
+// same manner as the AWS CLI, as are the credentials used.
+func (singleton) Region(value string) option {
+	return func(this *configuration) { this.Region = value }
+}
+
+// Endpoint overrides the AWS service endpoint, e.g. "http://localhost:9324" for a local ElasticMQ or LocalStack.
+func (singleton) Endpoint(value string) option {
+	return func(this *configuration) { this.Endpoint = value }
+}
+
+// DirectQueues indicates that the topic of each dispatch names the SQS queue to which it is sent directly rather than
+// the SNS topic to which it is published (and from which it is fanned out to the queues subscribed to the topic).
+func (singleton) DirectQueues(value bool) option {
+	return func(this *configuration) { this.DirectQueues = value }
+}
+
+// WaitTime is the duration each receive request long-polls the queue for messages (at most 20 seconds).
+func (singleton) WaitTime(value time.Duration) option {
+	return func(this *configuration) { this.WaitTime = value }
+}
+func (singleton) Connector(value adapter.Connector) option {
+	return func(this *configuration) { this.Connector = value }
+}
+func (singleton) Logger(value logger) option {
+	return func(this *configuration) { this.Logger = value }
+}
+func (singleton) Now(value func() time.Time) option {
+	return func(this *configuration) { this.Now = value }
+}
+
+func (singleton) apply(options ...option) option {
+	return func(this *configuration) {
+		for _, item := range Options.defaults(options...) {
+			item(this)
+		}
+	}
+}
+func (singleton) defaults(options ...option) []option {
+	var defaultNow = time.Now
+	var defaultLogger = nop{}
+
+	return append([]option{
+		Options.WaitTime(time.Second * 20),
+		Options.Connector(adapter.New()),
+		Options.Logger(defaultLogger),
+		Options.Now(defaultNow),
+	}, options...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type nop struct{}
+
+func (nop) Printf(_ string, _ ...any) {}
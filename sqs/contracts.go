@@ -0,0 +1,39 @@
+package sqs
+
+import (
+	"errors"
+	"strings"
+)
+
+type logger interface {
+	Printf(format string, args ...any)
+}
+
+var (
+	ErrMissingStreamName = errors.New("the stream name (queue name) is required")
+	ErrUnknownDelivery   = errors.New("the delivery is not outstanding on the stream and cannot be acknowledged")
+	ErrClosed            = errors.New("the resource has already been closed")
+)
+
+// The message attributes which carry the properties of a dispatch. Application-specified headers are carried together
+// as a JSON object because SQS permits no more than ten attributes per message. Payloads which are not valid UTF-8
+// text are base64-encoded as indicated by the body encoding attribute.
+const (
+	AttributeTopic           = "Topic"
+	AttributeSourceID        = "Source-Id"
+	AttributeMessageID       = "Message-Id"
+	AttributeCorrelationID   = "Correlation-Id"
+	AttributeMessageType     = "Message-Type"
+	AttributeContentType     = "Content-Type"
+	AttributeContentEncoding = "Content-Encoding"
+	AttributeTimestamp       = "Timestamp"
+	AttributeHeaders         = "Headers"
+	AttributeBodyEncoding    = "Body-Encoding"
+)
+
+const bodyEncodingBase64 = "base64"
+
+// isFIFO indicates whether the queue or topic is a FIFO queue or topic, the names of which must end with ".fifo".
+func isFIFO(name string) bool { return strings.HasSuffix(name, fifoSuffix) }
+
+const fifoSuffix = ".fifo"
@@ -0,0 +1,85 @@
+package sqs
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqs/adapter"
+)
+
+type defaultReader struct {
+	inner   adapter.Client
+	config  configuration
+	streams []io.Closer
+	closed  bool
+	mutex   sync.Mutex
+	logger  logger
+}
+
+func newReader(inner adapter.Client, config configuration) messaging.Reader {
+	return &defaultReader{inner: inner, config: config, logger: config.Logger}
+}
+
+// Stream receives the messages of the SQS queue named by StreamName. When EstablishTopology is specified, the queue is
+// created (as a FIFO queue if its name ends with ".fifo") and, unless topics are direct queues, subscribed to the SNS
+// topic of each of the Topics, each of which is created if necessary.
+func (this *defaultReader) Stream(ctx context.Context, settings messaging.StreamConfig) (messaging.Stream, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed {
+		return nil, ErrClosed
+	}
+
+	if len(settings.StreamName) == 0 {
+		return nil, ErrMissingStreamName
+	}
+
+	queueURL, err := this.queueURL(ctx, settings)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to open stream for queue [%s]: %s", settings.StreamName, err)
+		return nil, err
+	}
+
+	this.logger.Printf("[INFO] Stream opened for queue [%s], awaiting messages...", settings.StreamName)
+	stream := newStream(this.inner, queueURL, settings, this.config)
+	this.streams = append(this.streams, stream)
+	return stream, nil
+}
+func (this *defaultReader) queueURL(ctx context.Context, settings messaging.StreamConfig) (string, error) {
+	if !settings.EstablishTopology {
+		return this.inner.QueueURL(ctx, settings.StreamName)
+	}
+
+	queueURL, queueARN, err := this.inner.CreateQueue(ctx, settings.StreamName, isFIFO(settings.StreamName))
+	if err != nil || this.config.DirectQueues {
+		return queueURL, err
+	}
+
+	topicARNs := make([]string, 0, len(settings.Topics))
+	for _, topic := range settings.Topics {
+		topicARN, err := this.inner.CreateTopic(ctx, topic, isFIFO(topic))
+		if err != nil {
+			return "", err
+		}
+		topicARNs = append(topicARNs, topicARN)
+	}
+
+	return queueURL, this.inner.Subscribe(ctx, queueURL, queueARN, topicARNs)
+}
+
+func (this *defaultReader) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.closed = true
+	for i, stream := range this.streams {
+		this.streams[i] = nil
+		_ = stream.Close()
+	}
+
+	this.streams = this.streams[0:0]
+	return nil
+}
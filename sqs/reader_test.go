@@ -0,0 +1,173 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqs/adapter"
+)
+
+func TestReaderFixture(t *testing.T) {
+	gunit.Run(new(ReaderFixture), t)
+}
+
+type ReaderFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	reader messaging.Reader
+	direct bool
+
+	createQueueName  string
+	createQueueFIFO  bool
+	createQueueError error
+	queueURLName     string
+	queueURLError    error
+	createdTopics    []string
+	createTopicFIFO  []bool
+	createTopicError error
+	subscribeURL     string
+	subscribeARN     string
+	subscribeTopics  []string
+	subscribeError   error
+}
+
+func (this *ReaderFixture) Setup() {
+	this.ctx = context.Background()
+	this.initializeReader()
+}
+func (this *ReaderFixture) initializeReader() {
+	config := configuration{}
+	Options.apply(Options.Connector(nil), Options.DirectQueues(this.direct))(&config)
+	this.reader = newReader(this, config)
+}
+
+func (this *ReaderFixture) TestWhenOpeningStreamWithoutName_ReturnError() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrMissingStreamName)
+}
+func (this *ReaderFixture) TestWhenOpeningStream_LookupQueueURL() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue", Topics: []string{"topic"}})
+
+	this.So(stream, should.NotBeNil)
+	this.So(err, should.BeNil)
+	this.So(this.queueURLName, should.Equal, "queue")
+	this.So(this.createQueueName, should.BeEmpty)
+	this.So(this.createdTopics, should.BeEmpty)
+	this.So(stream.(*defaultStream).queueURL, should.Equal, "url:queue")
+}
+func (this *ReaderFixture) TestWhenQueueLookupFails_ReturnError() {
+	this.queueURLError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.queueURLError)
+}
+func (this *ReaderFixture) TestWhenEstablishingTopology_CreateQueueAndTopicsAndSubscribe() {
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue.fifo",
+		Topics:            []string{"a", "b.fifo"},
+	})
+
+	this.So(stream, should.NotBeNil)
+	this.So(err, should.BeNil)
+	this.So(this.createQueueName, should.Equal, "queue.fifo")
+	this.So(this.createQueueFIFO, should.BeTrue)
+	this.So(this.createdTopics, should.Equal, []string{"a", "b.fifo"})
+	this.So(this.createTopicFIFO, should.Equal, []bool{false, true})
+	this.So(this.subscribeURL, should.Equal, "url:queue.fifo")
+	this.So(this.subscribeARN, should.Equal, "arn:queue.fifo")
+	this.So(this.subscribeTopics, should.Equal, []string{"arn:a", "arn:b.fifo"})
+	this.So(this.queueURLName, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenEstablishingTopologyWithDirectQueues_CreateOnlyQueue() {
+	this.direct = true
+	this.initializeReader()
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		Topics:            []string{"a"},
+	})
+
+	this.So(stream, should.NotBeNil)
+	this.So(err, should.BeNil)
+	this.So(this.createQueueName, should.Equal, "queue")
+	this.So(this.createQueueFIFO, should.BeFalse)
+	this.So(this.createdTopics, should.BeEmpty)
+	this.So(this.subscribeURL, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenCreatingTopicFails_ReturnError() {
+	this.createTopicError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		Topics:            []string{"a"},
+	})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.createTopicError)
+	this.So(this.subscribeURL, should.BeEmpty)
+}
+func (this *ReaderFixture) TestWhenSubscribingFails_ReturnError() {
+	this.subscribeError = errors.New("")
+
+	stream, err := this.reader.Stream(this.ctx, messaging.StreamConfig{
+		EstablishTopology: true,
+		StreamName:        "queue",
+		Topics:            []string{"a"},
+	})
+
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, this.subscribeError)
+}
+func (this *ReaderFixture) TestWhenClosed_StreamsClosedAndNewStreamsRejected() {
+	stream, _ := this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+
+	err := this.reader.Close()
+
+	this.So(err, should.BeNil)
+	this.So(stream.(*defaultStream).closed.Err(), should.NotBeNil)
+	stream, err = this.reader.Stream(this.ctx, messaging.StreamConfig{StreamName: "queue"})
+	this.So(stream, should.BeNil)
+	this.So(err, should.Equal, ErrClosed)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *ReaderFixture) CreateQueue(_ context.Context, name string, fifo bool) (string, string, error) {
+	this.createQueueName = name
+	this.createQueueFIFO = fifo
+	return "url:" + name, "arn:" + name, this.createQueueError
+}
+func (this *ReaderFixture) QueueURL(_ context.Context, name string) (string, error) {
+	this.queueURLName = name
+	return "url:" + name, this.queueURLError
+}
+func (this *ReaderFixture) CreateTopic(_ context.Context, name string, fifo bool) (string, error) {
+	this.createdTopics = append(this.createdTopics, name)
+	this.createTopicFIFO = append(this.createTopicFIFO, fifo)
+	return "arn:" + name, this.createTopicError
+}
+func (this *ReaderFixture) Subscribe(_ context.Context, queueURL, queueARN string, topicARNs []string) error {
+	this.subscribeURL = queueURL
+	this.subscribeARN = queueARN
+	this.subscribeTopics = topicARNs
+	return this.subscribeError
+}
+func (this *ReaderFixture) Send(context.Context, string, []adapter.Message) error    { panic("nop") }
+func (this *ReaderFixture) Publish(context.Context, string, []adapter.Message) error { panic("nop") }
+func (this *ReaderFixture) Receive(context.Context, string, int, time.Duration) ([]adapter.Received, error) {
+	panic("nop")
+}
+func (this *ReaderFixture) Delete(context.Context, string, []string) ([]string, error) { panic("nop") }
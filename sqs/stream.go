@@ -0,0 +1,168 @@
+package sqs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqs/adapter"
+)
+
+// defaultStream long-polls the queue for messages whenever its buffer of received messages is exhausted. Messages
+// remain in the queue until acknowledged; those not acknowledged (including those outstanding when the stream is
+// closed) become visible to other consumers once the queue's visibility timeout has elapsed.
+type defaultStream struct {
+	inner     adapter.Client
+	queueURL  string
+	queueName string
+	capacity  int
+	wait      time.Duration
+	logger    logger
+
+	closed   context.Context
+	shutdown context.CancelFunc
+	buffer   []adapter.Received
+
+	mutex    sync.Mutex
+	sequence uint64
+	inflight map[uint64]string
+}
+
+func newStream(inner adapter.Client, queueURL string, settings messaging.StreamConfig, config configuration) messaging.Stream {
+	capacity := int(settings.BufferCapacity)
+	if capacity <= 0 || capacity > maxReceiveCount {
+		capacity = maxReceiveCount
+	}
+
+	closed, shutdown := context.WithCancel(context.Background())
+	return &defaultStream{
+		inner:     inner,
+		queueURL:  queueURL,
+		queueName: settings.StreamName,
+		capacity:  capacity,
+		wait:      config.WaitTime,
+		logger:    config.Logger,
+		closed:    closed,
+		shutdown:  shutdown,
+		inflight:  make(map[uint64]string),
+	}
+}
+
+func (this *defaultStream) Read(ctx context.Context, target *messaging.Delivery) error {
+	for len(this.buffer) == 0 {
+		if err := this.fill(ctx); err != nil {
+			return err
+		}
+	}
+
+	source := this.buffer[0]
+	this.buffer[0] = adapter.Received{}
+	this.buffer = this.buffer[1:]
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.sequence++
+	this.inflight[this.sequence] = source.ReceiptHandle
+	processDelivery(source, this.sequence, this.queueName, target)
+	return nil
+}
+func (this *defaultStream) fill(ctx context.Context) (err error) {
+	if this.closed.Err() != nil {
+		return io.EOF
+	} else if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(this.closed, cancel)()
+
+	this.buffer, err = this.inner.Receive(ctx, this.queueURL, this.capacity, this.wait)
+	if this.closed.Err() != nil {
+		return io.EOF
+	} else if err != nil && ctx.Err() == nil {
+		this.logger.Printf("[WARN] Unable to receive messages from queue [%s]: %s", this.queueName, err)
+	}
+
+	return err
+}
+
+func processDelivery(source adapter.Received, deliveryID uint64, queueName string, target *messaging.Delivery) {
+	attributes := source.Attributes
+	target.Upstream = source
+	target.DeliveryID = deliveryID
+	target.SourceID = parseUint64(attributes[AttributeSourceID])
+	target.MessageID = parseUint64(attributes[AttributeMessageID])
+	target.CorrelationID = parseUint64(attributes[AttributeCorrelationID])
+	target.Timestamp = source.SentTimestamp
+	target.Durable = true
+	target.Topic = attributes[AttributeTopic]
+	target.MessageType = attributes[AttributeMessageType]
+	target.ContentType = attributes[AttributeContentType]
+	target.ContentEncoding = attributes[AttributeContentEncoding]
+	target.Payload = []byte(source.Body)
+	target.Headers = nil
+
+	if len(target.Topic) == 0 {
+		target.Topic = queueName
+	}
+	if parsed, err := time.Parse(time.RFC3339Nano, attributes[AttributeTimestamp]); err == nil {
+		target.Timestamp = parsed
+	}
+	if attributes[AttributeBodyEncoding] == bodyEncodingBase64 {
+		target.Payload, _ = base64.StdEncoding.DecodeString(source.Body)
+	}
+	if raw := attributes[AttributeHeaders]; len(raw) > 0 {
+		_ = json.Unmarshal([]byte(raw), &target.Headers)
+	}
+}
+func parseUint64(value string) uint64 {
+	parsed, _ := strconv.ParseUint(value, 10, 64)
+	return parsed
+}
+
+// Acknowledge deletes the messages of the deliveries from the queue. When only some are deleted, the others remain
+// outstanding such that they may be acknowledged again.
+func (this *defaultStream) Acknowledge(ctx context.Context, deliveries ...messaging.Delivery) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	handles := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		handle, contains := this.inflight[delivery.DeliveryID]
+		if !contains {
+			return ErrUnknownDelivery
+		}
+		handles = append(handles, handle)
+	}
+
+	if len(handles) == 0 {
+		return nil
+	}
+
+	failed, err := this.inner.Delete(ctx, this.queueURL, handles)
+	if err != nil {
+		this.logger.Printf("[WARN] Unable to delete messages from queue [%s]: %s", this.queueName, err)
+	}
+
+	for _, delivery := range deliveries {
+		if !slices.Contains(failed, this.inflight[delivery.DeliveryID]) {
+			delete(this.inflight, delivery.DeliveryID) // deleted messages are no longer outstanding
+		}
+	}
+
+	return err
+}
+
+func (this *defaultStream) Close() error {
+	this.shutdown()
+	return nil
+}
+
+const maxReceiveCount = 10
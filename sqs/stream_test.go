@@ -0,0 +1,255 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqs/adapter"
+)
+
+func TestStreamFixture(t *testing.T) {
+	gunit.Run(new(StreamFixture), t)
+}
+
+type StreamFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	stream messaging.Stream
+
+	received     [][]adapter.Received
+	receiveURL   string
+	receiveMax   int
+	receiveWait  time.Duration
+	receiveCount int
+	receiveError error
+
+	deleteURL     string
+	deleteHandles []string
+	deleteFailed  []string
+	deleteError   error
+}
+
+func (this *StreamFixture) Setup() {
+	this.ctx = context.Background()
+	config := configuration{}
+	Options.apply(Options.Connector(nil), Options.WaitTime(time.Second))(&config)
+	this.stream = newStream(this, "url", messaging.StreamConfig{StreamName: "queue", BufferCapacity: 32}, config)
+}
+
+func (this *StreamFixture) TestWhenReading_LongPollQueueAndMapMessageToDelivery() {
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	message := adapter.Received{
+		MessageID:     "id",
+		ReceiptHandle: "handle",
+		Body:          "payload",
+		SentTimestamp: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Attributes: map[string]string{
+			AttributeTopic:           "topic",
+			AttributeSourceID:        "1",
+			AttributeMessageID:       "2",
+			AttributeCorrelationID:   "3",
+			AttributeMessageType:     "message-type",
+			AttributeContentType:     "content-type",
+			AttributeContentEncoding: "content-encoding",
+			AttributeTimestamp:       timestamp.Format(time.RFC3339Nano),
+			AttributeHeaders:         `{"key":"value"}`,
+		},
+	}
+	this.received = append(this.received, []adapter.Received{message})
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.BeNil)
+	this.So(this.receiveURL, should.Equal, "url")
+	this.So(this.receiveMax, should.Equal, maxReceiveCount)
+	this.So(this.receiveWait, should.Equal, time.Second)
+	this.So(delivery, should.Equal, messaging.Delivery{
+		Upstream:        message,
+		DeliveryID:      1,
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Timestamp:       timestamp,
+		Durable:         true,
+		Topic:           "topic",
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Headers:         map[string]any{"key": "value"},
+		Payload:         []byte("payload"),
+	})
+}
+func (this *StreamFixture) TestWhenReadingMessageWithoutAttributes_UseQueueNameAndSentTimestamp() {
+	sent := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	this.received = append(this.received, []adapter.Received{{Body: "payload", SentTimestamp: sent}})
+
+	delivery := this.read()
+
+	this.So(delivery.Topic, should.Equal, "queue")
+	this.So(delivery.Timestamp, should.Equal, sent)
+	this.So(delivery.Headers, should.BeNil)
+}
+func (this *StreamFixture) TestWhenReadingBase64EncodedBody_DecodePayload() {
+	this.received = append(this.received, []adapter.Received{{
+		Body:       "AP8=",
+		Attributes: map[string]string{AttributeBodyEncoding: bodyEncodingBase64},
+	}})
+
+	delivery := this.read()
+
+	this.So(delivery.Payload, should.Equal, []byte{0x00, 0xFF})
+}
+func (this *StreamFixture) TestWhenReadingRepeatedly_BufferedMessagesReadBeforePollingAgain() {
+	this.received = append(this.received, []adapter.Received{{}, {}}, []adapter.Received{{}})
+
+	first, second, third := this.read(), this.read(), this.read()
+
+	this.So(first.DeliveryID, should.Equal, 1)
+	this.So(second.DeliveryID, should.Equal, 2)
+	this.So(third.DeliveryID, should.Equal, 3)
+	this.So(this.receiveCount, should.Equal, 2)
+}
+func (this *StreamFixture) TestWhenPollingReturnsNothing_PollAgain() {
+	this.received = append(this.received, nil, []adapter.Received{{Body: "payload"}})
+
+	delivery := this.read()
+
+	this.So(delivery.Payload, should.Equal, []byte("payload"))
+	this.So(this.receiveCount, should.Equal, 2)
+}
+func (this *StreamFixture) TestWhenReceiveFails_ReturnError() {
+	this.receiveError = errors.New("")
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, this.receiveError)
+}
+func (this *StreamFixture) TestWhenContextCancelled_ReturnError() {
+	ctx, cancel := context.WithCancel(this.ctx)
+	cancel()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(ctx, &delivery)
+
+	this.So(err, should.Equal, context.Canceled)
+	this.So(this.receiveCount, should.Equal, 0)
+}
+func (this *StreamFixture) TestWhenClosed_ReturnEOF() {
+	_ = this.stream.Close()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+}
+func (this *StreamFixture) TestWhenClosedWhilePolling_ReturnEOF() {
+	this.received = append(this.received, nil) // blocks until cancelled
+	go func() {
+		time.Sleep(time.Millisecond * 5)
+		_ = this.stream.Close()
+	}()
+
+	var delivery messaging.Delivery
+	err := this.stream.Read(this.ctx, &delivery)
+
+	this.So(err, should.Equal, io.EOF)
+}
+func (this *StreamFixture) TestWhenAcknowledging_DeleteMessagesByReceiptHandle() {
+	this.received = append(this.received, []adapter.Received{{ReceiptHandle: "a"}, {ReceiptHandle: "b"}})
+	deliveries := []messaging.Delivery{this.read(), this.read()}
+
+	err := this.stream.Acknowledge(this.ctx, deliveries...)
+
+	this.So(err, should.BeNil)
+	this.So(this.deleteURL, should.Equal, "url")
+	this.So(this.deleteHandles, should.Equal, []string{"a", "b"})
+	this.So(this.stream.Acknowledge(this.ctx, deliveries[0]), should.Equal, ErrUnknownDelivery)
+}
+func (this *StreamFixture) TestWhenAcknowledgingUnknownDelivery_ReturnErrorWithoutDeleting() {
+	err := this.stream.Acknowledge(this.ctx, messaging.Delivery{DeliveryID: 42})
+
+	this.So(err, should.Equal, ErrUnknownDelivery)
+	this.So(this.deleteHandles, should.BeNil)
+}
+func (this *StreamFixture) TestWhenDeleteFails_ReturnErrorAndRetainDeliveries() {
+	this.received = append(this.received, []adapter.Received{{ReceiptHandle: "a"}})
+	delivery := this.read()
+	this.deleteError = errors.New("")
+
+	err := this.stream.Acknowledge(this.ctx, delivery)
+
+	this.So(err, should.Equal, this.deleteError)
+	this.deleteError = nil
+	this.So(this.stream.Acknowledge(this.ctx, delivery), should.BeNil)
+}
+func (this *StreamFixture) TestWhenDeletePartiallyFails_RetainOnlyUndeletedDeliveries() {
+	this.received = append(this.received, []adapter.Received{{ReceiptHandle: "a"}, {ReceiptHandle: "b"}, {ReceiptHandle: "c"}})
+	deliveries := []messaging.Delivery{this.read(), this.read(), this.read()}
+	this.deleteFailed = []string{"b"}
+	this.deleteError = errors.New("")
+
+	err := this.stream.Acknowledge(this.ctx, deliveries...)
+
+	this.So(err, should.Equal, this.deleteError)
+	this.So(this.stream.Acknowledge(this.ctx, deliveries[0]), should.Equal, ErrUnknownDelivery)
+	this.So(this.stream.Acknowledge(this.ctx, deliveries[2]), should.Equal, ErrUnknownDelivery)
+	this.deleteFailed, this.deleteError = nil, nil
+	this.So(this.stream.Acknowledge(this.ctx, deliveries[1]), should.BeNil)
+	this.So(this.deleteHandles, should.Equal, []string{"b"})
+}
+
+func (this *StreamFixture) read() (delivery messaging.Delivery) {
+	this.So(this.stream.Read(this.ctx, &delivery), should.BeNil)
+	return delivery
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *StreamFixture) Receive(ctx context.Context, queueURL string, max int, wait time.Duration) ([]adapter.Received, error) {
+	this.receiveCount++
+	this.receiveURL = queueURL
+	this.receiveMax = max
+	this.receiveWait = wait
+	if this.receiveError != nil {
+		return nil, this.receiveError
+	}
+
+	if len(this.received) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	received := this.received[0]
+	this.received = this.received[1:]
+	if received == nil && len(this.received) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	return received, nil
+}
+func (this *StreamFixture) Delete(_ context.Context, queueURL string, receiptHandles []string) ([]string, error) {
+	this.deleteURL = queueURL
+	this.deleteHandles = receiptHandles
+	if this.deleteError != nil && this.deleteFailed == nil {
+		return receiptHandles, this.deleteError
+	}
+	return this.deleteFailed, this.deleteError
+}
+func (this *StreamFixture) CreateQueue(context.Context, string, bool) (string, string, error) {
+	panic("nop")
+}
+func (this *StreamFixture) QueueURL(context.Context, string) (string, error)          { panic("nop") }
+func (this *StreamFixture) CreateTopic(context.Context, string, bool) (string, error) { panic("nop") }
+func (this *StreamFixture) Subscribe(context.Context, string, string, []string) error { panic("nop") }
+func (this *StreamFixture) Send(context.Context, string, []adapter.Message) error     { panic("nop") }
+func (this *StreamFixture) Publish(context.Context, string, []adapter.Message) error  { panic("nop") }
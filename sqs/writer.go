@@ -0,0 +1,209 @@
+package sqs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqs/adapter"
+)
+
+// defaultWriter publishes each dispatch to the SNS topic named by its topic or, when configured for direct queues,
+// sends it to the SQS queue so named. Dispatches to FIFO topics and queues are grouped by partition and deduplicated
+// by message ID. Neither SNS nor SQS offers a transaction spanning multiple messages, so a transactional writer
+// buffers the messages written and sends them in order upon Commit.
+type defaultWriter struct {
+	inner         adapter.Client
+	direct        bool
+	transactional bool
+	now           func() time.Time
+	logger        logger
+
+	mutex        sync.Mutex
+	buffer       []pendingMessage
+	destinations map[string]string
+}
+type pendingMessage struct {
+	Topic   string
+	Message adapter.Message
+}
+
+func newWriter(inner adapter.Client, transactional bool, config configuration) messaging.CommitWriter {
+	return &defaultWriter{
+		inner:         inner,
+		direct:        config.DirectQueues,
+		transactional: transactional,
+		now:           config.Now,
+		logger:        config.Logger,
+		destinations:  make(map[string]string),
+	}
+}
+
+func (this *defaultWriter) Write(ctx context.Context, dispatches ...messaging.Dispatch) (int, error) {
+	now := this.now().UTC()
+	messages := make([]pendingMessage, 0, len(dispatches))
+	for _, dispatch := range dispatches {
+		if len(dispatch.Topic) == 0 {
+			return 0, messaging.ErrEmptyDispatchTopic
+		}
+
+		messages = append(messages, pendingMessage{Topic: dispatch.Topic, Message: toMessage(dispatch, now)})
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.transactional {
+		this.buffer = append(this.buffer, messages...)
+		return len(messages), nil
+	}
+
+	return this.send(ctx, messages)
+}
+
+// send sends each consecutive run of messages having the same topic together such that the order of the messages
+// written is preserved.
+func (this *defaultWriter) send(ctx context.Context, messages []pendingMessage) (int, error) {
+	for start := 0; start < len(messages); {
+		topic := messages[start].Topic
+		end := start + 1
+		for end < len(messages) && messages[end].Topic == topic {
+			end++
+		}
+
+		batch := make([]adapter.Message, 0, end-start)
+		for _, pending := range messages[start:end] {
+			batch = append(batch, pending.Message)
+		}
+
+		if err := this.sendBatch(ctx, topic, batch); err != nil {
+			this.logger.Printf("[WARN] Unable to send messages to [%s]: %s", topic, err)
+			return start, err
+		}
+
+		start = end
+	}
+
+	return len(messages), nil
+}
+func (this *defaultWriter) sendBatch(ctx context.Context, topic string, batch []adapter.Message) error {
+	destination, err := this.resolve(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	if this.direct {
+		return this.inner.Send(ctx, destination, batch)
+	}
+
+	return this.inner.Publish(ctx, destination, batch)
+}
+func (this *defaultWriter) resolve(ctx context.Context, topic string) (destination string, err error) {
+	if destination, contains := this.destinations[topic]; contains {
+		return destination, nil
+	}
+
+	if this.direct {
+		destination, err = this.inner.QueueURL(ctx, topic)
+	} else {
+		destination, err = this.inner.CreateTopic(ctx, topic, isFIFO(topic)) // idempotent, returns the existing ARN
+	}
+
+	if err == nil {
+		this.destinations[topic] = destination
+	}
+
+	return destination, err
+}
+
+func toMessage(dispatch messaging.Dispatch, now time.Time) adapter.Message {
+	if dispatch.Timestamp.IsZero() {
+		dispatch.Timestamp = now
+	}
+
+	message := adapter.Message{Body: string(dispatch.Payload), Attributes: make(map[string]string)}
+	setAttribute(message.Attributes, AttributeTopic, dispatch.Topic)
+	setAttribute(message.Attributes, AttributeSourceID, formatUint64(dispatch.SourceID))
+	setAttribute(message.Attributes, AttributeMessageID, formatUint64(dispatch.MessageID))
+	setAttribute(message.Attributes, AttributeCorrelationID, formatUint64(dispatch.CorrelationID))
+	setAttribute(message.Attributes, AttributeMessageType, dispatch.MessageType)
+	setAttribute(message.Attributes, AttributeContentType, dispatch.ContentType)
+	setAttribute(message.Attributes, AttributeContentEncoding, dispatch.ContentEncoding)
+	setAttribute(message.Attributes, AttributeTimestamp, dispatch.Timestamp.UTC().Format(time.RFC3339Nano))
+
+	if !utf8.Valid(dispatch.Payload) {
+		message.Body = base64.StdEncoding.EncodeToString(dispatch.Payload)
+		setAttribute(message.Attributes, AttributeBodyEncoding, bodyEncodingBase64)
+	}
+	if len(dispatch.Headers) > 0 {
+		headers := make(map[string]string, len(dispatch.Headers))
+		for key, value := range dispatch.Headers {
+			headers[key] = formatHeader(value)
+		}
+		raw, _ := json.Marshal(headers)
+		setAttribute(message.Attributes, AttributeHeaders, string(raw))
+	}
+
+	if isFIFO(dispatch.Topic) {
+		message.GroupID = strconv.FormatUint(dispatch.Partition, 10)
+		message.DeduplicationID = formatUint64(dispatch.MessageID) // otherwise content-based deduplication applies
+	}
+
+	return message
+}
+func setAttribute(attributes map[string]string, key, value string) {
+	if len(value) > 0 {
+		attributes[key] = value // SQS rejects attributes having empty values
+	}
+}
+func formatUint64(value uint64) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(value, 10)
+}
+func formatHeader(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func (this *defaultWriter) Commit() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	_, err := this.send(context.Background(), this.buffer)
+	this.clearBuffer()
+	return err
+}
+func (this *defaultWriter) Rollback() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.clearBuffer()
+	return nil
+}
+func (this *defaultWriter) clearBuffer() {
+	for i := range this.buffer {
+		this.buffer[i] = pendingMessage{} // clear it out to avoid a memory leak
+	}
+
+	this.buffer = this.buffer[0:0]
+}
+
+// Close releases any buffered messages; the underlying AWS clients are shared by the connection.
+func (this *defaultWriter) Close() error {
+	return this.Rollback()
+}
@@ -0,0 +1,250 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smarty/gunit"
+	"github.com/smarty/gunit/assert/should"
+	"github.com/smarty/messaging/v3"
+	"github.com/smarty/messaging/v3/sqs/adapter"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+
+	ctx    context.Context
+	writer messaging.CommitWriter
+	now    time.Time
+	direct bool
+
+	createdTopics []string
+	lookedUp      []string
+	resolveError  error
+	destinations  []string
+	batches       [][]adapter.Message
+	sendCount     int
+	publishCount  int
+	sendError     error
+}
+
+func (this *WriterFixture) Setup() {
+	this.ctx = context.Background()
+	this.now = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	this.initializeWriter(false)
+}
+func (this *WriterFixture) initializeWriter(transactional bool) {
+	config := configuration{}
+	Options.apply(
+		Options.Connector(nil),
+		Options.DirectQueues(this.direct),
+		Options.Now(func() time.Time { return this.now }),
+	)(&config)
+	this.writer = newWriter(this, transactional, config)
+}
+
+func (this *WriterFixture) TestWhenWritingDispatchWithoutTopic_ReturnError() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, messaging.ErrEmptyDispatchTopic)
+	this.So(this.batches, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWriting_PublishMessageToTopic() {
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{
+		SourceID:        1,
+		MessageID:       2,
+		CorrelationID:   3,
+		Topic:           "topic",
+		MessageType:     "message-type",
+		ContentType:     "content-type",
+		ContentEncoding: "content-encoding",
+		Payload:         []byte("payload"),
+		Headers:         map[string]any{"key": 42},
+	})
+
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.createdTopics, should.Equal, []string{"topic"})
+	this.So(this.destinations, should.Equal, []string{"arn:topic"})
+	this.So(this.publishCount, should.Equal, 1)
+	this.So(this.batches, should.Equal, [][]adapter.Message{{{
+		Body: "payload",
+		Attributes: map[string]string{
+			AttributeTopic:           "topic",
+			AttributeSourceID:        "1",
+			AttributeMessageID:       "2",
+			AttributeCorrelationID:   "3",
+			AttributeMessageType:     "message-type",
+			AttributeContentType:     "content-type",
+			AttributeContentEncoding: "content-encoding",
+			AttributeTimestamp:       this.now.Format(time.RFC3339Nano),
+			AttributeHeaders:         `{"key":"42"}`,
+		},
+	}}})
+}
+func (this *WriterFixture) TestWhenWritingBinaryPayload_EncodeBodyAsBase64() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic", Payload: []byte{0x00, 0xFF}})
+
+	message := this.batches[0][0]
+	this.So(message.Body, should.Equal, "AP8=")
+	this.So(message.Attributes[AttributeBodyEncoding], should.Equal, bodyEncodingBase64)
+}
+func (this *WriterFixture) TestWhenWritingToFIFOTopic_GroupByPartitionAndDeduplicateByMessageID() {
+	_, _ = this.writer.Write(this.ctx,
+		messaging.Dispatch{Topic: "topic.fifo", Partition: 7, MessageID: 9},
+		messaging.Dispatch{Topic: "topic.fifo", Partition: 8})
+
+	this.So(this.batches, should.HaveLength, 1)
+	this.So(this.batches[0][0].GroupID, should.Equal, "7")
+	this.So(this.batches[0][0].DeduplicationID, should.Equal, "9")
+	this.So(this.batches[0][1].GroupID, should.Equal, "8")
+	this.So(this.batches[0][1].DeduplicationID, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWritingToStandardTopic_NoGroupOrDeduplicationID() {
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic", Partition: 7, MessageID: 9})
+
+	this.So(this.batches[0][0].GroupID, should.BeEmpty)
+	this.So(this.batches[0][0].DeduplicationID, should.BeEmpty)
+}
+func (this *WriterFixture) TestWhenWritingToMultipleTopics_SendConsecutiveRunsInOrderAndResolveEachTopicOnce() {
+	count, err := this.writer.Write(this.ctx,
+		messaging.Dispatch{Topic: "a", Payload: []byte("1")},
+		messaging.Dispatch{Topic: "a", Payload: []byte("2")},
+		messaging.Dispatch{Topic: "b", Payload: []byte("3")},
+		messaging.Dispatch{Topic: "a", Payload: []byte("4")})
+
+	this.So(count, should.Equal, 4)
+	this.So(err, should.BeNil)
+	this.So(this.createdTopics, should.Equal, []string{"a", "b"})
+	this.So(this.destinations, should.Equal, []string{"arn:a", "arn:b", "arn:a"})
+	this.So(this.bodies(), should.Equal, [][]string{{"1", "2"}, {"3"}, {"4"}})
+}
+func (this *WriterFixture) TestWhenWritingToDirectQueues_SendMessagesToQueue() {
+	this.direct = true
+	this.initializeWriter(false)
+
+	_, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "queue"})
+
+	this.So(err, should.BeNil)
+	this.So(this.lookedUp, should.Equal, []string{"queue"})
+	this.So(this.createdTopics, should.BeEmpty)
+	this.So(this.destinations, should.Equal, []string{"url:queue"})
+	this.So(this.sendCount, should.Equal, 1)
+	this.So(this.publishCount, should.Equal, 0)
+}
+func (this *WriterFixture) TestWhenResolvingDestinationFails_ReturnErrorAndRetryLater() {
+	this.resolveError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.resolveError)
+	this.So(this.batches, should.BeEmpty)
+
+	this.resolveError = nil
+	_, err = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "topic"})
+	this.So(err, should.BeNil)
+	this.So(this.createdTopics, should.Equal, []string{"topic", "topic"})
+}
+func (this *WriterFixture) TestWhenSendingFails_ReturnCountOfMessagesSent() {
+	this.sendError = errors.New("")
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"}, messaging.Dispatch{Topic: "b"})
+
+	this.So(count, should.Equal, 0)
+	this.So(err, should.Equal, this.sendError)
+}
+func (this *WriterFixture) TestWhenWritingTransactionally_SendOnlyUponCommit() {
+	this.initializeWriter(true)
+
+	count, err := this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a", Payload: []byte("1")})
+	this.So(count, should.Equal, 1)
+	this.So(err, should.BeNil)
+	this.So(this.batches, should.BeEmpty)
+
+	err = this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.bodies(), should.Equal, [][]string{{"1"}})
+	this.So(this.writer.Commit(), should.BeNil)
+	this.So(this.batches, should.HaveLength, 1)
+}
+func (this *WriterFixture) TestWhenCommitFails_ReturnErrorAndDiscardBuffer() {
+	this.initializeWriter(true)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	sendError := errors.New("")
+	this.sendError = sendError
+
+	err := this.writer.Commit()
+
+	this.So(err, should.Equal, sendError)
+	this.sendError = nil
+	this.So(this.writer.Commit(), should.BeNil)
+	this.So(this.publishCount, should.Equal, 1)
+}
+func (this *WriterFixture) TestWhenRollingBackOrClosing_DiscardBuffer() {
+	this.initializeWriter(true)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "a"})
+	this.So(this.writer.Rollback(), should.BeNil)
+	_, _ = this.writer.Write(this.ctx, messaging.Dispatch{Topic: "b"})
+	this.So(this.writer.Close(), should.BeNil)
+
+	err := this.writer.Commit()
+
+	this.So(err, should.BeNil)
+	this.So(this.batches, should.BeEmpty)
+}
+
+func (this *WriterFixture) bodies() (bodies [][]string) {
+	for _, batch := range this.batches {
+		var items []string
+		for _, message := range batch {
+			items = append(items, message.Body)
+		}
+		bodies = append(bodies, items)
+	}
+	return bodies
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *WriterFixture) CreateTopic(_ context.Context, name string, _ bool) (string, error) {
+	this.createdTopics = append(this.createdTopics, name)
+	return "arn:" + name, this.resolveError
+}
+func (this *WriterFixture) QueueURL(_ context.Context, name string) (string, error) {
+	this.lookedUp = append(this.lookedUp, name)
+	return "url:" + name, this.resolveError
+}
+func (this *WriterFixture) Publish(_ context.Context, topicARN string, messages []adapter.Message) error {
+	this.publishCount++
+	return this.record(topicARN, messages)
+}
+func (this *WriterFixture) Send(_ context.Context, queueURL string, messages []adapter.Message) error {
+	this.sendCount++
+	return this.record(queueURL, messages)
+}
+func (this *WriterFixture) record(destination string, messages []adapter.Message) error {
+	if this.sendError != nil {
+		return this.sendError
+	}
+
+	this.destinations = append(this.destinations, destination)
+	this.batches = append(this.batches, messages)
+	return nil
+}
+func (this *WriterFixture) CreateQueue(context.Context, string, bool) (string, string, error) {
+	panic("nop")
+}
+func (this *WriterFixture) Subscribe(context.Context, string, string, []string) error { panic("nop") }
+func (this *WriterFixture) Receive(context.Context, string, int, time.Duration) ([]adapter.Received, error) {
+	panic("nop")
+}
+func (this *WriterFixture) Delete(context.Context, string, []string) ([]string, error) { panic("nop") }
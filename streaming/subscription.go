@@ -11,6 +11,8 @@ type Subscription struct {
 	name               string
 	streamName         string
 	streamReplication  bool
+	streamQueue        bool
+	streamOffset       string
 	subscriptionTopics []string
 	availableTopics    []string
 	partition          uint64
@@ -18,6 +20,7 @@ type Subscription struct {
 	handlers           []messaging.Handler
 	bufferCapacity     uint16
 	establishTopology  bool
+	verifyTopology     bool
 	batchCapacity      uint16
 	handleDelivery     bool
 	deliveryToContext  bool
@@ -25,22 +28,37 @@ type Subscription struct {
 	reconnectDelay     time.Duration
 	shutdownTimeout    time.Duration
 	shutdownStrategy   ShutdownStrategy
+	failurePolicy      FailurePolicy
+	queueArguments     messaging.QueueArguments
+	maxPriority        uint8
 }
 
 func (this Subscription) streamConfig() messaging.StreamConfig {
 	return messaging.StreamConfig{
 		EstablishTopology: this.establishTopology,
+		VerifyTopology:    this.verifyTopology,
 		ExclusiveStream:   len(this.handlers) <= 1,
 		BufferCapacity:    this.bufferCapacity,
 		StreamName:        this.streamName,
 		StreamReplication: this.streamReplication,
+		StreamQueue:       this.streamQueue,
 		Topics:            this.subscriptionTopics,
 		AvailableTopics:   this.availableTopics,
 		GroupName:         this.name,
 		Partition:         this.partition,
 		Sequence:          this.sequence,
+		StreamOffset:      this.streamOffset,
+		QueueArguments:    this.arguments(),
 	}
 }
+func (this Subscription) arguments() messaging.QueueArguments {
+	arguments := this.queueArguments
+	if this.maxPriority > 0 {
+		arguments.MaxPriority = this.maxPriority
+	}
+
+	return arguments
+}
 func (this Subscription) hardShutdown(potentialParent context.Context) (context.Context, context.CancelFunc) {
 	if this.shutdownStrategy == ShutdownStrategyImmediate {
 		return potentialParent, func() {}
@@ -56,3 +74,15 @@ const (
 	ShutdownStrategyImmediate
 	ShutdownStrategyDrain
 )
+
+// FailurePolicy decides the action taken when a handler panics while handling a batch of deliveries. The value
+// recovered from the panic is provided along with a copy of the deliveries of the batch, which the policy may retain.
+type FailurePolicy func(recovered any, deliveries []messaging.Delivery) FailureAction
+
+type FailureAction int
+
+const (
+	FailureActionPanic   FailureAction = iota // the panic continues, as when no policy is configured
+	FailureActionRequeue                      // the batch is rejected and requeued for redelivery
+	FailureActionDiscard                      // the batch is rejected and discarded (or dead-lettered)
+)
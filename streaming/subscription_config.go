@@ -47,15 +47,34 @@ func (subscriptionSingleton) BufferDelayBetweenBatches(value time.Duration) subs
 func (subscriptionSingleton) EstablishTopology(value bool) subscriptionOption {
 	return func(this *Subscription) { this.establishTopology = value }
 }
+func (subscriptionSingleton) VerifyTopology(value bool) subscriptionOption {
+	return func(this *Subscription) { this.verifyTopology = value }
+}
 func (subscriptionSingleton) StreamReplication(value bool) subscriptionOption {
 	return func(this *Subscription) { this.streamReplication = value }
 }
+func (subscriptionSingleton) StreamQueue(value bool) subscriptionOption {
+	return func(this *Subscription) { this.streamQueue = value }
+}
+func (subscriptionSingleton) StreamOffset(value string) subscriptionOption {
+	return func(this *Subscription) { this.streamOffset = value }
+}
 func (subscriptionSingleton) Topics(values ...string) subscriptionOption {
 	return func(this *Subscription) { this.subscriptionTopics = values }
 }
 func (subscriptionSingleton) AvailableTopics(values ...string) subscriptionOption {
 	return func(this *Subscription) { this.availableTopics = values }
 }
+func (subscriptionSingleton) QueueArguments(value messaging.QueueArguments) subscriptionOption {
+	return func(this *Subscription) { this.queueArguments = value }
+}
+
+// MaxPriority declares the queue as a priority queue supporting the priorities up to the value specified, such that
+// dispatches written with a higher Priority are delivered ahead of those of lower priority. This takes precedence over
+// the MaxPriority of the QueueArguments, if any.
+func (subscriptionSingleton) MaxPriority(value uint8) subscriptionOption {
+	return func(this *Subscription) { this.maxPriority = value }
+}
 func (subscriptionSingleton) Partition(value uint64) subscriptionOption {
 	return func(this *Subscription) { this.partition = value }
 }
@@ -71,6 +90,13 @@ func (subscriptionSingleton) FullDeliveryToContext(value bool) subscriptionOptio
 func (subscriptionSingleton) ReconnectDelay(value time.Duration) subscriptionOption {
 	return func(this *Subscription) { this.reconnectDelay = value }
 }
+
+// FailurePolicy causes each worker to recover when its handler panics and, as decided by the policy, to reject the
+// deliveries of the failed batch rather than acknowledging them. If the stream does not implement messaging.Rejecter,
+// the panic continues without consulting the policy.
+func (subscriptionSingleton) FailurePolicy(value FailurePolicy) subscriptionOption {
+	return func(this *Subscription) { this.failurePolicy = value }
+}
 func (subscriptionSingleton) ShutdownStrategy(strategy ShutdownStrategy, timeout time.Duration) subscriptionOption {
 	return func(this *Subscription) {
 		switch strategy {
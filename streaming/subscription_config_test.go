@@ -48,23 +48,31 @@ func (this *SubscriptionConfigFixture) TestWhenValuesAreProvided_SubscriptionSho
 		SubscriptionOptions.BufferCapacity(2),
 		SubscriptionOptions.BufferDelayBetweenBatches(3),
 		SubscriptionOptions.EstablishTopology(true),
+		SubscriptionOptions.VerifyTopology(true),
 		SubscriptionOptions.StreamReplication(true),
+		SubscriptionOptions.StreamQueue(true),
+		SubscriptionOptions.StreamOffset("first"),
 		SubscriptionOptions.FullDeliveryToHandler(true),
 		SubscriptionOptions.ReconnectDelay(5),
 		SubscriptionOptions.ShutdownStrategy(ShutdownStrategyCurrentBatch, 4),
 		SubscriptionOptions.Partition(6),
 		SubscriptionOptions.Sequence(7),
+		SubscriptionOptions.QueueArguments(messaging.QueueArguments{DeadLetterExchange: "dlx"}),
+		SubscriptionOptions.MaxPriority(10),
 	)
 
 	this.So(subscription, should.Equal, Subscription{
 		name:               "name",
 		streamName:         "queue",
 		streamReplication:  true,
+		streamQueue:        true,
+		streamOffset:       "first",
 		subscriptionTopics: []string{"topic1", "topic2"},
 		availableTopics:    []string{"topic3"},
 		handlers:           []messaging.Handler{nil},
 		bufferCapacity:     2,
 		establishTopology:  true,
+		verifyTopology:     true,
 		batchCapacity:      1,
 		handleDelivery:     true,
 		bufferTimeout:      3,
@@ -73,6 +81,12 @@ func (this *SubscriptionConfigFixture) TestWhenValuesAreProvided_SubscriptionSho
 		shutdownTimeout:    4,
 		partition:          6,
 		sequence:           7,
+		queueArguments:     messaging.QueueArguments{DeadLetterExchange: "dlx"},
+		maxPriority:        10,
+	})
+	this.So(subscription.streamConfig().QueueArguments, should.Equal, messaging.QueueArguments{
+		DeadLetterExchange: "dlx",
+		MaxPriority:        10,
 	})
 }
 
@@ -110,3 +124,10 @@ func (this *SubscriptionConfigFixture) TestWhenFullThrottle_MaximumValuesForBuff
 	this.So(subscription.batchCapacity, should.Equal, 65535)
 	this.So(subscription.bufferCapacity, should.Equal, 65535)
 }
+func (this *SubscriptionConfigFixture) TestWhenFailurePolicyProvided_SubscriptionShouldHavePolicy() {
+	subscription := NewSubscription("queue",
+		SubscriptionOptions.AddWorkers(nil),
+		SubscriptionOptions.FailurePolicy(func(any, []messaging.Delivery) FailureAction { return FailureActionDiscard }))
+
+	this.So(subscription.failurePolicy(nil, nil), should.Equal, FailureActionDiscard)
+}
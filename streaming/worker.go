@@ -3,6 +3,7 @@ package streaming
 import (
 	"context"
 	"reflect"
+	"slices"
 	"sync"
 	"time"
 
@@ -22,6 +23,7 @@ type defaultWorker struct {
 	contextDelivery bool
 	bufferTimeout   time.Duration
 	strategy        ShutdownStrategy
+	failurePolicy   FailurePolicy
 	bufferLength    int
 }
 
@@ -39,6 +41,7 @@ func newWorker(config workerConfig) messaging.Listener {
 		contextDelivery: config.Subscription.deliveryToContext,
 		bufferTimeout:   config.Subscription.bufferTimeout,
 		strategy:        config.Subscription.shutdownStrategy,
+		failurePolicy:   config.Subscription.failurePolicy,
 	}
 }
 
@@ -120,12 +123,43 @@ func (this *defaultWorker) measureBufferLength() int {
 	return this.bufferLength
 }
 func (this *defaultWorker) deliverBatch() bool {
-	if len(this.currentBatch) > 0 {
-		this.handler.Handle(this.deliveryContext(), this.currentBatch...)
+	if recovered, failed := this.handleBatch(); failed {
+		return this.rejectBatch(recovered)
 	}
 
 	return this.stream.Acknowledge(this.hardContext, this.unacknowledged...) == nil
 }
+func (this *defaultWorker) handleBatch() (recovered any, failed bool) {
+	if len(this.currentBatch) == 0 {
+		return nil, false
+	}
+
+	if this.failurePolicy != nil {
+		defer func() {
+			if recovered = recover(); recovered != nil {
+				failed = true
+			}
+		}()
+	}
+
+	this.handler.Handle(this.deliveryContext(), this.currentBatch...)
+	return nil, false
+}
+func (this *defaultWorker) rejectBatch(recovered any) bool {
+	rejecter, supported := this.stream.(messaging.Rejecter)
+	if !supported {
+		panic(recovered)
+	}
+
+	// the policy may retain the deliveries, whereas the backing array of the batch is reused by subsequent batches
+	action := this.failurePolicy(recovered, slices.Clone(this.unacknowledged))
+	if action != FailureActionRequeue && action != FailureActionDiscard {
+		panic(recovered)
+	}
+
+	requeue := action == FailureActionRequeue
+	return rejecter.Reject(this.hardContext, requeue, this.unacknowledged...) == nil
+}
 func (this *defaultWorker) deliveryContext() context.Context {
 	if this.contextDelivery {
 		return context.WithValue(this.hardContext, ContextKeyDeliveries, this.unacknowledged)
@@ -20,6 +20,7 @@ type WorkerFixture struct {
 	*gunit.Fixture
 
 	handler       messaging.Handler
+	stream        messaging.Stream
 	softContext   context.Context
 	softShutdown  context.CancelFunc
 	hardContext   context.Context
@@ -40,16 +41,27 @@ type WorkerFixture struct {
 	acknowledgeDeliveries []messaging.Delivery
 	acknowledgeError      error
 
+	rejectCount      int
+	rejectContext    context.Context
+	rejectRequeue    bool
+	rejectDeliveries []messaging.Delivery
+	rejectError      error
+
+	policyRecovered  any
+	policyDeliveries [][]messaging.Delivery
+
 	closeCount int
 
 	handleTimestamp time.Time
 	handleCount     int
 	handleCtx       context.Context
 	handleMessages  []any
+	handlePanic     any
 }
 
 func (this *WorkerFixture) Setup() {
 	this.handler = this
+	this.stream = this
 	this.softContext, this.softShutdown = context.WithCancel(context.Background())
 	this.hardContext, this.hardShutdown = context.WithCancel(context.Background())
 	this.subscription = Subscription{bufferCapacity: 16, batchCapacity: 16}
@@ -57,7 +69,7 @@ func (this *WorkerFixture) Setup() {
 }
 func (this *WorkerFixture) initializeWorker() {
 	worker := newWorker(workerConfig{
-		Stream:       this,
+		Stream:       this.stream,
 		Subscription: this.subscription,
 		Handler:      this.handler,
 		SoftContext:  this.softContext,
@@ -282,6 +294,108 @@ func (this *WorkerFixture) TestWhenRequestingShutdownAndStrategyIsCurrentBatch_D
 	this.So(this.acknowledgeTimestamp[0], should.HappenWithin, time.Millisecond*25, time.Now().UTC()) // 1-second sleep is skipped
 }
 
+func (this *WorkerFixture) TestWhenHandlerPanicsWithoutFailurePolicy_Panic() {
+	this.readError = io.EOF
+	this.handlePanic = "boink"
+	this.channelBuffer <- messaging.Delivery{Message: 1}
+
+	this.So(this.worker.Listen, should.Panic)
+	this.So(this.acknowledgeCount, should.Equal, 0)
+	this.So(this.rejectCount, should.Equal, 0)
+}
+func (this *WorkerFixture) TestWhenHandlerPanicsAndPolicyRequeues_RejectBatchWithRequeueAndContinue() {
+	this.readError = io.EOF
+	this.handlePanic = "boink"
+	this.subscription.batchCapacity = 2
+	this.subscription.failurePolicy = this.policy(FailureActionRequeue)
+	this.initializeWorker()
+	deliveries := []messaging.Delivery{{Message: 1}, {Message: 2}}
+	this.channelBuffer <- deliveries[0]
+	this.channelBuffer <- deliveries[1]
+	this.channelBuffer <- messaging.Delivery{Message: 3}
+
+	this.worker.Listen()
+
+	this.So(this.policyRecovered, should.Equal, "boink")
+	this.So(this.policyDeliveries, should.Equal, [][]messaging.Delivery{deliveries, {{Message: 3}}}) // retained by the policy
+	this.So(this.rejectCount, should.Equal, 2)
+	this.So(this.rejectContext, should.Equal, this.hardContext)
+	this.So(this.rejectRequeue, should.BeTrue)
+	this.So(this.rejectDeliveries, should.Equal, append(deliveries, messaging.Delivery{Message: 3}))
+	this.So(this.acknowledgeCount, should.Equal, 0)
+}
+func (this *WorkerFixture) TestWhenHandlerPanicsAndPolicyDiscards_RejectBatchWithoutRequeue() {
+	this.readError = io.EOF
+	this.handlePanic = "boink"
+	this.subscription.failurePolicy = this.policy(FailureActionDiscard)
+	this.initializeWorker()
+	this.channelBuffer <- messaging.Delivery{Message: 1}
+
+	this.worker.Listen()
+
+	this.So(this.rejectCount, should.Equal, 1)
+	this.So(this.rejectRequeue, should.BeFalse)
+	this.So(this.rejectDeliveries, should.Equal, []messaging.Delivery{{Message: 1}})
+}
+func (this *WorkerFixture) TestWhenHandlerSucceedsWithFailurePolicy_AcknowledgeBatch() {
+	this.readError = io.EOF
+	this.subscription.failurePolicy = this.policy(FailureActionDiscard)
+	this.initializeWorker()
+	this.channelBuffer <- messaging.Delivery{Message: 1}
+
+	this.worker.Listen()
+
+	this.So(this.acknowledgeCount, should.Equal, 1)
+	this.So(this.rejectCount, should.Equal, 0)
+	this.So(this.policyRecovered, should.BeNil)
+}
+func (this *WorkerFixture) TestWhenHandlerPanicsAndPolicyPanics_Panic() {
+	this.readError = io.EOF
+	this.handlePanic = "boink"
+	this.subscription.failurePolicy = this.policy(FailureActionPanic)
+	this.initializeWorker()
+	this.channelBuffer <- messaging.Delivery{Message: 1}
+
+	this.So(this.worker.Listen, should.PanicWith, "boink")
+	this.So(this.rejectCount, should.Equal, 0)
+}
+func (this *WorkerFixture) TestWhenHandlerPanicsAndStreamCannotReject_Panic() {
+	this.readError = io.EOF
+	this.handlePanic = "boink"
+	this.stream = struct{ messaging.Stream }{Stream: this} // hides Reject
+	this.subscription.failurePolicy = this.policy(FailureActionRequeue)
+	this.initializeWorker()
+	this.channelBuffer <- messaging.Delivery{Message: 1}
+
+	this.So(this.worker.Listen, should.PanicWith, "boink")
+	this.So(this.rejectCount, should.Equal, 0)
+	this.So(this.policyDeliveries, should.BeEmpty)
+}
+func (this *WorkerFixture) TestWhenRejectionFails_ListeningConcludesWithoutProcessingBufferedDeliveries() {
+	this.readError = io.EOF
+	this.handlePanic = "boink"
+	this.rejectError = errors.New("")
+	this.subscription.batchCapacity = 1
+	this.subscription.bufferCapacity = 2
+	this.subscription.failurePolicy = this.policy(FailureActionRequeue)
+	this.initializeWorker()
+	this.channelBuffer <- messaging.Delivery{Message: 1}
+	this.channelBuffer <- messaging.Delivery{Message: 2}
+
+	this.worker.Listen()
+
+	this.So(this.rejectCount, should.Equal, 1)
+	this.So(len(this.channelBuffer), should.Equal, 1)
+}
+
+func (this *WorkerFixture) policy(action FailureAction) FailurePolicy {
+	return func(recovered any, deliveries []messaging.Delivery) FailureAction {
+		this.policyRecovered = recovered
+		this.policyDeliveries = append(this.policyDeliveries, deliveries)
+		return action
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 func (this *WorkerFixture) Read(ctx context.Context, delivery *messaging.Delivery) error {
@@ -311,6 +425,13 @@ func (this *WorkerFixture) Acknowledge(ctx context.Context, deliveries ...messag
 	this.acknowledgeDeliveries = append(this.acknowledgeDeliveries, deliveries...)
 	return this.acknowledgeError
 }
+func (this *WorkerFixture) Reject(ctx context.Context, requeue bool, deliveries ...messaging.Delivery) error {
+	this.rejectCount++
+	this.rejectContext = ctx
+	this.rejectRequeue = requeue
+	this.rejectDeliveries = append(this.rejectDeliveries, deliveries...)
+	return this.rejectError
+}
 func (this *WorkerFixture) Close() error { panic("nop") }
 
 func (this *WorkerFixture) Handle(ctx context.Context, messages ...any) {
@@ -318,4 +439,8 @@ func (this *WorkerFixture) Handle(ctx context.Context, messages ...any) {
 	this.handleCount++
 	this.handleCtx = ctx
 	this.handleMessages = append(this.handleMessages, messages...)
+
+	if this.handlePanic != nil {
+		panic(this.handlePanic)
+	}
 }